@@ -13,6 +13,7 @@ package gdal
 import "C"
 import (
 	"fmt"
+	"runtime"
 	"unsafe"
 )
 
@@ -34,6 +35,7 @@ func GDALTranslate(
 	srcDS Dataset,
 	options []string,
 ) Dataset {
+	defer runtime.KeepAlive(srcDS.ref)
 
 	var err C.int
 
@@ -54,7 +56,7 @@ func GDALTranslate(
 		&err,
 	)
 
-	return Dataset{outputDs}
+	return ownedDataset(outputDs)
 
 }
 
@@ -64,6 +66,8 @@ func GDALWarp(
 	srcDs []Dataset,
 	options []string,
 ) Dataset {
+	defer runtime.KeepAlive(dstDs.ref)
+	defer runtime.KeepAlive(srcDs)
 
 	var err C.int
 
@@ -92,6 +96,6 @@ func GDALWarp(
 		&err,
 	)
 
-	return Dataset{outputDs}
+	return ownedDataset(outputDs)
 
 }
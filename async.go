@@ -0,0 +1,259 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+/* ==================================================================== */
+/*     GDALAsyncReader                                                  */
+/* ==================================================================== */
+
+// AsyncReader reads a window of a dataset progressively, as the data
+// becomes available, for instance from JPIP or progressive JPEG2000
+// sources. Drivers without native support deliver the whole window at
+// once.
+//
+// GDAL keeps writing into a buffer of its own while the read is running,
+// so each updated region is copied to the buffer passed to
+// BeginAsyncReader by GetNextUpdatedRegion. Hold LockBuffer while reading
+// that buffer if updates are fetched from another goroutine.
+type AsyncReader struct {
+	cval C.GDALAsyncReaderH
+	// dataset is kept so that a tracked dataset outlives the reader.
+	dataset Dataset
+
+	// ending keeps End from releasing the reader while
+	// GetNextUpdatedRegion uses it, and mu guards the buffer.
+	ending   sync.RWMutex
+	mu       sync.Mutex
+	window   Window
+	bands    int
+	elemSize int
+	buffer   interface{}
+	bufPtr   unsafe.Pointer
+	cbuf     unsafe.Pointer
+	bandMap  *C.int
+}
+
+// BeginAsyncReader starts reading win of the given bands (1-based, all
+// bands if empty) into buffer, a numeric slice of the types accepted by IO
+// holding the window of each band in turn. options are driver specific
+// (e.g. "LEVEL=n" for JPIP). End must be called once done.
+func (dataset Dataset) BeginAsyncReader(win Window, buffer interface{}, bands []int, options []string) (*AsyncReader, error) {
+	defer runtime.KeepAlive(dataset.ref)
+	if err := win.within(dataset.RasterXSize(), dataset.RasterYSize()); err != nil {
+		return nil, err
+	}
+	if len(bands) == 0 {
+		bands = make([]int, dataset.RasterCount())
+		for i := range bands {
+			bands[i] = i + 1
+		}
+	}
+	dataType, dataPtr, err := determineBufferType(buffer)
+	if err != nil {
+		return nil, err
+	}
+	if n, want := reflect.ValueOf(buffer).Len(), win.Len()*len(bands); n != want {
+		return nil, fmt.Errorf("buffer has %d elements, window %+v of %d bands needs %d", n, win, len(bands), want)
+	}
+
+	reader := &AsyncReader{
+		dataset:  dataset,
+		window:   win,
+		bands:    len(bands),
+		elemSize: dataType.Size() / 8,
+		buffer:   buffer,
+		bufPtr:   dataPtr,
+	}
+
+	// GDAL holds on to the buffer and band map until the reader is ended,
+	// so they can't live in Go memory.
+	reader.cbuf = C.VSICalloc(C.size_t(win.Len()*len(bands)), C.size_t(reader.elemSize))
+	if reader.cbuf == nil {
+		return nil, fmt.Errorf("cannot allocate %d bytes for the async reader", win.Len()*len(bands)*reader.elemSize)
+	}
+	reader.bandMap = (*C.int)(C.VSIMalloc(C.size_t(len(bands)) * C.size_t(unsafe.Sizeof(C.int(0)))))
+	bandMap := unsafe.Slice(reader.bandMap, len(bands))
+	for i, band := range bands {
+		bandMap[i] = C.int(band)
+	}
+
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	reader.cval = C.GDALBeginAsyncReader(
+		dataset.cval,
+		C.int(win.XOff), C.int(win.YOff), C.int(win.XSize), C.int(win.YSize),
+		reader.cbuf,
+		C.int(win.XSize), C.int(win.YSize),
+		C.GDALDataType(dataType),
+		C.int(len(bands)), reader.bandMap,
+		0, 0, 0,
+		(**C.char)(unsafe.Pointer(&opts[0])),
+	)
+	if reader.cval == nil {
+		reader.free()
+		return nil, failure("GDALBeginAsyncReader", "cannot start async reader")
+	}
+	return reader, nil
+}
+
+// GetNextUpdatedRegion waits up to timeout (forever if negative) for more
+// data, copies the updated region, given in buffer coordinates, to the
+// buffer and returns it with the status of the read. Reading is done once
+// AR_Complete or AR_Error is returned.
+func (reader *AsyncReader) GetNextUpdatedRegion(timeout time.Duration) (AsyncStatusType, Window, error) {
+	reader.ending.RLock()
+	defer reader.ending.RUnlock()
+	defer runtime.KeepAlive(reader.dataset.ref)
+	if reader.cval == nil {
+		return AR_Error, Window{}, fmt.Errorf("async reader has ended")
+	}
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	var xOff, yOff, xSize, ySize C.int
+	status := AsyncStatusType(C.GDALARGetNextUpdatedRegion(
+		reader.cval, seconds(timeout), &xOff, &yOff, &xSize, &ySize,
+	))
+	region := Window{int(xOff), int(yOff), int(xSize), int(ySize)}
+	if status == AR_Error {
+		return status, region, failure("GDALARGetNextUpdatedRegion", "async read failed")
+	}
+	if region.XSize > 0 && region.YSize > 0 {
+		if !reader.LockBuffer(-1) {
+			return AR_Error, region, failure("GDALARLockBuffer", "cannot lock the async reader buffer")
+		}
+		reader.copyRegion(region)
+		reader.UnlockBuffer()
+	}
+	return status, region, nil
+}
+
+// copyRegion copies region from GDAL's buffer to the Go buffer.
+func (reader *AsyncReader) copyRegion(region Window) {
+	size := reader.window.Len() * reader.bands * reader.elemSize
+	src := unsafe.Slice((*byte)(reader.cbuf), size)
+	dst := unsafe.Slice((*byte)(reader.bufPtr), size)
+	rowBytes := region.XSize * reader.elemSize
+	for band := 0; band < reader.bands; band++ {
+		for y := region.YOff; y < region.YOff+region.YSize; y++ {
+			start := ((band*reader.window.YSize+y)*reader.window.XSize + region.XOff) * reader.elemSize
+			copy(dst[start:start+rowBytes], src[start:start+rowBytes])
+		}
+	}
+}
+
+// LockBuffer keeps GDAL and GetNextUpdatedRegion from updating the buffer
+// until UnlockBuffer is called. It waits up to timeout (forever if
+// negative) and reports whether the lock was acquired. It still guards the
+// buffer after End.
+func (reader *AsyncReader) LockBuffer(timeout time.Duration) bool {
+	reader.mu.Lock()
+	if reader.cval != nil && C.GDALARLockBuffer(reader.cval, seconds(timeout)) == 0 {
+		reader.mu.Unlock()
+		return false
+	}
+	return true
+}
+
+// UnlockBuffer releases a lock taken by LockBuffer.
+func (reader *AsyncReader) UnlockBuffer() {
+	if reader.cval != nil {
+		C.GDALARUnlockBuffer(reader.cval)
+	}
+	reader.mu.Unlock()
+}
+
+// End stops the read and releases the reader, waiting for a concurrent
+// GetNextUpdatedRegion to return first. Calling it again is a no-op.
+func (reader *AsyncReader) End() {
+	reader.ending.Lock()
+	defer reader.ending.Unlock()
+	defer runtime.KeepAlive(reader.dataset.ref)
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+	if reader.cval == nil {
+		return
+	}
+	C.GDALEndAsyncReader(reader.dataset.cval, reader.cval)
+	reader.cval = nil
+	reader.free()
+}
+
+func (reader *AsyncReader) free() {
+	C.VSIFree(reader.cbuf)
+	C.VSIFree(unsafe.Pointer(reader.bandMap))
+	reader.cbuf, reader.bandMap = nil, nil
+}
+
+// AsyncUpdate is a region of the buffer updated by an AsyncReader.
+type AsyncUpdate struct {
+	Status AsyncStatusType
+	Region Window
+	Err    error
+}
+
+// Updates fetches updates in a new goroutine, waiting up to timeout for
+// each, and sends the non-empty ones on the returned channel. The channel
+// is closed, and the reader ended, once the read is complete, failed
+// (the last update then carries the error), or ctx is done. Receivers must
+// hold LockBuffer while reading the buffer.
+func (reader *AsyncReader) Updates(ctx context.Context, timeout time.Duration) <-chan AsyncUpdate {
+	updates := make(chan AsyncUpdate)
+	go func() {
+		defer close(updates)
+		defer reader.End()
+		for ctx.Err() == nil {
+			status, region, err := reader.GetNextUpdatedRegion(timeout)
+			done := err != nil || status == AR_Complete || status == AR_Error
+			if err != nil || region.Len() > 0 {
+				select {
+				case updates <- AsyncUpdate{status, region, err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if done {
+				return
+			}
+		}
+	}()
+	return updates
+}
+
+// seconds converts a timeout to the seconds GDAL expects, -1 meaning
+// forever.
+func seconds(timeout time.Duration) C.double {
+	if timeout < 0 {
+		return -1
+	}
+	return C.double(timeout.Seconds())
+}
@@ -0,0 +1,68 @@
+package gdal
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAsyncReader(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("", 3, 2, 1, Byte, nil)
+	defer ds.Close()
+	if err := WriteWindow(ds.RasterBand(1), Window{XSize: 3, YSize: 2}, []uint8{1, 2, 3, 4, 5, 6}); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := make([]uint16, 4)
+	reader, err := ds.BeginAsyncReader(Window{XOff: 1, XSize: 2, YSize: 2}, buffer, nil, nil)
+	if err != nil {
+		t.Fatalf("BeginAsyncReader: %v", err)
+	}
+	var last AsyncUpdate
+	for update := range reader.Updates(context.Background(), time.Second) {
+		if update.Err != nil {
+			t.Fatalf("update: %v", update.Err)
+		}
+		last = update
+	}
+	if last.Status != AR_Complete {
+		t.Errorf("got final status %s, want %s", last.Status.Name(), AR_Complete.Name())
+	}
+	reader.LockBuffer(-1)
+	defer reader.UnlockBuffer()
+	if want := []uint16{2, 3, 5, 6}; !reflect.DeepEqual(buffer, want) {
+		t.Errorf("got %v, want %v", buffer, want)
+	}
+}
+
+func TestAsyncReaderConcurrentEnd(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("", 2, 2, 1, Byte, nil)
+	defer ds.Close()
+	reader, err := ds.BeginAsyncReader(Window{XSize: 2, YSize: 2}, make([]uint8, 4), nil, nil)
+	if err != nil {
+		t.Fatalf("BeginAsyncReader: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if _, _, err := reader.GetNextUpdatedRegion(0); err != nil {
+				return
+			}
+		}
+	}()
+	reader.End()
+	<-done
+	if _, _, err := reader.GetNextUpdatedRegion(0); err == nil {
+		t.Error("GetNextUpdatedRegion after End: got no error")
+	}
+}
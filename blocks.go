@@ -0,0 +1,192 @@
+package gdal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Block iteration                                                 */
+/* -------------------------------------------------------------------- */
+
+// Block is one block of a band's natural block grid.
+type Block struct {
+	// XBlock and YBlock are the column and row of the block in the grid.
+	XBlock, YBlock int
+	// Window is the part of the raster covered by the block. Blocks on the
+	// right and bottom edges are clipped to the raster size.
+	Window
+}
+
+// BlockIterator walks the blocks of a band in row major order.
+//
+//	it := band.Blocks()
+//	for it.Next() {
+//		data, err := gdal.ReadBlockData[float32](band, it.Block(), data)
+//		...
+//	}
+type BlockIterator struct {
+	xSize, ySize           int
+	blockXSize, blockYSize int
+	xBlocks, yBlocks       int
+	next                   int
+	block                  Block
+}
+
+// Blocks returns an iterator over the blocks of the band.
+func (rasterBand RasterBand) Blocks() *BlockIterator {
+	it := &BlockIterator{xSize: rasterBand.XSize(), ySize: rasterBand.YSize()}
+	it.blockXSize, it.blockYSize = rasterBand.BlockSize()
+	if it.blockXSize > 0 && it.blockYSize > 0 {
+		it.xBlocks = (it.xSize + it.blockXSize - 1) / it.blockXSize
+		it.yBlocks = (it.ySize + it.blockYSize - 1) / it.blockYSize
+	}
+	return it
+}
+
+// Len returns the total number of blocks.
+func (it *BlockIterator) Len() int {
+	return it.xBlocks * it.yBlocks
+}
+
+// Next advances to the next block, and reports whether there is one.
+func (it *BlockIterator) Next() bool {
+	if it.next >= it.Len() {
+		return false
+	}
+	it.block = it.at(it.next)
+	it.next++
+	return true
+}
+
+// Block returns the current block.
+func (it *BlockIterator) Block() Block {
+	return it.block
+}
+
+func (it *BlockIterator) at(i int) Block {
+	block := Block{XBlock: i % it.xBlocks, YBlock: i / it.xBlocks}
+	block.XOff = block.XBlock * it.blockXSize
+	block.YOff = block.YBlock * it.blockYSize
+	block.XSize, block.YSize = it.blockXSize, it.blockYSize
+	if block.XOff+block.XSize > it.xSize {
+		block.XSize = it.xSize - block.XOff
+	}
+	if block.YOff+block.YSize > it.ySize {
+		block.YSize = it.ySize - block.YOff
+	}
+	return block
+}
+
+// ReadBlockData reads the valid pixels of block as T, reusing buf when it
+// is large enough, so a single buffer can serve a whole iteration.
+func ReadBlockData[T Numeric](band RasterBand, block Block, buf []T) ([]T, error) {
+	if cap(buf) < block.Len() {
+		buf = make([]T, block.Len())
+	}
+	buf = buf[:block.Len()]
+	return buf, ReadWindowInto(band, block.Window, buf)
+}
+
+// ProcessBlocks streams the blocks of src[0] through fn and writes the
+// results to the same window of dst. fn receives the block's pixels of
+// every band of src, which must all have the same size, and returns the
+// block's output pixels.
+//
+// Up to workers calls of fn run in parallel, and about twice that many
+// blocks are held in memory. Reads and writes are serialized, since GDAL
+// datasets must not be used concurrently. The first error, or ctx being
+// done, stops the processing and is returned.
+func ProcessBlocks[T, U Numeric](
+	ctx context.Context,
+	src []RasterBand,
+	dst RasterBand,
+	workers int,
+	fn func(block Block, in [][]T) ([]U, error),
+) error {
+	if len(src) == 0 {
+		return fmt.Errorf("no source bands")
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	for _, band := range append([]RasterBand{dst}, src[1:]...) {
+		if band.XSize() != src[0].XSize() || band.YSize() != src[0].YSize() {
+			return fmt.Errorf("bands are %dx%d and %dx%d", src[0].XSize(), src[0].YSize(), band.XSize(), band.YSize())
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		ioMutex  sync.Mutex
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	type job struct {
+		block Block
+		in    [][]T
+	}
+	jobs := make(chan job, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				out, err := fn(job.block, job.in)
+				if err == nil && len(out) != job.block.Len() {
+					err = fmt.Errorf("block %d,%d: got %d output pixels, want %d", job.block.XBlock, job.block.YBlock, len(out), job.block.Len())
+				}
+				if err == nil {
+					ioMutex.Lock()
+					err = WriteWindow(dst, job.block.Window, out)
+					ioMutex.Unlock()
+				}
+				if err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	it := src[0].Blocks()
+	for it.Next() && ctx.Err() == nil {
+		block := it.Block()
+		in := make([][]T, len(src))
+		var err error
+		ioMutex.Lock()
+		for i, band := range src {
+			if in[i], err = ReadBlockData[T](band, block, nil); err != nil {
+				break
+			}
+		}
+		ioMutex.Unlock()
+		if err != nil {
+			fail(err)
+			break
+		}
+		select {
+		case jobs <- job{block, in}:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
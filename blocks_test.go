@@ -0,0 +1,54 @@
+package gdal
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBlockIterator(t *testing.T) {
+	it := &BlockIterator{xSize: 5, ySize: 3, blockXSize: 2, blockYSize: 2, xBlocks: 3, yBlocks: 2}
+	var blocks []Block
+	for it.Next() {
+		blocks = append(blocks, it.Block())
+	}
+	if len(blocks) != 6 {
+		t.Fatalf("got %d blocks, want 6", len(blocks))
+	}
+	last := Block{XBlock: 2, YBlock: 1, Window: Window{XOff: 4, YOff: 2, XSize: 1, YSize: 1}}
+	if blocks[5] != last {
+		t.Errorf("got last block %+v, want %+v", blocks[5], last)
+	}
+}
+
+func TestProcessBlocks(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("", 5, 3, 2, Float32, nil)
+	defer ds.Close()
+	src, dst := ds.RasterBand(1), ds.RasterBand(2)
+	if err := WriteWindow(src, Window{XSize: 5, YSize: 3}, []float32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14}); err != nil {
+		t.Fatal(err)
+	}
+
+	err = ProcessBlocks(context.Background(), []RasterBand{src}, dst, 4, func(block Block, in [][]float32) ([]float64, error) {
+		out := make([]float64, len(in[0]))
+		for i, v := range in[0] {
+			out[i] = float64(v) * 2
+		}
+		return out, nil
+	})
+	if err != nil {
+		t.Fatalf("ProcessBlocks: %v", err)
+	}
+	got, err := ReadWindow[int32](dst, Window{XSize: 5, YSize: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range got {
+		if v != int32(2*i) {
+			t.Fatalf("pixel %d: got %d, want %d", i, v, 2*i)
+		}
+	}
+}
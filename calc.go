@@ -0,0 +1,578 @@
+package gdal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Raster calculator                                               */
+/* -------------------------------------------------------------------- */
+
+// CalcOptions configure Calc.
+type CalcOptions struct {
+	// Band is the band of the output dataset to write, 1 if 0.
+	Band int
+	// NoData is the nodata value of the output band. If nil, the band's
+	// nodata value is used, or else the gdal_calc default for its type,
+	// such as 255 for Byte or -32768 for Int16, which is then set on the
+	// band.
+	NoData *float64
+	// Scale and Offset, if Scale is not 0, store results as
+	// (result - Offset) / Scale, which is then rounded and clamped to
+	// the output type, and are set as the scale and offset of the band.
+	Scale, Offset float64
+	// IgnoreMasks only considers nodata values and NaN invalid, not the
+	// pixels masked out by the mask bands of the inputs.
+	IgnoreMasks bool
+	// Workers is the number of blocks evaluated in parallel,
+	// runtime.NumCPU() if 0.
+	Workers int
+	// Context, if not nil, cancels the computation once done.
+	Context context.Context
+}
+
+// Calc evaluates expr for each pixel and writes the results to a band of
+// out, like gdal_calc.py. inputs maps the variable names of expr to bands,
+// which must have the size of the output band. Results are converted to
+// the data type of the output band, rounded and clamped to its range.
+//
+// Expressions combine numbers and variables with the arithmetic operators
+// + - * / % and ^ (power), the comparisons == != < <= > >=, the logical
+// operators && || and !, which yield 1 or 0, and the functions where(cond,
+// a, b), abs, sqrt, exp, log, log10, sin, cos, tan, asin, acos, atan,
+// atan2, floor, ceil, round, pow, min and max, e.g. "(B4-B3)/(B4+B3)" or
+// "where(A>100, 1, 0)".
+//
+// A pixel is nodata in the output if it is nodata, NaN or masked out in
+// any band used by expr, or if expr evaluates to NaN or an infinity.
+// Blocks are evaluated in parallel, see ProcessBlocks.
+func Calc(expr string, inputs map[string]RasterBand, out Dataset, opts CalcOptions) error {
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	root, used, err := parseCalc(expr, names)
+	if err != nil {
+		return err
+	}
+	if opts.Band == 0 {
+		opts.Band = 1
+	}
+	if opts.Band < 1 || opts.Band > out.RasterCount() {
+		return fmt.Errorf("output band %d out of range [1, %d]", opts.Band, out.RasterCount())
+	}
+	dst := out.RasterBand(opts.Band)
+
+	// The sources are the used input bands, followed by the mask bands
+	// of those which need them.
+	type input struct {
+		noData    float64
+		hasNoData bool
+		mask      int
+	}
+	var src []RasterBand
+	var ins []input
+	for _, name := range used {
+		band := inputs[name]
+		in := input{mask: -1}
+		in.noData, in.hasNoData = band.NoDataValue()
+		src = append(src, band)
+		ins = append(ins, in)
+	}
+	if !opts.IgnoreMasks {
+		for i, band := range src[:len(used)] {
+			if band.GetMaskFlags()&(GMF_AllValid|GMF_NoData) == 0 {
+				ins[i].mask = len(src)
+				src = append(src, band.GetMaskBand())
+			}
+		}
+	}
+	if len(src) == 0 {
+		// A constant expression: sources only drive the block iteration.
+		src = append(src, dst)
+	}
+
+	noData, err := calcNoData(dst, opts.NoData)
+	if err != nil {
+		return err
+	}
+	if opts.Scale != 0 {
+		if err := dst.SetScale(opts.Scale); err != nil {
+			return err
+		}
+		if err := dst.SetOffset(opts.Offset); err != nil {
+			return err
+		}
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	workers := opts.Workers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+	return ProcessBlocks(ctx, src, dst, workers, func(block Block, in [][]float64) ([]float64, error) {
+		n := block.Len()
+		result := root.eval(in[:len(used)], n)
+		out := make([]float64, n)
+		for p := 0; p < n; p++ {
+			v := result[p]
+			valid := !math.IsNaN(v) && !math.IsInf(v, 0)
+			for i, input := range ins {
+				x := in[i][p]
+				if math.IsNaN(x) || (input.hasNoData && x == input.noData) || (input.mask >= 0 && in[input.mask][p] == 0) {
+					valid = false
+					break
+				}
+			}
+			switch {
+			case !valid:
+				v = noData
+			case opts.Scale != 0:
+				v = (v - opts.Offset) / opts.Scale
+			}
+			out[p] = v
+		}
+		return out, nil
+	})
+}
+
+// calcNoData returns the nodata value of the output band, setting it if
+// needed.
+func calcNoData(band RasterBand, noData *float64) (float64, error) {
+	if noData == nil {
+		if value, ok := band.NoDataValue(); ok {
+			return value, nil
+		}
+		value, ok := map[DataType]float64{
+			Byte:    255,
+			Int8:    -128,
+			UInt16:  65535,
+			Int16:   -32768,
+			UInt32:  4294967293,
+			Int32:   -2147483647,
+			UInt64:  18446744073709551615,
+			Int64:   -9223372036854775808,
+			Float32: math.MaxFloat32,
+			Float64: math.MaxFloat64,
+		}[band.RasterDataType()]
+		if !ok {
+			return 0, fmt.Errorf("unsupported output type %s", band.RasterDataType().Name())
+		}
+		noData = &value
+	}
+	return *noData, band.SetNoDataValue(*noData)
+}
+
+// calcNode is a node of a parsed expression, evaluated over n pixels.
+// vars holds the pixels of the variables, which must not be modified.
+type calcNode interface {
+	eval(vars [][]float64, n int) []float64
+}
+
+type calcNumber float64
+
+func (node calcNumber) eval(vars [][]float64, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = float64(node)
+	}
+	return out
+}
+
+type calcVar int
+
+func (node calcVar) eval(vars [][]float64, n int) []float64 {
+	return vars[node]
+}
+
+type calcUnary struct {
+	op func(float64) float64
+	x  calcNode
+}
+
+func (node calcUnary) eval(vars [][]float64, n int) []float64 {
+	x := node.x.eval(vars, n)
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = node.op(x[i])
+	}
+	return out
+}
+
+type calcBinary struct {
+	op   func(a, b float64) float64
+	x, y calcNode
+}
+
+func (node calcBinary) eval(vars [][]float64, n int) []float64 {
+	x, y := node.x.eval(vars, n), node.y.eval(vars, n)
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = node.op(x[i], y[i])
+	}
+	return out
+}
+
+type calcWhere struct {
+	cond, x, y calcNode
+}
+
+func (node calcWhere) eval(vars [][]float64, n int) []float64 {
+	cond, x, y := node.cond.eval(vars, n), node.x.eval(vars, n), node.y.eval(vars, n)
+	out := make([]float64, n)
+	for i := range out {
+		if cond[i] != 0 {
+			out[i] = x[i]
+		} else {
+			out[i] = y[i]
+		}
+	}
+	return out
+}
+
+func calcBool(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+var calcBinaryOps = map[string]func(a, b float64) float64{
+	"||": func(a, b float64) float64 { return calcBool(a != 0 || b != 0) },
+	"&&": func(a, b float64) float64 { return calcBool(a != 0 && b != 0) },
+	"==": func(a, b float64) float64 { return calcBool(a == b) },
+	"!=": func(a, b float64) float64 { return calcBool(a != b) },
+	"<":  func(a, b float64) float64 { return calcBool(a < b) },
+	"<=": func(a, b float64) float64 { return calcBool(a <= b) },
+	">":  func(a, b float64) float64 { return calcBool(a > b) },
+	">=": func(a, b float64) float64 { return calcBool(a >= b) },
+	"+":  func(a, b float64) float64 { return a + b },
+	"-":  func(a, b float64) float64 { return a - b },
+	"*":  func(a, b float64) float64 { return a * b },
+	"/":  func(a, b float64) float64 { return a / b },
+	"%":  math.Mod,
+	"^":  math.Pow,
+}
+
+// calcLevels lists the binary operators from the lowest precedence.
+var calcLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+var calcUnaryFuncs = map[string]func(float64) float64{
+	"abs":   math.Abs,
+	"sqrt":  math.Sqrt,
+	"exp":   math.Exp,
+	"log":   math.Log,
+	"log10": math.Log10,
+	"sin":   math.Sin,
+	"cos":   math.Cos,
+	"tan":   math.Tan,
+	"asin":  math.Asin,
+	"acos":  math.Acos,
+	"atan":  math.Atan,
+	"floor": math.Floor,
+	"ceil":  math.Ceil,
+	"round": math.Round,
+}
+
+var calcBinaryFuncs = map[string]func(a, b float64) float64{
+	"pow":   math.Pow,
+	"atan2": math.Atan2,
+	"min":   math.Min,
+	"max":   math.Max,
+}
+
+// calcParser is a recursive descent parser of Calc expressions.
+type calcParser struct {
+	tokens []string
+	pos    int
+	vars   map[string]calcVar
+	used   []string
+}
+
+// parseCalc parses expr, whose variables are among names. It returns the
+// names used by expr, in the order of the calcVar indexes.
+func parseCalc(expr string, names []string) (calcNode, []string, error) {
+	tokens, err := tokenizeCalc(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &calcParser{tokens: tokens, vars: map[string]calcVar{}}
+	known := map[string]bool{}
+	for _, name := range names {
+		known[name] = true
+	}
+	node, err := p.parseBinary(0, known)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, nil, fmt.Errorf("unexpected %q in expression", p.tokens[p.pos])
+	}
+	return node, p.used, nil
+}
+
+func tokenizeCalc(expr string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			j := i
+			for j < len(expr) && (unicode.IsDigit(rune(expr[j])) || expr[j] == '.') {
+				j++
+			}
+			if j < len(expr) && (expr[j] == 'e' || expr[j] == 'E') {
+				k := j + 1
+				if k < len(expr) && (expr[k] == '+' || expr[k] == '-') {
+					k++
+				}
+				if k < len(expr) && unicode.IsDigit(rune(expr[k])) {
+					for j = k; j < len(expr) && unicode.IsDigit(rune(expr[j])); j++ {
+					}
+				}
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(expr) && (unicode.IsLetter(rune(expr[j])) || unicode.IsDigit(rune(expr[j])) || expr[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		default:
+			if i+1 < len(expr) {
+				switch two := expr[i : i+2]; two {
+				case "||", "&&", "==", "!=", "<=", ">=", "**":
+					if two == "**" {
+						two = "^"
+					}
+					tokens = append(tokens, two)
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("+-*/%^()<>!,", c) {
+				return nil, fmt.Errorf("unexpected character %q in expression", c)
+			}
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func (p *calcParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *calcParser) expect(token string) error {
+	if p.peek() != token {
+		if p.peek() == "" {
+			return fmt.Errorf("expected %q at end of expression", token)
+		}
+		return fmt.Errorf("expected %q, got %q", token, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+// parseBinary parses the operators of calcLevels[level] and above.
+func (p *calcParser) parseBinary(level int, known map[string]bool) (calcNode, error) {
+	if level == len(calcLevels) {
+		return p.parseUnary(known)
+	}
+	x, err := p.parseBinary(level+1, known)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		found := false
+		for _, candidate := range calcLevels[level] {
+			found = found || op == candidate
+		}
+		if !found {
+			return x, nil
+		}
+		p.pos++
+		y, err := p.parseBinary(level+1, known)
+		if err != nil {
+			return nil, err
+		}
+		x = calcBinary{calcBinaryOps[op], x, y}
+	}
+}
+
+func (p *calcParser) parseUnary(known map[string]bool) (calcNode, error) {
+	switch p.peek() {
+	case "-":
+		p.pos++
+		x, err := p.parseUnary(known)
+		return calcUnary{func(v float64) float64 { return -v }, x}, err
+	case "+":
+		p.pos++
+		return p.parseUnary(known)
+	case "!":
+		p.pos++
+		x, err := p.parseUnary(known)
+		return calcUnary{func(v float64) float64 { return calcBool(v == 0) }, x}, err
+	}
+	return p.parsePower(known)
+}
+
+// parsePower parses right associative powers, which bind tighter than
+// unary operators on their left: -2^2 is -4.
+func (p *calcParser) parsePower(known map[string]bool) (calcNode, error) {
+	x, err := p.parsePrimary(known)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != "^" {
+		return x, nil
+	}
+	p.pos++
+	y, err := p.parseUnary(known)
+	if err != nil {
+		return nil, err
+	}
+	return calcBinary{math.Pow, x, y}, nil
+}
+
+func (p *calcParser) parsePrimary(known map[string]bool) (calcNode, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case token == "(":
+		p.pos++
+		x, err := p.parseBinary(0, known)
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+	case unicode.IsDigit(rune(token[0])) || token[0] == '.':
+		p.pos++
+		v, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", token)
+		}
+		return calcNumber(v), nil
+	case unicode.IsLetter(rune(token[0])) || token[0] == '_':
+		p.pos++
+		if p.peek() == "(" {
+			return p.parseCall(token, known)
+		}
+		if !known[token] {
+			return nil, fmt.Errorf("unknown variable %q", token)
+		}
+		v, ok := p.vars[token]
+		if !ok {
+			v = calcVar(len(p.used))
+			p.vars[token] = v
+			p.used = append(p.used, token)
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("unexpected %q in expression", token)
+}
+
+func (p *calcParser) parseCall(name string, known map[string]bool) (calcNode, error) {
+	p.pos++
+	var args []calcNode
+	for p.peek() != ")" {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseBinary(0, known)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.pos++
+
+	arity := func(n int) error {
+		if len(args) != n {
+			return fmt.Errorf("%s takes %d arguments, got %d", name, n, len(args))
+		}
+		return nil
+	}
+	if fn, ok := calcUnaryFuncs[name]; ok {
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		return calcUnary{fn, args[0]}, nil
+	}
+	if fn, ok := calcBinaryFuncs[name]; ok {
+		if name == "min" || name == "max" {
+			if len(args) < 2 {
+				return nil, fmt.Errorf("%s takes at least 2 arguments, got %d", name, len(args))
+			}
+			x := args[0]
+			for _, y := range args[1:] {
+				x = calcBinary{fn, x, y}
+			}
+			return x, nil
+		}
+		if err := arity(2); err != nil {
+			return nil, err
+		}
+		return calcBinary{fn, args[0], args[1]}, nil
+	}
+	if name == "where" {
+		if err := arity(3); err != nil {
+			return nil, err
+		}
+		return calcWhere{args[0], args[1], args[2]}, nil
+	}
+	return nil, fmt.Errorf("unknown function %q", name)
+}
+
+// CreateLike creates a dataset with the size, geotransform and projection
+// of like, with bandCount bands of dataType, for instance to hold the
+// output of Calc.
+func (driver Driver) CreateLike(filename string, like Dataset, bandCount int, dataType DataType, options []string) (Dataset, error) {
+	dataset := driver.Create(filename, like.RasterXSize(), like.RasterYSize(), bandCount, dataType, options)
+	if dataset.cval == nil {
+		return Dataset{}, fmt.Errorf("cannot create '%s' with %s", filename, driver.ShortName())
+	}
+	if gt := like.GeoTransform(); gt != (GeoTransform{0, 1, 0, 0, 0, 1}) {
+		if err := dataset.SetGeoTransform(gt); err != nil {
+			dataset.Close()
+			return Dataset{}, err
+		}
+	}
+	if projection := like.Projection(); projection != "" {
+		if err := dataset.SetProjection(projection); err != nil {
+			dataset.Close()
+			return Dataset{}, err
+		}
+	}
+	return dataset, nil
+}
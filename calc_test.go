@@ -0,0 +1,125 @@
+package gdal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseCalc(t *testing.T) {
+	vars := [][]float64{{2, 3}, {10, 0}}
+	for _, test := range []struct {
+		expr string
+		want []float64
+	}{
+		{"A + B * 2", []float64{22, 3}},
+		{"(A + B) * 2", []float64{24, 6}},
+		{"-A^2", []float64{-4, -9}},
+		{"2^3^2", []float64{512, 512}},
+		{"A ** 2", []float64{4, 9}},
+		{"B / A", []float64{5, 0}},
+		{"B % 3", []float64{1, 0}},
+		{"1.5e1 - .5", []float64{14.5, 14.5}},
+		{"A > 2 || B == 10", []float64{1, 1}},
+		{"A > 2 && B == 10", []float64{0, 0}},
+		{"!(A <= 2)", []float64{0, 1}},
+		{"A != 3", []float64{1, 0}},
+		{"where(B > 5, A, -1)", []float64{2, -1}},
+		{"min(A, B, 2.5)", []float64{2, 0}},
+		{"max(A, B)", []float64{10, 3}},
+		{"abs(A - B)", []float64{8, 3}},
+		{"pow(A, 2) + sqrt(4)", []float64{6, 11}},
+		{"round(B / 3)", []float64{3, 0}},
+	} {
+		node, used, err := parseCalc(test.expr, []string{"A", "B"})
+		if err != nil {
+			t.Errorf("%s: %v", test.expr, err)
+			continue
+		}
+		in := make([][]float64, len(used))
+		for i, name := range used {
+			in[i] = vars[name[0]-'A']
+		}
+		got := node.eval(in, 2)
+		for i := range got {
+			if math.Abs(got[i]-test.want[i]) > 1e-9 {
+				t.Errorf("%s = %v, want %v", test.expr, got, test.want)
+				break
+			}
+		}
+	}
+
+	for _, expr := range []string{"", "A +", "(A", "A B", "C", "foo(A)", "where(A, 1)", "min(A)", "A $ B", "sqrt(A, B)"} {
+		if _, _, err := parseCalc(expr, []string{"A", "B"}); err == nil {
+			t.Errorf("%q parsed", expr)
+		}
+	}
+	if _, used, _ := parseCalc("B + B * 2", []string{"A", "B"}); len(used) != 1 || used[0] != "B" {
+		t.Errorf("used = %v, want [B]", used)
+	}
+}
+
+func TestCalc(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := driver.Create("", 4, 2, 2, Int16, nil)
+	defer src.Close()
+	win := Window{XSize: 4, YSize: 2}
+	red, nir := src.RasterBand(1), src.RasterBand(2)
+	if err := red.SetNoDataValue(-1); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteWindow(red, win, []int16{10, 20, 30, -1, 0, 50, 60, 70}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteWindow(nir, win, []int16{30, 20, 90, 40, 0, 150, 60, 30}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := driver.CreateLike("", src, 1, Int16, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	opts := CalcOptions{Scale: 0.001, Workers: 2}
+	inputs := map[string]RasterBand{"B3": red, "B4": nir}
+	if err := Calc("(B4-B3)/(B4+B3)", inputs, out, opts); err != nil {
+		t.Fatalf("Calc: %v", err)
+	}
+	got, err := ReadWindow[int16](out.RasterBand(1), win)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// -1 is red's nodata, 0/0 is NaN: both yield the Int16 default nodata.
+	want := []int16{500, 0, 500, -32768, -32768, 500, 0, -400}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("NDVI = %v, want %v", got, want)
+		}
+	}
+	if noData, ok := out.RasterBand(1).NoDataValue(); !ok || noData != -32768 {
+		t.Errorf("output nodata = %v, %v", noData, ok)
+	}
+	if scale, _ := out.RasterBand(1).GetScale(); scale != 0.001 {
+		t.Errorf("output scale = %v", scale)
+	}
+
+	noData := 0.0
+	if err := Calc("where(B4 > 50, 1, 2)", inputs, out, CalcOptions{NoData: &noData}); err != nil {
+		t.Fatalf("Calc: %v", err)
+	}
+	if got, err = ReadWindow[int16](out.RasterBand(1), win); err != nil {
+		t.Fatal(err)
+	}
+	want = []int16{2, 2, 1, 2, 2, 1, 1, 2}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("where = %v, want %v", got, want)
+		}
+	}
+
+	if err := Calc("B5 + 1", inputs, out, CalcOptions{}); err == nil {
+		t.Error("Calc with an unknown variable succeeded")
+	}
+}
@@ -0,0 +1,252 @@
+package gdal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Driver option lists                                             */
+/* -------------------------------------------------------------------- */
+
+// OptionType is the type of the value of a driver option.
+type OptionType string
+
+const (
+	OptionInt          = OptionType("int")
+	OptionUnsignedInt  = OptionType("unsigned int")
+	OptionFloat        = OptionType("float")
+	OptionBoolean      = OptionType("boolean")
+	OptionString       = OptionType("string")
+	OptionStringSelect = OptionType("string-select")
+)
+
+// OptionDescriptor describes a creation, open or layer creation option
+// accepted by a driver.
+type OptionDescriptor struct {
+	Name        string
+	Type        OptionType
+	Description string
+	// Default is the value used when the option is not set, if known.
+	Default string
+	// Values lists the accepted values of a string-select option.
+	Values []string
+	// Aliases maps alternative spellings of values to the values.
+	Aliases map[string]string
+	// Min and Max bound the value of numeric options, when not empty.
+	Min, Max string
+	// MaxSize is the maximum length of string options, 0 if unlimited.
+	MaxSize int
+	// Scope is "raster", "vector" or "raster,vector" for the drivers
+	// handling both, and empty when the option applies to all datasets.
+	Scope string
+}
+
+// optionListXML is the DMD_CREATIONOPTIONLIST, DMD_OPENOPTIONLIST and
+// DS_LAYER_CREATIONOPTIONLIST document, whose root element name differs.
+type optionListXML struct {
+	Options []struct {
+		Name        string `xml:"name,attr"`
+		Type        string `xml:"type,attr"`
+		Description string `xml:"description,attr"`
+		Default     string `xml:"default,attr"`
+		Min         string `xml:"min,attr"`
+		Max         string `xml:"max,attr"`
+		MaxSize     string `xml:"maxsize,attr"`
+		Scope       string `xml:"scope,attr"`
+		Values      []struct {
+			Alias string `xml:"alias,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"Value"`
+	} `xml:"Option"`
+}
+
+// parseOptionList parses a driver option list document.
+func parseOptionList(doc string) ([]OptionDescriptor, error) {
+	if strings.TrimSpace(doc) == "" {
+		return nil, nil
+	}
+	var list optionListXML
+	if err := xml.Unmarshal([]byte(doc), &list); err != nil {
+		return nil, fmt.Errorf("invalid option list: %w", err)
+	}
+	options := make([]OptionDescriptor, 0, len(list.Options))
+	for _, o := range list.Options {
+		option := OptionDescriptor{
+			Name:        o.Name,
+			Type:        OptionType(strings.ToLower(o.Type)),
+			Description: o.Description,
+			Default:     o.Default,
+			Min:         o.Min,
+			Max:         o.Max,
+			Scope:       o.Scope,
+		}
+		if option.Type == "integer" {
+			option.Type = OptionInt
+		}
+		if o.MaxSize != "" {
+			option.MaxSize, _ = strconv.Atoi(o.MaxSize)
+		}
+		for _, v := range o.Values {
+			value := strings.TrimSpace(v.Value)
+			option.Values = append(option.Values, value)
+			if v.Alias != "" {
+				if option.Aliases == nil {
+					option.Aliases = map[string]string{}
+				}
+				option.Aliases[v.Alias] = value
+			}
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+// CreationOptions returns the options accepted by Create and CreateCopy,
+// from the DMD_CREATIONOPTIONLIST metadata item of the driver.
+func (driver Driver) CreationOptions() ([]OptionDescriptor, error) {
+	return parseOptionList(driver.MetadataItem(DMD_CREATIONOPTIONLIST, ""))
+}
+
+// OpenOptions returns the options accepted by OpenEx, from the
+// DMD_OPENOPTIONLIST metadata item of the driver.
+func (driver Driver) OpenOptions() ([]OptionDescriptor, error) {
+	return parseOptionList(driver.MetadataItem(DMD_OPENOPTIONLIST, ""))
+}
+
+// LayerCreationOptions returns the options accepted by
+// Dataset.CreateLayer, from the DS_LAYER_CREATIONOPTIONLIST metadata item
+// of the driver.
+func (driver Driver) LayerCreationOptions() ([]OptionDescriptor, error) {
+	return parseOptionList(driver.MetadataItem(DS_LAYER_CREATIONOPTIONLIST, ""))
+}
+
+// OptionsError lists the problems ValidateCreationOptions and its
+// siblings found in a list of options.
+type OptionsError struct {
+	// Driver is the short name of the driver, Kind "creation", "open" or
+	// "layer creation".
+	Driver, Kind string
+	// Problems holds one message per bad option.
+	Problems []string
+}
+
+func (err *OptionsError) Error() string {
+	return fmt.Sprintf("invalid %s options for %s: %s", err.Kind, err.Driver, strings.Join(err.Problems, "; "))
+}
+
+// ValidateCreationOptions checks KEY=VALUE options against the creation
+// options of the driver, returning an *OptionsError listing the unknown
+// keys and the values not matching their option's type, allowed values
+// or range. As with GDAL, any option is accepted by drivers publishing no
+// option list.
+func (driver Driver) ValidateCreationOptions(options []string) error {
+	descriptors, err := driver.CreationOptions()
+	if err != nil {
+		return err
+	}
+	return validateOptions(driver.ShortName(), "creation", descriptors, options)
+}
+
+// ValidateOpenOptions checks KEY=VALUE options against the open options
+// of the driver, like ValidateCreationOptions.
+func (driver Driver) ValidateOpenOptions(options []string) error {
+	descriptors, err := driver.OpenOptions()
+	if err != nil {
+		return err
+	}
+	return validateOptions(driver.ShortName(), "open", descriptors, options)
+}
+
+// ValidateLayerCreationOptions checks KEY=VALUE options against the layer
+// creation options of the driver, like ValidateCreationOptions.
+func (driver Driver) ValidateLayerCreationOptions(options []string) error {
+	descriptors, err := driver.LayerCreationOptions()
+	if err != nil {
+		return err
+	}
+	return validateOptions(driver.ShortName(), "layer creation", descriptors, options)
+}
+
+func validateOptions(driver, kind string, descriptors []OptionDescriptor, options []string) error {
+	if len(descriptors) == 0 {
+		return nil
+	}
+	byName := make(map[string]*OptionDescriptor, len(descriptors))
+	for i := range descriptors {
+		byName[strings.ToUpper(descriptors[i].Name)] = &descriptors[i]
+	}
+	var problems []string
+	for _, option := range options {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			problems = append(problems, fmt.Sprintf("%q is not KEY=VALUE", option))
+			continue
+		}
+		descriptor, ok := byName[strings.ToUpper(kv[0])]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown option %s", kv[0]))
+			continue
+		}
+		if err := descriptor.check(kv[1]); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", kv[0], err))
+		}
+	}
+	if len(problems) > 0 {
+		return &OptionsError{Driver: driver, Kind: kind, Problems: problems}
+	}
+	return nil
+}
+
+// check validates value against the option.
+func (option *OptionDescriptor) check(value string) error {
+	switch option.Type {
+	case OptionInt, OptionUnsignedInt:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || (option.Type == OptionUnsignedInt && n < 0) {
+			return fmt.Errorf("%q is not an %s", value, option.Type)
+		}
+		return option.checkRange(float64(n), value)
+	case OptionFloat:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a float", value)
+		}
+		return option.checkRange(f, value)
+	case OptionBoolean:
+		switch strings.ToUpper(value) {
+		case "YES", "NO", "ON", "OFF", "TRUE", "FALSE", "1", "0":
+			return nil
+		}
+		return fmt.Errorf("%q is not a boolean", value)
+	case OptionStringSelect:
+		for _, allowed := range option.Values {
+			if strings.EqualFold(value, allowed) {
+				return nil
+			}
+		}
+		for alias := range option.Aliases {
+			if strings.EqualFold(value, alias) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(option.Values, ", "))
+	case OptionString:
+		if option.MaxSize > 0 && len(value) > option.MaxSize {
+			return fmt.Errorf("%q is longer than %d characters", value, option.MaxSize)
+		}
+	}
+	return nil
+}
+
+func (option *OptionDescriptor) checkRange(v float64, value string) error {
+	if min, err := strconv.ParseFloat(option.Min, 64); err == nil && v < min {
+		return fmt.Errorf("%s is less than %s", value, option.Min)
+	}
+	if max, err := strconv.ParseFloat(option.Max, 64); err == nil && v > max {
+		return fmt.Errorf("%s is greater than %s", value, option.Max)
+	}
+	return nil
+}
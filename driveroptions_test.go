@@ -0,0 +1,103 @@
+package gdal
+
+import (
+	"errors"
+	"testing"
+)
+
+const testOptionList = `<CreationOptionList>
+   <Option name='COMPRESS' type='string-select' default='NONE' description='Compression method'>
+       <Value>NONE</Value>
+       <Value alias='ZIP'>DEFLATE</Value>
+   </Option>
+   <Option name='TILED' type='boolean' description='Switch to tiled format'/>
+   <Option name='BLOCKXSIZE' type='int' min='16' max='4096'/>
+   <Option name='ZLEVEL' type='float' min='1' max='9'/>
+   <Option name='COPYRIGHT' type='string' maxsize='8' scope='raster'/>
+</CreationOptionList>`
+
+func TestParseOptionList(t *testing.T) {
+	options, err := parseOptionList(testOptionList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(options) != 5 {
+		t.Fatalf("got %d options, want 5", len(options))
+	}
+	compress := options[0]
+	if compress.Name != "COMPRESS" || compress.Type != OptionStringSelect || compress.Default != "NONE" ||
+		compress.Description != "Compression method" || len(compress.Values) != 2 ||
+		compress.Values[1] != "DEFLATE" || compress.Aliases["ZIP"] != "DEFLATE" {
+		t.Errorf("COMPRESS = %+v", compress)
+	}
+	if options[2].Min != "16" || options[2].Max != "4096" {
+		t.Errorf("BLOCKXSIZE = %+v", options[2])
+	}
+	if options[4].MaxSize != 8 || options[4].Scope != "raster" {
+		t.Errorf("COPYRIGHT = %+v", options[4])
+	}
+	if options, err := parseOptionList(""); err != nil || options != nil {
+		t.Errorf("empty list: %v, %v", options, err)
+	}
+	if _, err := parseOptionList("<CreationOptionList>"); err == nil {
+		t.Error("malformed list parsed")
+	}
+
+	good := []string{"compress=zip", "TILED=YES", "BLOCKXSIZE=256", "ZLEVEL=6", "COPYRIGHT=me"}
+	if err := validateOptions("GTiff", "creation", options, good); err != nil {
+		t.Errorf("valid options: %v", err)
+	}
+	bad := []string{"COMPRES=DEFLATE", "COMPRESS=LZMA", "TILED=MAYBE", "BLOCKXSIZE=8", "BLOCKXSIZE=abc", "ZLEVEL=10", "COPYRIGHT=somebody else", "TILED"}
+	err = validateOptions("GTiff", "creation", options, bad)
+	var optionsErr *OptionsError
+	if !errors.As(err, &optionsErr) {
+		t.Fatalf("invalid options: got %v, want an *OptionsError", err)
+	}
+	if len(optionsErr.Problems) != len(bad) {
+		t.Errorf("got %d problems, want %d: %v", len(optionsErr.Problems), len(bad), err)
+	}
+}
+
+func TestDriverCreationOptions(t *testing.T) {
+	driver, err := GetDriverByName("GTiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options, err := driver.CreationOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, option := range options {
+		if option.Name == "COMPRESS" {
+			found = option.Type == OptionStringSelect && len(option.Values) > 0
+		}
+	}
+	if !found {
+		t.Errorf("no COMPRESS string-select option in %+v", options)
+	}
+	if err := driver.ValidateCreationOptions([]string{"COMPRESS=DEFLATE", "TILED=YES"}); err != nil {
+		t.Errorf("valid options: %v", err)
+	}
+	if err := driver.ValidateCreationOptions([]string{"COMPRES=DEFLATE"}); err == nil {
+		t.Error("misspelled option accepted")
+	}
+	if err := driver.ValidateOpenOptions([]string{"NUM_THREADS=ALL_CPUS"}); err != nil {
+		t.Errorf("valid open options: %v", err)
+	}
+	// GTiff has no layer creation option list, so anything goes.
+	if err := driver.ValidateLayerCreationOptions([]string{"ANY_OPTION=1"}); err != nil {
+		t.Errorf("options of a driver without option list: %v", err)
+	}
+
+	gpkg, err := GetDriverByName("GPKG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gpkg.ValidateLayerCreationOptions([]string{"GEOMETRY_NAME=geom", "SPATIAL_INDEX=NO"}); err != nil {
+		t.Errorf("valid layer creation options: %v", err)
+	}
+	if err := gpkg.ValidateLayerCreationOptions([]string{"SPATIAL_INDEX=PERHAPS"}); err == nil {
+		t.Error("invalid layer creation option accepted")
+	}
+}
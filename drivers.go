@@ -0,0 +1,141 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Driver catalog                                                  */
+/* -------------------------------------------------------------------- */
+
+// DriverInfo summarizes the capabilities and metadata of a driver.
+type DriverInfo struct {
+	Driver              Driver
+	ShortName, LongName string
+
+	// Raster, Vector, Multidim and GNM tell which kinds of data the
+	// driver handles.
+	Raster, Vector, Multidim, GNM bool
+	// Create and CreateCopy tell whether Driver.Create and
+	// Driver.CreateCopy are supported, VirtualIO whether the driver reads
+	// through the /vsi file systems.
+	Create, CreateCopy, VirtualIO bool
+
+	// Extensions lists the usual file extensions, lower case and without
+	// the leading dot.
+	Extensions []string
+	MIMEType   string
+	// HelpURL is the documentation page of the driver, if any.
+	HelpURL string
+}
+
+// Info returns the capabilities and metadata of the driver.
+func (driver Driver) Info() DriverInfo {
+	capability := func(name string) bool {
+		return strings.EqualFold(driver.MetadataItem(name, ""), "YES")
+	}
+	info := DriverInfo{
+		Driver:     driver,
+		ShortName:  driver.ShortName(),
+		LongName:   driver.LongName(),
+		Raster:     capability(DCAP_RASTER),
+		Vector:     capability(DCAP_VECTOR),
+		Multidim:   capability(DCAP_MULTIDIM_RASTER),
+		GNM:        capability(DCAP_GNM),
+		Create:     capability(DCAP_CREATE),
+		CreateCopy: capability(DCAP_CREATECOPY),
+		VirtualIO:  capability(DCAP_VIRTUALIO),
+		MIMEType:   driver.MetadataItem(DMD_MIMETYPE, ""),
+	}
+	extensions := driver.MetadataItem(DMD_EXTENSIONS, "")
+	if extensions == "" {
+		extensions = driver.MetadataItem(DMD_EXTENSION, "")
+	}
+	for _, ext := range strings.Fields(extensions) {
+		info.Extensions = append(info.Extensions, strings.ToLower(strings.TrimPrefix(ext, ".")))
+	}
+	if topic := driver.MetadataItem(DMD_HELPTOPIC, ""); topic != "" {
+		info.HelpURL = topic
+		if !strings.Contains(topic, "://") {
+			info.HelpURL = "https://gdal.org/" + strings.TrimPrefix(topic, "/")
+		}
+	}
+	return info
+}
+
+// Drivers returns the registered drivers, in registration order.
+func Drivers() []DriverInfo {
+	count := GetDriverCount()
+	drivers := make([]DriverInfo, 0, count)
+	for i := 0; i < count; i++ {
+		drivers = append(drivers, GetDriver(i).Info())
+	}
+	return drivers
+}
+
+// DriverForExtension returns the first registered driver listing the
+// file extension, given with or without its leading dot.
+func DriverForExtension(ext string) (Driver, error) {
+	ext = strings.ToLower(strings.TrimPrefix(ext, "."))
+	for _, info := range Drivers() {
+		for _, e := range info.Extensions {
+			if e == ext {
+				return info.Driver, nil
+			}
+		}
+	}
+	return Driver{}, fmt.Errorf("no driver for extension %q", ext)
+}
+
+var vsimemSeq uint64
+
+// vsimemName returns a /vsimem file name not used by other calls, ending
+// with suffix.
+func vsimemName(suffix string) string {
+	return fmt.Sprintf("/vsimem/go-gdal-%d%s", atomic.AddUint64(&vsimemSeq, 1), suffix)
+}
+
+// IdentifyDriverBytes identifies the driver of a file from its content,
+// or its first bytes, without a file name. Drivers recognizing files by
+// their extension only are not found this way.
+func IdentifyDriverBytes(data []byte) (Driver, error) {
+	if len(data) == 0 {
+		return Driver{}, fmt.Errorf("no data to identify")
+	}
+	// The buffer is copied since GDAL keeps it past this call.
+	buffer := C.CBytes(data)
+	defer C.free(buffer)
+	cName := C.CString(vsimemName(""))
+	defer C.free(unsafe.Pointer(cName))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	file := C.VSIFileFromMemBuffer(cName, (*C.GByte)(buffer), C.vsi_l_offset(len(data)), 0)
+	if file == nil {
+		return Driver{}, failure("VSIFileFromMemBuffer", "cannot create in-memory file")
+	}
+	C.VSIFCloseL(file)
+	defer C.VSIUnlink(cName)
+
+	driver := C.GDALIdentifyDriver(cName, nil)
+	if driver == nil {
+		return Driver{}, fmt.Errorf("no driver recognizes the data")
+	}
+	return Driver{driver}, nil
+}
@@ -0,0 +1,73 @@
+package gdal
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDrivers(t *testing.T) {
+	drivers := Drivers()
+	if len(drivers) != GetDriverCount() {
+		t.Fatalf("got %d drivers, want %d", len(drivers), GetDriverCount())
+	}
+	var gtiff, gpkg *DriverInfo
+	for i := range drivers {
+		switch drivers[i].ShortName {
+		case "GTiff":
+			gtiff = &drivers[i]
+		case "GPKG":
+			gpkg = &drivers[i]
+		}
+	}
+	if gtiff == nil || gpkg == nil {
+		t.Fatal("GTiff or GPKG driver missing")
+	}
+	if !gtiff.Raster || gtiff.Vector || !gtiff.Create || !gtiff.CreateCopy || !gtiff.VirtualIO {
+		t.Errorf("GTiff capabilities = %+v", gtiff)
+	}
+	if gtiff.MIMEType != "image/tiff" || gtiff.HelpURL == "" {
+		t.Errorf("GTiff metadata = %+v", gtiff)
+	}
+	found := false
+	for _, ext := range gtiff.Extensions {
+		found = found || ext == "tif"
+	}
+	if !found {
+		t.Errorf("GTiff extensions = %v", gtiff.Extensions)
+	}
+	if !gpkg.Raster || !gpkg.Vector {
+		t.Errorf("GPKG capabilities = %+v", gpkg)
+	}
+
+	for _, ext := range []string{".tif", "TIF", "tiff"} {
+		driver, err := DriverForExtension(ext)
+		if err != nil {
+			t.Errorf("DriverForExtension(%q): %v", ext, err)
+		} else if driver.ShortName() != "GTiff" {
+			t.Errorf("DriverForExtension(%q) = %s", ext, driver.ShortName())
+		}
+	}
+	if _, err := DriverForExtension(".nosuchext"); err == nil {
+		t.Error("DriverForExtension of an unknown extension succeeded")
+	}
+}
+
+func TestIdentifyDriverBytes(t *testing.T) {
+	data, err := os.ReadFile("testdata/tiles.gpkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	driver, err := IdentifyDriverBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if driver.ShortName() != "GPKG" {
+		t.Errorf("identified %s, want GPKG", driver.ShortName())
+	}
+	if _, err := IdentifyDriverBytes([]byte{0xde, 0xad, 0xbe, 0xef, 0, 1, 2, 3}); err == nil {
+		t.Error("unknown data identified")
+	}
+	if _, err := IdentifyDriverBytes(nil); err == nil {
+		t.Error("empty data identified")
+	}
+}
@@ -0,0 +1,208 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Error type                                                      */
+/* -------------------------------------------------------------------- */
+
+// Error is a failure reported by GDAL. It carries the level, class and
+// message GDAL emitted (through CPLError) while running Op, the name of
+// the GDAL function that failed.
+//
+// Error matches the sentinels ErrDebug, ErrWarning, ErrFailure, ErrFatal
+// and ErrIllegal according to its Level, and the ErrorNumber classes
+// according to its Number, so callers can write
+//
+//	if errors.Is(err, gdal.ErrorNumberOpenFailed) { ... }
+type Error struct {
+	Level  ErrorLevel
+	Number ErrorNumber
+	Msg    string
+	Op     string
+}
+
+// Error implements the error interface.
+func (err *Error) Error() string {
+	msg := err.Msg
+	if msg == "" {
+		msg = err.sentinel().Error()
+	}
+	if err.Op == "" {
+		return msg
+	}
+	return err.Op + ": " + msg
+}
+
+// Is reports whether target is the sentinel of err's level or the class
+// of err's number.
+func (err *Error) Is(target error) bool {
+	if num, ok := target.(ErrorNumber); ok {
+		return err.Number == num
+	}
+	if err.Level == ErrorLevelFatal && target == ErrFailure {
+		// Fatal errors have always been reported as ErrFailure.
+		return true
+	}
+	return target == err.sentinel()
+}
+
+func (err *Error) sentinel() error {
+	switch err.Level {
+	case ErrorLevelDebug:
+		return ErrDebug
+	case ErrorLevelWarning:
+		return ErrWarning
+	case ErrorLevelFailure:
+		return ErrFailure
+	case ErrorLevelFatal:
+		return ErrFatal
+	}
+	return ErrIllegal
+}
+
+var errorNumberNames = map[ErrorNumber]string{
+	ErrorNumberNone:            "None",
+	ErrorNumberAppDefined:      "AppDefined",
+	ErrorNumberOutOfMemory:     "OutOfMemory",
+	ErrorNumberFileIO:          "FileIO",
+	ErrorNumberOpenFailed:      "OpenFailed",
+	ErrorNumberIllegalArg:      "IllegalArg",
+	ErrorNumberNotSupported:    "NotSupported",
+	ErrorNumberAssertionFailed: "AssertionFailed",
+	ErrorNumberNoWriteAccess:   "NoWriteAccess",
+	ErrorNumberUserInterrupt:   "UserInterrupt",
+	ErrorNumberObjectNull:      "ObjectNull",
+	ErrorNumberHTTPResponse:    "HTTPResponse",
+}
+
+// Error returns the name of the error class, so that ErrorNumber values
+// can be used as errors.Is targets.
+func (num ErrorNumber) Error() string {
+	if name, ok := errorNumberNames[num]; ok {
+		return name
+	}
+	return fmt.Sprintf("ErrorNumber(%d)", int(num))
+}
+
+// cplCall runs fn, a GDAL function returning a CPLErr, with the calling
+// goroutine locked to its OS thread and GDAL's thread local error state
+// cleared, so the returned error only carries what fn itself reported.
+func cplCall(op string, fn func() C.CPLErr) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+	cerr := fn()
+	if cerr == C.CE_None {
+		return nil
+	}
+	return lastError(op, ErrorLevel(cerr))
+}
+
+// lastError builds an Error for op from GDAL's thread local error state.
+// level is used when nothing was reported, and ErrorLevelNone takes the
+// reported level. Callers must hold the OS thread since the failing call.
+func lastError(op string, level ErrorLevel) *Error {
+	err := &Error{Level: level, Number: ErrorNumberNone, Op: op}
+	if lastLevel := ErrorLevel(C.CPLGetLastErrorType()); lastLevel != ErrorLevelNone {
+		if level == ErrorLevelNone {
+			err.Level = lastLevel
+		}
+		err.Number = ErrorNumber(C.CPLGetLastErrorNo())
+		err.Msg = C.GoString(C.CPLGetLastErrorMsg())
+	}
+	if err.Level == ErrorLevelNone {
+		err.Level = ErrorLevelFailure
+	}
+	return err
+}
+
+// failure is lastError for calls that signal failure by returning NULL,
+// with msg standing in when GDAL did not report anything.
+func failure(op, msg string) *Error {
+	err := lastError(op, ErrorLevelFailure)
+	if err.Msg == "" {
+		err.Msg = msg
+	}
+	return err
+}
+
+/* -------------------------------------------------------------------- */
+/*      Scoped error handlers                                           */
+/* -------------------------------------------------------------------- */
+
+// errorScope is the state of one WithErrorHandler call.
+type errorScope struct {
+	handler  ErrorHandler
+	warnings []*Error
+}
+
+// errorScopes holds the active error scopes, referenced from C by key.
+var errorScopes = struct {
+	sync.Mutex
+	next   uintptr
+	scopes map[uintptr]*errorScope
+}{scopes: make(map[uintptr]*errorScope)}
+
+// WithErrorHandler runs fn with handler receiving the messages GDAL raises
+// while fn runs, instead of the handler set by InstallErrorHandler. handler
+// may be nil. The warnings raised inside the scope are returned along with
+// the error of fn.
+//
+// The scope is bound to the OS thread fn runs on: fn keeps the calling
+// goroutine locked to it, and GDAL calls made from other goroutines started
+// by fn are not covered. Scopes may be nested, in which case the innermost
+// one receives the messages.
+func WithErrorHandler(handler ErrorHandler, fn func() error) (warnings []*Error, err error) {
+	scope := &errorScope{handler: handler}
+
+	errorScopes.Lock()
+	errorScopes.next++
+	key := errorScopes.next
+	errorScopes.scopes[key] = scope
+	errorScopes.Unlock()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.goCPLPushErrorHandler(C.uintptr_t(key))
+	defer func() {
+		C.CPLPopErrorHandler()
+		errorScopes.Lock()
+		delete(errorScopes.scopes, key)
+		errorScopes.Unlock()
+	}()
+
+	err = fn()
+	return scope.warnings, err
+}
+
+//export cplScopedErrorHandler
+func cplScopedErrorHandler(err C.CPLErr, num C.CPLErrorNum, msg *C.char, key C.uintptr_t) {
+	errorScopes.Lock()
+	scope, ok := errorScopes.scopes[uintptr(key)]
+	errorScopes.Unlock()
+	if !ok {
+		return
+	}
+	level, number, message := ErrorLevel(err), ErrorNumber(num), C.GoString(msg)
+	if level == ErrorLevelWarning {
+		scope.warnings = append(scope.warnings, &Error{Level: level, Number: number, Msg: message})
+	}
+	if scope.handler != nil {
+		scope.handler(level, number, message)
+	}
+}
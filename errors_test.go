@@ -0,0 +1,103 @@
+package gdal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	var err error = &Error{
+		Level:  ErrorLevelFailure,
+		Number: ErrorNumberOpenFailed,
+		Msg:    "no such file",
+		Op:     "GDALOpen",
+	}
+	if !errors.Is(err, ErrFailure) {
+		t.Errorf("%v is not ErrFailure", err)
+	}
+	if !errors.Is(err, ErrorNumberOpenFailed) {
+		t.Errorf("%v is not ErrorNumberOpenFailed", err)
+	}
+	if errors.Is(err, ErrWarning) || errors.Is(err, ErrorNumberFileIO) {
+		t.Errorf("%v matches the wrong level or class", err)
+	}
+	if got, want := err.Error(), "GDALOpen: no such file"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	fatal := &Error{Level: ErrorLevelFatal}
+	if !errors.Is(fatal, ErrFatal) || !errors.Is(fatal, ErrFailure) {
+		t.Errorf("fatal error should match ErrFatal and ErrFailure")
+	}
+}
+
+func TestOpenError(t *testing.T) {
+	_, err := Open("testdata/does-not-exist.tif", ReadOnly)
+	var gdalErr *Error
+	if !errors.As(err, &gdalErr) {
+		t.Fatalf("got %T, want *Error", err)
+	}
+	if gdalErr.Op != "GDALOpen" || !errors.Is(err, ErrorNumberOpenFailed) {
+		t.Errorf("got %#v", gdalErr)
+	}
+	if !strings.Contains(gdalErr.Msg, "does-not-exist.tif") {
+		t.Errorf("message %q does not name the file", gdalErr.Msg)
+	}
+}
+
+func TestErrorsArePerCall(t *testing.T) {
+	// Each goroutine fails on its own file, whose name must be the one in
+	// its error.
+	const calls = 16
+	errs := make([]error, calls)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = Open(fmt.Sprintf("testdata/does-not-exist-%02d.tif", i), ReadOnly)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		var gdalErr *Error
+		if !errors.As(err, &gdalErr) {
+			t.Errorf("call %d: got %T, want *Error", i, err)
+			continue
+		}
+		if gdalErr.Op != "GDALOpen" {
+			t.Errorf("call %d: got Op %q, want GDALOpen", i, gdalErr.Op)
+		}
+		if name := fmt.Sprintf("does-not-exist-%02d.tif", i); !strings.Contains(gdalErr.Msg, name) {
+			t.Errorf("call %d: message %q does not name %s", i, gdalErr.Msg, name)
+		}
+	}
+}
+
+func TestWithErrorHandler(t *testing.T) {
+	var global []string
+	InstallErrorHandler(func(level ErrorLevel, num ErrorNumber, msg string) {
+		global = append(global, msg)
+	})
+	defer InstallErrorHandler(nil)
+
+	var scoped []ErrorNumber
+	_, err := WithErrorHandler(func(level ErrorLevel, num ErrorNumber, msg string) {
+		scoped = append(scoped, num)
+	}, func() error {
+		_, err := Open("testdata/does-not-exist.tif", ReadOnly)
+		return err
+	})
+	if !errors.Is(err, ErrorNumberOpenFailed) {
+		t.Errorf("got %v, want the error of the scope's function", err)
+	}
+	if len(scoped) == 0 || scoped[0] != ErrorNumberOpenFailed {
+		t.Errorf("scope handler got %v, want OpenFailed", scoped)
+	}
+	if len(global) != 0 {
+		t.Errorf("global handler got %q from inside the scope", global)
+	}
+}
@@ -0,0 +1,48 @@
+package gdal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGCPs(t *testing.T) {
+	gcps := []GCP{
+		{ID: "1", Pixel: 0, Line: 0, X: 100, Y: 200},
+		{ID: "2", Pixel: 10, Line: 0, X: 120, Y: 200},
+		{ID: "3", Pixel: 0, Line: 10, X: 100, Y: 180},
+		{ID: "4", Pixel: 10, Line: 10, X: 120, Y: 180},
+	}
+	transform, residuals, err := GCPsToGeoTransform(gcps, false)
+	if err != nil {
+		t.Fatalf("GCPsToGeoTransform: %v", err)
+	}
+	for i, want := range [6]float64{100, 2, 0, 200, 0, -2} {
+		if math.Abs(transform[i]-want) > 1e-9 {
+			t.Fatalf("got %v, want coefficient %d to be %v", transform, i, want)
+		}
+	}
+	for i, r := range residuals {
+		if r > 1e-9 {
+			t.Errorf("GCP %d has residual %v", i, r)
+		}
+	}
+	if x, y := ApplyGeoTransform(transform, 5, 5); x != 110 || y != 190 {
+		t.Errorf("got %v, %v, want 110, 190", x, y)
+	}
+
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("", 10, 10, 1, Byte, nil)
+	defer ds.Close()
+	if err := ds.SetGCPs(gcps, `GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]],PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433]]`); err != nil {
+		t.Fatalf("SetGCPs: %v", err)
+	}
+	if got := ds.GCPs(); len(got) != 4 || got[3] != gcps[3] {
+		t.Errorf("got %+v, want %+v", got, gcps)
+	}
+	if ds.GCPProjection() == "" {
+		t.Errorf("GCP projection was not set")
+	}
+}
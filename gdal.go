@@ -13,9 +13,14 @@ import "C"
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"runtime"
+	"sync"
 	"sync/atomic"
 	"unsafe"
+
+	"github.com/airmap/gdal/internal/handle"
 )
 
 var (
@@ -91,21 +96,14 @@ func InstallErrorHandler(eh ErrorHandler) {
 	errorHandler.Store(eh)
 }
 
-// Error handling.  The following is bare-bones, and needs to be replaced with something more useful.
+// Err converts a CPLErr to an error. The result is an *Error built from
+// GDAL's last error state of the current thread, which may be stale unless
+// the call was made through cplCall.
 func (err C.CPLErr) Err() error {
-	switch err {
-	case 0:
+	if err == C.CE_None {
 		return nil
-	case 1:
-		return ErrDebug
-	case 2:
-		return ErrWarning
-	case 3:
-		return ErrFailure
-	case 4:
-		return ErrFailure
 	}
-	return ErrIllegal
+	return lastError("", ErrorLevel(err))
 }
 
 // Pixel data types
@@ -124,6 +122,13 @@ const (
 	CInt32   = DataType(C.GDT_CInt32)
 	CFloat32 = DataType(C.GDT_CFloat32)
 	CFloat64 = DataType(C.GDT_CFloat64)
+
+	// The following types were added in GDAL 3.5 (UInt64, Int64) and 3.7
+	// (Int8). Their values are spelled out so the package still builds
+	// against older versions, which reject them at run time.
+	UInt64 = DataType(12)
+	Int64  = DataType(13)
+	Int8   = DataType(14)
 )
 
 // Get data type size in bits.
@@ -221,6 +226,9 @@ const (
 	OFVector        = OpenFlag(C.GDAL_OF_VECTOR)
 	OFRaster        = OpenFlag(C.GDAL_OF_RASTER)
 	OFVerbose_Error = OpenFlag(C.GDAL_OF_VERBOSE_ERROR)
+	// OFMultidimRaster opens the dataset for the multidimensional API,
+	// see Dataset.RootGroup.
+	OFMultidimRaster = OpenFlag(C.GDAL_OF_MULTIDIM_RASTER)
 )
 
 // Types of color interpretation for raster bands.
@@ -292,10 +300,12 @@ type MajorObject struct {
 
 type Dataset struct {
 	cval C.GDALDatasetH
+	ref  *handle.Ref
 }
 
 type RasterBand struct {
 	cval C.GDALRasterBandH
+	ref  *handle.Ref
 }
 
 type Driver struct {
@@ -304,16 +314,13 @@ type Driver struct {
 
 type ColorTable struct {
 	cval C.GDALColorTableH
+	ref  *handle.Ref
 }
 
 type RasterAttributeTable struct {
 	cval C.GDALRasterAttributeTableH
 }
 
-type AsyncReader struct {
-	cval C.GDALAsyncReaderH
-}
-
 type ColorEntry struct {
 	cval C.GDALColorEntry
 }
@@ -388,6 +395,44 @@ func goGDALProgressFuncProxyA(complete C.double, message *C.char, data unsafe.Po
 	)
 }
 
+// progressRegistry holds progress callbacks that are referenced from C by
+// an opaque key rather than by pointer, since closures can't be handed to
+// C code.
+var progressRegistry = struct {
+	sync.Mutex
+	next uintptr
+	args map[uintptr]*goGDALProgressFuncProxyArgs
+}{args: make(map[uintptr]*goGDALProgressFuncProxyArgs)}
+
+// registerProgress registers a progress callback for use with
+// goGDALProgressFuncRegistryProxyB. The returned key is passed as the
+// progress data; release must be called once GDAL no longer uses it.
+func registerProgress(progress ProgressFunc, data interface{}) (key unsafe.Pointer, release func()) {
+	progressRegistry.Lock()
+	defer progressRegistry.Unlock()
+	progressRegistry.next++
+	id := progressRegistry.next
+	progressRegistry.args[id] = &goGDALProgressFuncProxyArgs{progress, data}
+	return C.goGDALProgressKey(C.uintptr_t(id)), func() {
+		progressRegistry.Lock()
+		delete(progressRegistry.args, id)
+		progressRegistry.Unlock()
+	}
+}
+
+//export goGDALProgressFuncRegistryProxyA
+func goGDALProgressFuncRegistryProxyA(complete C.double, message *C.char, key C.uintptr_t) int {
+	progressRegistry.Lock()
+	arg, ok := progressRegistry.args[uintptr(key)]
+	progressRegistry.Unlock()
+	if !ok || arg.progresssFunc == nil {
+		return 1
+	}
+	return arg.progresssFunc(
+		float64(complete), C.GoString(message), arg.data,
+	)
+}
+
 /* ==================================================================== */
 /*      Registration/driver related.                                    */
 /* ==================================================================== */
@@ -397,12 +442,21 @@ const (
 	DMD_HELPTOPIC          = string(C.GDAL_DMD_HELPTOPIC)
 	DMD_MIMETYPE           = string(C.GDAL_DMD_MIMETYPE)
 	DMD_EXTENSION          = string(C.GDAL_DMD_EXTENSION)
+	DMD_EXTENSIONS         = string(C.GDAL_DMD_EXTENSIONS)
 	DMD_CREATIONOPTIONLIST = string(C.GDAL_DMD_CREATIONOPTIONLIST)
 	DMD_CREATIONDATATYPES  = string(C.GDAL_DMD_CREATIONDATATYPES)
+	DMD_OPENOPTIONLIST     = string(C.GDAL_DMD_OPENOPTIONLIST)
+
+	DS_LAYER_CREATIONOPTIONLIST = string(C.GDAL_DS_LAYER_CREATIONOPTIONLIST)
 
 	DCAP_CREATE     = string(C.GDAL_DCAP_CREATE)
 	DCAP_CREATECOPY = string(C.GDAL_DCAP_CREATECOPY)
 	DCAP_VIRTUALIO  = string(C.GDAL_DCAP_VIRTUALIO)
+
+	DCAP_RASTER          = string(C.GDAL_DCAP_RASTER)
+	DCAP_VECTOR          = string(C.GDAL_DCAP_VECTOR)
+	DCAP_GNM             = string(C.GDAL_DCAP_GNM)
+	DCAP_MULTIDIM_RASTER = string(C.GDAL_DCAP_MULTIDIM_RASTER)
 )
 
 // Create a new dataset with this driver.
@@ -430,7 +484,7 @@ func (driver Driver) Create(
 		C.GDALDataType(dataType),
 		(**C.char)(unsafe.Pointer(&opts[0])),
 	)
-	return Dataset{h}
+	return ownedDataset(h)
 }
 
 // Create a copy of a dataset
@@ -442,6 +496,7 @@ func (driver Driver) CreateCopy(
 	progress ProgressFunc,
 	data interface{},
 ) Dataset {
+	defer runtime.KeepAlive(sourceDataset.ref)
 	name := C.CString(filename)
 	defer C.free(unsafe.Pointer(name))
 
@@ -477,7 +532,7 @@ func (driver Driver) CreateCopy(
 		)
 	}
 
-	return Dataset{h}
+	return ownedDataset(h)
 }
 
 // Return the driver needed to access the provided dataset name.
@@ -502,11 +557,15 @@ func Open(filename string, access Access) (Dataset, error) {
 	cFilename := C.CString(filename)
 	defer C.free(unsafe.Pointer(cFilename))
 
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
 	dataset := C.GDALOpen(cFilename, C.GDALAccess(access))
 	if dataset == nil {
-		return Dataset{nil}, fmt.Errorf("Error: dataset '%s' open error", filename)
+		return Dataset{}, failure("GDALOpen", fmt.Sprintf("dataset '%s' open error", filename))
 	}
-	return Dataset{dataset}, nil
+	return ownedDataset(dataset), nil
 }
 
 // Open an existing dataset
@@ -547,11 +606,15 @@ func OpenEx(filename string, flags OpenFlag, allowedDrivers []string,
 		siblingsA = (**C.char)(unsafe.Pointer(&siblings[0]))
 	}
 
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
 	dataset := C.GDALOpenEx(cFilename, C.uint(flags), driversA, ooptionsA, siblingsA)
 	if dataset == nil {
-		return Dataset{nil}, fmt.Errorf("Error: dataset '%s' openEx error", filename)
+		return Dataset{}, failure("GDALOpenEx", fmt.Sprintf("dataset '%s' openEx error", filename))
 	}
-	return Dataset{dataset}, nil
+	return ownedDataset(dataset), nil
 }
 
 // Open a shared existing dataset
@@ -560,7 +623,7 @@ func OpenShared(filename string, access Access) Dataset {
 	defer C.free(unsafe.Pointer(cFilename))
 
 	dataset := C.GDALOpenShared(cFilename, C.GDALAccess(access))
-	return Dataset{dataset}
+	return ownedDataset(dataset)
 }
 
 // Unimplemented: DumpOpenDatasets
@@ -615,7 +678,9 @@ func (driver Driver) DeleteDataset(name string) error {
 	cDriver := driver.cval
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
-	return C.GDALDeleteDataset(cDriver, cName).Err()
+	return cplCall("GDALDeleteDataset", func() C.CPLErr {
+		return C.GDALDeleteDataset(cDriver, cName)
+	})
 }
 
 // Rename named dataset
@@ -625,7 +690,9 @@ func (driver Driver) RenameDataset(newName, oldName string) error {
 	defer C.free(unsafe.Pointer(cNewName))
 	cOldName := C.CString(oldName)
 	defer C.free(unsafe.Pointer(cOldName))
-	return C.GDALRenameDataset(cDriver, cNewName, cOldName).Err()
+	return cplCall("GDALRenameDataset", func() C.CPLErr {
+		return C.GDALRenameDataset(cDriver, cNewName, cOldName)
+	})
 }
 
 // Copy all files associated with the named dataset
@@ -635,7 +702,9 @@ func (driver Driver) CopyDatasetFiles(newName, oldName string) error {
 	defer C.free(unsafe.Pointer(cNewName))
 	cOldName := C.CString(oldName)
 	defer C.free(unsafe.Pointer(cOldName))
-	return C.GDALCopyDatasetFiles(cDriver, cNewName, cOldName).Err()
+	return cplCall("GDALCopyDatasetFiles", func() C.CPLErr {
+		return C.GDALCopyDatasetFiles(cDriver, cNewName, cOldName)
+	})
 }
 
 // Get the short name associated with this driver
@@ -657,8 +726,89 @@ func (driver Driver) LongName() string {
 // Unimplemented: InitGCPs
 // Unimplemented: DeinitGCPs
 // Unimplemented: DuplicateGCPs
-// Unimplemented: GCPsToGeoTransform
-// Unimplemented: ApplyGeoTransform
+
+// GCP is a ground control point, tying a pixel/line position of a raster
+// to a georeferenced position.
+type GCP struct {
+	ID    string
+	Info  string
+	Pixel float64
+	Line  float64
+	X     float64
+	Y     float64
+	Z     float64
+}
+
+// cGCPs converts gcps to GDAL_GCPs. The returned function frees the
+// strings they point to.
+func cGCPs(gcps []GCP) ([]C.GDAL_GCP, func()) {
+	cgcps := make([]C.GDAL_GCP, len(gcps))
+	for i, gcp := range gcps {
+		cgcps[i] = C.GDAL_GCP{
+			pszId:      C.CString(gcp.ID),
+			pszInfo:    C.CString(gcp.Info),
+			dfGCPPixel: C.double(gcp.Pixel),
+			dfGCPLine:  C.double(gcp.Line),
+			dfGCPX:     C.double(gcp.X),
+			dfGCPY:     C.double(gcp.Y),
+			dfGCPZ:     C.double(gcp.Z),
+		}
+	}
+	return cgcps, func() {
+		for _, cgcp := range cgcps {
+			C.free(unsafe.Pointer(cgcp.pszId))
+			C.free(unsafe.Pointer(cgcp.pszInfo))
+		}
+	}
+}
+
+// Generate a geotransform from GCPs. The transform is a least squares fit,
+// and with approxOK false it fails unless every GCP is matched within a
+// quarter pixel. residuals holds the distance, in georeferenced units,
+// between each GCP and its transformed pixel/line position.
+func GCPsToGeoTransform(gcps []GCP, approxOK bool) (transform GeoTransform, residuals []float64, err error) {
+	if len(gcps) < 2 {
+		return transform, nil, fmt.Errorf("at least 2 GCPs are needed, got %d", len(gcps))
+	}
+	cgcps, free := cGCPs(gcps)
+	defer free()
+
+	approx := C.int(0)
+	if approxOK {
+		approx = 1
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	ok := C.GDALGCPsToGeoTransform(
+		C.int(len(cgcps)),
+		&cgcps[0],
+		(*C.double)(unsafe.Pointer(&transform[0])),
+		approx,
+	)
+	if ok == 0 {
+		return transform, nil, failure("GDALGCPsToGeoTransform", "GCPs do not fit an affine transform")
+	}
+
+	residuals = make([]float64, len(gcps))
+	for i, gcp := range gcps {
+		x, y := ApplyGeoTransform(transform, gcp.Pixel, gcp.Line)
+		residuals[i] = math.Hypot(x-gcp.X, y-gcp.Y)
+	}
+	return transform, residuals, nil
+}
+
+// Apply a geotransform to a pixel/line position
+func ApplyGeoTransform(transform GeoTransform, pixel, line float64) (x, y float64) {
+	C.GDALApplyGeoTransform(
+		(*C.double)(unsafe.Pointer(&transform[0])),
+		C.double(pixel), C.double(line),
+		(*C.double)(&x), (*C.double)(&y),
+	)
+	return x, y
+}
 
 /* ==================================================================== */
 /*      major objects (dataset, and, driver, drivermanager).            */
@@ -671,6 +821,12 @@ func (object MajorObject) Description() string {
 	return desc
 }
 
+// Fetch the dataset description, usually its file name
+func (dataset Dataset) Description() string {
+	defer runtime.KeepAlive(dataset.ref)
+	return C.GoString(C.GDALGetDescription(C.GDALMajorObjectH(unsafe.Pointer(dataset.cval))))
+}
+
 // Set object description
 func (object MajorObject) SetDescription(desc string) {
 	cObject := object.cval
@@ -685,6 +841,7 @@ func (object MajorObject) Metadata(domain string) []string {
 	return nil
 }
 func (dataset *Dataset) Metadata(domain string) []string {
+	defer runtime.KeepAlive(dataset.ref)
 	cDomain := C.CString(domain)
 	defer C.free(unsafe.Pointer(cDomain))
 
@@ -727,6 +884,7 @@ func (object MajorObject) SetMetadataItem(name, value, domain string) {
 // TODO: Make correct class hirerarchy via interfaces
 
 func (rasterBand *RasterBand) SetMetadataItem(name, value, domain string) error {
+	defer runtime.KeepAlive(rasterBand.ref)
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -736,15 +894,18 @@ func (rasterBand *RasterBand) SetMetadataItem(name, value, domain string) error
 	c_domain := C.CString(domain)
 	defer C.free(unsafe.Pointer(c_domain))
 
-	return C.GDALSetMetadataItem(
-		C.GDALMajorObjectH(unsafe.Pointer(rasterBand.cval)),
-		c_name, c_value, c_domain,
-	).Err()
+	return cplCall("GDALSetMetadataItem", func() C.CPLErr {
+		return C.GDALSetMetadataItem(
+			C.GDALMajorObjectH(unsafe.Pointer(rasterBand.cval)),
+			c_name, c_value, c_domain,
+		)
+	})
 }
 
 // TODO: Make korrekt class hirerarchy via interfaces
 
 func (object *Dataset) SetMetadataItem(name, value, domain string) error {
+	defer runtime.KeepAlive(object.ref)
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -754,10 +915,12 @@ func (object *Dataset) SetMetadataItem(name, value, domain string) error {
 	c_domain := C.CString(domain)
 	defer C.free(unsafe.Pointer(c_domain))
 
-	return C.GDALSetMetadataItem(
-		C.GDALMajorObjectH(unsafe.Pointer(object.cval)),
-		c_name, c_value, c_domain,
-	).Err()
+	return cplCall("GDALSetMetadataItem", func() C.CPLErr {
+		return C.GDALSetMetadataItem(
+			C.GDALMajorObjectH(unsafe.Pointer(object.cval)),
+			c_name, c_value, c_domain,
+		)
+	})
 }
 
 // Fetch single metadata item.
@@ -776,6 +939,7 @@ func (object *Driver) MetadataItem(name, domain string) string {
 	)
 }
 func (object *Dataset) MetadataItem(name, domain string) string {
+	defer runtime.KeepAlive(object.ref)
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -791,6 +955,7 @@ func (object *Dataset) MetadataItem(name, domain string) string {
 }
 
 func (object *RasterBand) MetadataItem(name, domain string) string {
+	defer runtime.KeepAlive(object.ref)
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -811,12 +976,14 @@ func (object *RasterBand) MetadataItem(name, domain string) string {
 
 // Get the driver to which this dataset relates
 func (dataset Dataset) Driver() Driver {
+	defer runtime.KeepAlive(dataset.ref)
 	driver := Driver{C.GDALGetDatasetDriver(dataset.cval)}
 	return driver
 }
 
 // Fetch files forming the dataset.
 func (dataset Dataset) FileList() []string {
+	defer runtime.KeepAlive(dataset.ref)
 	p := C.GDALGetFileList(dataset.cval)
 	var strings []string
 	q := uintptr(unsafe.Pointer(p))
@@ -832,38 +999,47 @@ func (dataset Dataset) FileList() []string {
 	return strings
 }
 
-// Close the dataset
+// Close the dataset. With handle tracking on, only the first Close of an
+// owned dataset closes it; closing a copy again, or a borrowed dataset, is
+// a no-op.
 func (dataset Dataset) Close() {
-	C.GDALClose(dataset.cval)
+	if dataset.ref.Close() {
+		closeDataset(dataset.cval)
+	}
 	return
 }
 
 // Fetch X size of raster
 func (dataset Dataset) RasterXSize() int {
+	defer runtime.KeepAlive(dataset.ref)
 	xSize := int(C.GDALGetRasterXSize(dataset.cval))
 	return xSize
 }
 
 // Fetch Y size of raster
 func (dataset Dataset) RasterYSize() int {
+	defer runtime.KeepAlive(dataset.ref)
 	ySize := int(C.GDALGetRasterYSize(dataset.cval))
 	return ySize
 }
 
 // Fetch the number of raster bands in the dataset
 func (dataset Dataset) RasterCount() int {
+	defer runtime.KeepAlive(dataset.ref)
 	count := int(C.GDALGetRasterCount(dataset.cval))
 	return count
 }
 
 // Fetch a raster band object from a dataset
 func (dataset Dataset) RasterBand(band int) RasterBand {
-	rasterBand := RasterBand{C.GDALGetRasterBand(dataset.cval, C.int(band))}
+	defer runtime.KeepAlive(dataset.ref)
+	rasterBand := RasterBand{C.GDALGetRasterBand(dataset.cval, C.int(band)), handle.Borrowed(dataset.ref)}
 	return rasterBand
 }
 
 // Add a band to a dataset
 func (dataset Dataset) AddBand(dataType DataType, options []string) error {
+	defer runtime.KeepAlive(dataset.ref)
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -872,11 +1048,13 @@ func (dataset Dataset) AddBand(dataType DataType, options []string) error {
 	}
 	cOptions[length] = (*C.char)(unsafe.Pointer(nil))
 
-	return C.GDALAddBand(
-		dataset.cval,
-		C.GDALDataType(dataType),
-		(**C.char)(unsafe.Pointer(&cOptions[0])),
-	).Err()
+	return cplCall("GDALAddBand", func() C.CPLErr {
+		return C.GDALAddBand(
+			dataset.cval,
+			C.GDALDataType(dataType),
+			(**C.char)(unsafe.Pointer(&cOptions[0])),
+		)
+	})
 }
 
 type ResampleAlg int
@@ -887,57 +1065,95 @@ const (
 	GRA_Cubic            = ResampleAlg(2)
 	GRA_CubicSpline      = ResampleAlg(3)
 	GRA_Lanczos          = ResampleAlg(4)
+	GRA_Average          = ResampleAlg(5)
+	GRA_Mode             = ResampleAlg(6)
+	GRA_Max              = ResampleAlg(8)
+	GRA_Min              = ResampleAlg(9)
+	GRA_Med              = ResampleAlg(10)
+	GRA_Q1               = ResampleAlg(11)
+	GRA_Q3               = ResampleAlg(12)
+	GRA_Sum              = ResampleAlg(13)
+	GRA_RMS              = ResampleAlg(14)
 )
 
+var resampleAlgNames = map[ResampleAlg]string{
+	GRA_NearestNeighbour: "near",
+	GRA_Bilinear:         "bilinear",
+	GRA_Cubic:            "cubic",
+	GRA_CubicSpline:      "cubicspline",
+	GRA_Lanczos:          "lanczos",
+	GRA_Average:          "average",
+	GRA_Mode:             "mode",
+	GRA_Max:              "max",
+	GRA_Min:              "min",
+	GRA_Med:              "med",
+	GRA_Q1:               "q1",
+	GRA_Q3:               "q3",
+	GRA_Sum:              "sum",
+	GRA_RMS:              "rms",
+}
+
+// Name returns the name the command line utilities use for the
+// resampling algorithm (e.g. "cubicspline"), or "" if it is unknown.
+func (resampleAlg ResampleAlg) Name() string {
+	return resampleAlgNames[resampleAlg]
+}
+
 func (dataset Dataset) AutoCreateWarpedVRT(srcWKT, dstWKT string, resampleAlg ResampleAlg) (Dataset, error) {
+	defer runtime.KeepAlive(dataset.ref)
 	c_srcWKT := C.CString(srcWKT)
 	defer C.free(unsafe.Pointer(c_srcWKT))
 	c_dstWKT := C.CString(dstWKT)
 	defer C.free(unsafe.Pointer(c_dstWKT))
-	/*
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
 
-	 */
 	h := C.GDALAutoCreateWarpedVRT(dataset.cval, c_srcWKT, c_dstWKT, C.GDALResampleAlg(resampleAlg), 0.0, nil)
-	d := Dataset{h}
+	d := ownedDataset(h)
 	if h == nil {
-		return d, fmt.Errorf("AutoCreateWarpedVRT failed")
+		return d, failure("GDALAutoCreateWarpedVRT", "AutoCreateWarpedVRT failed")
 	}
 	return d, nil
 
 }
 
-// Unimplemented: GDALBeginAsyncReader
-// Unimplemented: GDALEndAsyncReader
-
 func determineBufferType(buffer interface{}) (dataType DataType, dataPtr unsafe.Pointer, err error) {
-	switch data := buffer.(type) {
+	switch buffer.(type) {
 	case []int8:
-		dataType = Byte
-		dataPtr = unsafe.Pointer(&data[0])
+		dataType = Int8
 	case []uint8:
 		dataType = Byte
-		dataPtr = unsafe.Pointer(&data[0])
 	case []int16:
 		dataType = Int16
-		dataPtr = unsafe.Pointer(&data[0])
 	case []uint16:
 		dataType = UInt16
-		dataPtr = unsafe.Pointer(&data[0])
 	case []int32:
 		dataType = Int32
-		dataPtr = unsafe.Pointer(&data[0])
 	case []uint32:
 		dataType = UInt32
-		dataPtr = unsafe.Pointer(&data[0])
+	case []int64:
+		dataType = Int64
+	case []uint64:
+		dataType = UInt64
 	case []float32:
 		dataType = Float32
-		dataPtr = unsafe.Pointer(&data[0])
 	case []float64:
 		dataType = Float64
-		dataPtr = unsafe.Pointer(&data[0])
+	case []complex64:
+		dataType = CFloat32
+	case []complex128:
+		dataType = CFloat64
 	default:
 		err = fmt.Errorf("error: buffer is not a valid data type (must be a valid numeric slice)")
+		return
 	}
+	data := reflect.ValueOf(buffer)
+	if data.Len() == 0 {
+		err = fmt.Errorf("error: buffer is empty")
+		return
+	}
+	dataPtr = unsafe.Pointer(data.Pointer())
 	return
 }
 
@@ -951,22 +1167,25 @@ func (dataset Dataset) IO(
 	bandMap []int,
 	pixelSpace, lineSpace, bandSpace int,
 ) error {
+	defer runtime.KeepAlive(dataset.ref)
 	dataType, dataPtr, err := determineBufferType(buffer)
 	if err != nil {
 		return err
 	}
 
-	return C.GDALDatasetRasterIO(
-		dataset.cval,
-		C.GDALRWFlag(rwFlag),
-		C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize),
-		dataPtr,
-		C.int(bufXSize), C.int(bufYSize),
-		C.GDALDataType(dataType),
-		C.int(bandCount),
-		(*C.int)(unsafe.Pointer(&IntSliceToCInt(bandMap)[0])),
-		C.int(pixelSpace), C.int(lineSpace), C.int(bandSpace),
-	).Err()
+	return cplCall("GDALDatasetRasterIO", func() C.CPLErr {
+		return C.GDALDatasetRasterIO(
+			dataset.cval,
+			C.GDALRWFlag(rwFlag),
+			C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize),
+			dataPtr,
+			C.int(bufXSize), C.int(bufYSize),
+			C.GDALDataType(dataType),
+			C.int(bandCount),
+			(*C.int)(unsafe.Pointer(&IntSliceToCInt(bandMap)[0])),
+			C.int(pixelSpace), C.int(lineSpace), C.int(bandSpace),
+		)
+	})
 }
 
 // Advise driver of upcoming read requests
@@ -978,6 +1197,7 @@ func (dataset Dataset) AdviseRead(
 	bandMap []int,
 	options []string,
 ) error {
+	defer runtime.KeepAlive(dataset.ref)
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -986,70 +1206,124 @@ func (dataset Dataset) AdviseRead(
 	}
 	cOptions[length] = (*C.char)(unsafe.Pointer(nil))
 
-	return C.GDALDatasetAdviseRead(
-		dataset.cval,
-		C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize),
-		C.int(bufXSize), C.int(bufYSize),
-		C.GDALDataType(dataType),
-		C.int(bandCount),
-		(*C.int)(unsafe.Pointer(&IntSliceToCInt(bandMap)[0])),
-		(**C.char)(unsafe.Pointer(&cOptions[0])),
-	).Err()
+	return cplCall("GDALDatasetAdviseRead", func() C.CPLErr {
+		return C.GDALDatasetAdviseRead(
+			dataset.cval,
+			C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize),
+			C.int(bufXSize), C.int(bufYSize),
+			C.GDALDataType(dataType),
+			C.int(bandCount),
+			(*C.int)(unsafe.Pointer(&IntSliceToCInt(bandMap)[0])),
+			(**C.char)(unsafe.Pointer(&cOptions[0])),
+		)
+	})
 }
 
 // Fetch the projection definition string for this dataset
 func (dataset Dataset) Projection() string {
+	defer runtime.KeepAlive(dataset.ref)
 	proj := C.GoString(C.GDALGetProjectionRef(dataset.cval))
 	return proj
 }
 
 // Set the projection reference string
 func (dataset Dataset) SetProjection(proj string) error {
+	defer runtime.KeepAlive(dataset.ref)
 	cProj := C.CString(proj)
 	defer C.free(unsafe.Pointer(cProj))
 
-	return C.GDALSetProjection(dataset.cval, cProj).Err()
+	return cplCall("GDALSetProjection", func() C.CPLErr {
+		return C.GDALSetProjection(dataset.cval, cProj)
+	})
 }
 
 // Get the affine transformation coefficients
-func (dataset Dataset) GeoTransform() [6]float64 {
-	var transform [6]float64
+func (dataset Dataset) GeoTransform() GeoTransform {
+	defer runtime.KeepAlive(dataset.ref)
+	var transform GeoTransform
 	C.GDALGetGeoTransform(dataset.cval, (*C.double)(unsafe.Pointer(&transform[0])))
 	return transform
 }
 
 // Set the affine transformation coefficients
-func (dataset Dataset) SetGeoTransform(transform [6]float64) error {
-	return C.GDALSetGeoTransform(
-		dataset.cval,
-		(*C.double)(unsafe.Pointer(&transform[0])),
-	).Err()
+func (dataset Dataset) SetGeoTransform(transform GeoTransform) error {
+	defer runtime.KeepAlive(dataset.ref)
+	return cplCall("GDALSetGeoTransform", func() C.CPLErr {
+		return C.GDALSetGeoTransform(
+			dataset.cval,
+			(*C.double)(unsafe.Pointer(&transform[0])),
+		)
+	})
 }
 
 // Return the inverted transform
-func (dataset Dataset) InvGeoTransform() [6]float64 {
+func (dataset Dataset) InvGeoTransform() GeoTransform {
 	return InvGeoTransform(dataset.GeoTransform())
 }
 
 // Invert the supplied transform
-func InvGeoTransform(transform [6]float64) [6]float64 {
-	var result [6]float64
+func InvGeoTransform(transform GeoTransform) GeoTransform {
+	var result GeoTransform
 	C.GDALInvGeoTransform((*C.double)(unsafe.Pointer(&transform[0])), (*C.double)(unsafe.Pointer(&result[0])))
 	return result
 }
 
 // Get number of GCPs
 func (dataset Dataset) GDALGetGCPCount() int {
+	defer runtime.KeepAlive(dataset.ref)
 	count := C.GDALGetGCPCount(dataset.cval)
 	return int(count)
 }
 
-// Unimplemented: GDALGetGCPProjection
-// Unimplemented: GDALGetGCPs
-// Unimplemented: GDALSetGCPs
+// Fetch the projection definition string of the GCPs
+func (dataset Dataset) GCPProjection() string {
+	defer runtime.KeepAlive(dataset.ref)
+	return C.GoString(C.GDALGetGCPProjection(dataset.cval))
+}
+
+// Fetch the GCPs of this dataset
+func (dataset Dataset) GCPs() []GCP {
+	defer runtime.KeepAlive(dataset.ref)
+	count := int(C.GDALGetGCPCount(dataset.cval))
+	if count == 0 {
+		return nil
+	}
+	cgcps := unsafe.Slice(C.GDALGetGCPs(dataset.cval), count)
+	gcps := make([]GCP, count)
+	for i, cgcp := range cgcps {
+		gcps[i] = GCP{
+			ID:    C.GoString(cgcp.pszId),
+			Info:  C.GoString(cgcp.pszInfo),
+			Pixel: float64(cgcp.dfGCPPixel),
+			Line:  float64(cgcp.dfGCPLine),
+			X:     float64(cgcp.dfGCPX),
+			Y:     float64(cgcp.dfGCPY),
+			Z:     float64(cgcp.dfGCPZ),
+		}
+	}
+	return gcps
+}
+
+// Assign GCPs, and the projection definition string they are expressed in
+func (dataset Dataset) SetGCPs(gcps []GCP, projection string) error {
+	defer runtime.KeepAlive(dataset.ref)
+	cProj := C.CString(projection)
+	defer C.free(unsafe.Pointer(cProj))
+	cgcps, free := cGCPs(gcps)
+	defer free()
+
+	var cgcpPtr *C.GDAL_GCP
+	if len(cgcps) > 0 {
+		cgcpPtr = &cgcps[0]
+	}
+	return cplCall("GDALSetGCPs", func() C.CPLErr {
+		return C.GDALSetGCPs(dataset.cval, C.int(len(cgcps)), cgcpPtr, cProj)
+	})
+}
 
 // Fetch a format specific internally meaningful handle
 func (dataset Dataset) GDALGetInternalHandle(request string) unsafe.Pointer {
+	defer runtime.KeepAlive(dataset.ref)
 	cRequest := C.CString(request)
 	defer C.free(unsafe.Pointer(cRequest))
 
@@ -1059,12 +1333,14 @@ func (dataset Dataset) GDALGetInternalHandle(request string) unsafe.Pointer {
 
 // Add one to dataset reference count
 func (dataset Dataset) GDALReferenceDataset() int {
+	defer runtime.KeepAlive(dataset.ref)
 	count := C.GDALReferenceDataset(dataset.cval)
 	return int(count)
 }
 
 // Subtract one from dataset reference count
 func (dataset Dataset) GDALDereferenceDataset() int {
+	defer runtime.KeepAlive(dataset.ref)
 	count := C.GDALDereferenceDataset(dataset.cval)
 	return int(count)
 }
@@ -1079,40 +1355,48 @@ func (dataset Dataset) BuildOverviews(
 	progress ProgressFunc,
 	data interface{},
 ) error {
+	defer runtime.KeepAlive(dataset.ref)
 	cResampling := C.CString(resampling)
 	defer C.free(unsafe.Pointer(cResampling))
 
 	arg := &goGDALProgressFuncProxyArgs{progress, data}
 
-	return C.GDALBuildOverviews(
-		dataset.cval,
-		cResampling,
-		C.int(nOverviews),
-		(*C.int)(unsafe.Pointer(&IntSliceToCInt(overviewList)[0])),
-		C.int(nBands),
-		(*C.int)(unsafe.Pointer(&IntSliceToCInt(bandList)[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
-	).Err()
+	return cplCall("GDALBuildOverviews", func() C.CPLErr {
+		return C.GDALBuildOverviews(
+			dataset.cval,
+			cResampling,
+			C.int(nOverviews),
+			(*C.int)(unsafe.Pointer(&IntSliceToCInt(overviewList)[0])),
+			C.int(nBands),
+			(*C.int)(unsafe.Pointer(&IntSliceToCInt(bandList)[0])),
+			C.goGDALProgressFuncProxyB(),
+			unsafe.Pointer(arg),
+		)
+	})
 }
 
 // Unimplemented: GDALGetOpenDatasets
 
 // Return access flag
 func (dataset Dataset) Access() Access {
+	defer runtime.KeepAlive(dataset.ref)
 	accessVal := C.GDALGetAccess(dataset.cval)
 	return Access(accessVal)
 }
 
 // Write all write cached data to disk
 func (dataset Dataset) FlushCache() {
+	defer runtime.KeepAlive(dataset.ref)
 	C.GDALFlushCache(dataset.cval)
 	return
 }
 
 // Adds a mask band to the dataset
 func (dataset Dataset) CreateMaskBand(flags int) error {
-	return C.GDALCreateDatasetMaskBand(dataset.cval, C.int(flags)).Err()
+	defer runtime.KeepAlive(dataset.ref)
+	return cplCall("GDALCreateDatasetMaskBand", func() C.CPLErr {
+		return C.GDALCreateDatasetMaskBand(dataset.cval, C.int(flags))
+	})
 }
 
 // Copy all dataset raster data
@@ -1122,6 +1406,8 @@ func (sourceDataset Dataset) CopyWholeRaster(
 	progress ProgressFunc,
 	data interface{},
 ) error {
+	defer runtime.KeepAlive(sourceDataset.ref)
+	defer runtime.KeepAlive(destDataset.ref)
 	arg := &goGDALProgressFuncProxyArgs{progress, data}
 
 	length := len(options)
@@ -1132,13 +1418,15 @@ func (sourceDataset Dataset) CopyWholeRaster(
 	}
 	cOptions[length] = (*C.char)(unsafe.Pointer(nil))
 
-	return C.GDALDatasetCopyWholeRaster(
-		sourceDataset.cval,
-		destDataset.cval,
-		(**C.char)(unsafe.Pointer(&cOptions[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
-	).Err()
+	return cplCall("GDALDatasetCopyWholeRaster", func() C.CPLErr {
+		return C.GDALDatasetCopyWholeRaster(
+			sourceDataset.cval,
+			destDataset.cval,
+			(**C.char)(unsafe.Pointer(&cOptions[0])),
+			C.goGDALProgressFuncProxyB(),
+			unsafe.Pointer(arg),
+		)
+	})
 }
 
 /* ==================================================================== */
@@ -1147,15 +1435,17 @@ func (sourceDataset Dataset) CopyWholeRaster(
 
 // Fetch the pixel data type for this band
 func (rasterBand RasterBand) RasterDataType() DataType {
+	defer runtime.KeepAlive(rasterBand.ref)
 	dataType := C.GDALGetRasterDataType(rasterBand.cval)
 	return DataType(dataType)
 }
 
 // Fetch the "natural" block size of this band
 func (rasterBand RasterBand) BlockSize() (int, int) {
-	var xSize, ySize int
-	C.GDALGetBlockSize(rasterBand.cval, (*C.int)(unsafe.Pointer(&xSize)), (*C.int)(unsafe.Pointer(&ySize)))
-	return xSize, ySize
+	defer runtime.KeepAlive(rasterBand.ref)
+	var xSize, ySize C.int
+	C.GDALGetBlockSize(rasterBand.cval, &xSize, &ySize)
+	return int(xSize), int(ySize)
 }
 
 // Advise driver of upcoming read requests
@@ -1164,6 +1454,7 @@ func (rasterBand RasterBand) AdviseRead(
 	dataType DataType,
 	options []string,
 ) error {
+	defer runtime.KeepAlive(rasterBand.ref)
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -1172,12 +1463,14 @@ func (rasterBand RasterBand) AdviseRead(
 	}
 	cOptions[length] = (*C.char)(unsafe.Pointer(nil))
 
-	return C.GDALRasterAdviseRead(
-		rasterBand.cval,
-		C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize), C.int(bufXSize), C.int(bufYSize),
-		C.GDALDataType(dataType),
-		(**C.char)(unsafe.Pointer(&cOptions[0])),
-	).Err()
+	return cplCall("GDALRasterAdviseRead", func() C.CPLErr {
+		return C.GDALRasterAdviseRead(
+			rasterBand.cval,
+			C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize), C.int(bufXSize), C.int(bufYSize),
+			C.GDALDataType(dataType),
+			(**C.char)(unsafe.Pointer(&cOptions[0])),
+		)
+	})
 }
 
 // Read / Write a region of image data for this band
@@ -1188,104 +1481,131 @@ func (rasterBand RasterBand) IO(
 	bufXSize, bufYSize int,
 	pixelSpace, lineSpace int,
 ) error {
+	defer runtime.KeepAlive(rasterBand.ref)
 	dataType, dataPtr, err := determineBufferType(buffer)
 	if err != nil {
 		return err
 	}
 
-	return C.GDALRasterIO(
-		rasterBand.cval,
-		C.GDALRWFlag(rwFlag),
-		C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize),
-		dataPtr,
-		C.int(bufXSize), C.int(bufYSize),
-		C.GDALDataType(dataType),
-		C.int(pixelSpace), C.int(lineSpace),
-	).Err()
+	return cplCall("GDALRasterIO", func() C.CPLErr {
+		return C.GDALRasterIO(
+			rasterBand.cval,
+			C.GDALRWFlag(rwFlag),
+			C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize),
+			dataPtr,
+			C.int(bufXSize), C.int(bufYSize),
+			C.GDALDataType(dataType),
+			C.int(pixelSpace), C.int(lineSpace),
+		)
+	})
 }
 
 // Read a block of image data efficiently
 func (rasterBand RasterBand) ReadBlock(xOff, yOff int, dataPtr unsafe.Pointer) error {
-	return C.GDALReadBlock(rasterBand.cval, C.int(xOff), C.int(yOff), dataPtr).Err()
+	defer runtime.KeepAlive(rasterBand.ref)
+	return cplCall("GDALReadBlock", func() C.CPLErr {
+		return C.GDALReadBlock(rasterBand.cval, C.int(xOff), C.int(yOff), dataPtr)
+	})
 }
 
 // Write a block of image data efficiently
 func (rasterBand RasterBand) WriteBlock(xOff, yOff int, dataPtr unsafe.Pointer) error {
-	return C.GDALWriteBlock(rasterBand.cval, C.int(xOff), C.int(yOff), dataPtr).Err()
+	defer runtime.KeepAlive(rasterBand.ref)
+	return cplCall("GDALWriteBlock", func() C.CPLErr {
+		return C.GDALWriteBlock(rasterBand.cval, C.int(xOff), C.int(yOff), dataPtr)
+	})
 }
 
 // Fetch X size of raster
 func (rasterBand RasterBand) XSize() int {
+	defer runtime.KeepAlive(rasterBand.ref)
 	xSize := C.GDALGetRasterBandXSize(rasterBand.cval)
 	return int(xSize)
 }
 
 // Fetch Y size of raster
 func (rasterBand RasterBand) YSize() int {
+	defer runtime.KeepAlive(rasterBand.ref)
 	ySize := C.GDALGetRasterBandYSize(rasterBand.cval)
 	return int(ySize)
 }
 
 // Find out if we have update permission for this band
 func (rasterBand RasterBand) GetAccess() Access {
+	defer runtime.KeepAlive(rasterBand.ref)
 	access := C.GDALGetRasterAccess(rasterBand.cval)
 	return Access(access)
 }
 
 // Fetch the band number of this raster band
 func (rasterBand RasterBand) BandNumber() int {
+	defer runtime.KeepAlive(rasterBand.ref)
 	bandNumber := C.GDALGetBandNumber(rasterBand.cval)
 	return int(bandNumber)
 }
 
 // Fetch the owning dataset handle
 func (rasterBand RasterBand) GetDataset() Dataset {
+	defer runtime.KeepAlive(rasterBand.ref)
 	dataset := C.GDALGetBandDataset(rasterBand.cval)
-	return Dataset{dataset}
+	return Dataset{dataset, handle.Borrowed(rasterBand.ref)}
 }
 
 // How should this band be interpreted as color?
 func (rasterBand RasterBand) ColorInterp() ColorInterp {
+	defer runtime.KeepAlive(rasterBand.ref)
 	colorInterp := C.GDALGetRasterColorInterpretation(rasterBand.cval)
 	return ColorInterp(colorInterp)
 }
 
 // Set color interpretation of the raster band
 func (rasterBand RasterBand) SetColorInterp(colorInterp ColorInterp) error {
-	return C.GDALSetRasterColorInterpretation(rasterBand.cval, C.GDALColorInterp(colorInterp)).Err()
+	defer runtime.KeepAlive(rasterBand.ref)
+	return cplCall("GDALSetRasterColorInterpretation", func() C.CPLErr {
+		return C.GDALSetRasterColorInterpretation(rasterBand.cval, C.GDALColorInterp(colorInterp))
+	})
 }
 
 // Fetch the color table associated with this raster band
 func (rasterBand RasterBand) ColorTable() ColorTable {
+	defer runtime.KeepAlive(rasterBand.ref)
 	colorTable := C.GDALGetRasterColorTable(rasterBand.cval)
-	return ColorTable{colorTable}
+	return ColorTable{colorTable, handle.Borrowed(rasterBand.ref)}
 }
 
 // Set the raster color table for this raster band
 func (rasterBand RasterBand) SetColorTable(colorTable ColorTable) error {
-	return C.GDALSetRasterColorTable(rasterBand.cval, colorTable.cval).Err()
+	defer runtime.KeepAlive(rasterBand.ref)
+	defer runtime.KeepAlive(colorTable.ref)
+	return cplCall("GDALSetRasterColorTable", func() C.CPLErr {
+		return C.GDALSetRasterColorTable(rasterBand.cval, colorTable.cval)
+	})
 }
 
 // Check for arbitrary overviews
 func (rasterBand RasterBand) HasArbitraryOverviews() int {
+	defer runtime.KeepAlive(rasterBand.ref)
 	yes := C.GDALHasArbitraryOverviews(rasterBand.cval)
 	return int(yes)
 }
 
 // Return the number of overview layers available
 func (rasterBand RasterBand) OverviewCount() int {
+	defer runtime.KeepAlive(rasterBand.ref)
 	count := C.GDALGetOverviewCount(rasterBand.cval)
 	return int(count)
 }
 
 // Fetch overview raster band object
 func (rasterBand RasterBand) Overview(level int) RasterBand {
+	defer runtime.KeepAlive(rasterBand.ref)
 	overview := C.GDALGetOverview(rasterBand.cval, C.int(level))
-	return RasterBand{overview}
+	return RasterBand{overview, handle.Borrowed(rasterBand.ref)}
 }
 
 // Fetch the no data value for this band
 func (rasterBand RasterBand) NoDataValue() (val float64, valid bool) {
+	defer runtime.KeepAlive(rasterBand.ref)
 	var success int
 	noDataVal := C.GDALGetRasterNoDataValue(rasterBand.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(noDataVal), success != 0
@@ -1293,11 +1613,15 @@ func (rasterBand RasterBand) NoDataValue() (val float64, valid bool) {
 
 // Set the no data value for this band
 func (rasterBand RasterBand) SetNoDataValue(val float64) error {
-	return C.GDALSetRasterNoDataValue(rasterBand.cval, C.double(val)).Err()
+	defer runtime.KeepAlive(rasterBand.ref)
+	return cplCall("GDALSetRasterNoDataValue", func() C.CPLErr {
+		return C.GDALSetRasterNoDataValue(rasterBand.cval, C.double(val))
+	})
 }
 
 // Fetch the list of category names for this raster
 func (rasterBand RasterBand) CategoryNames() []string {
+	defer runtime.KeepAlive(rasterBand.ref)
 	p := C.GDALGetRasterCategoryNames(rasterBand.cval)
 	var strings []string
 	q := uintptr(unsafe.Pointer(p))
@@ -1315,6 +1639,7 @@ func (rasterBand RasterBand) CategoryNames() []string {
 
 // Set the category names for this band
 func (rasterBand RasterBand) SetRasterCategoryNames(names []string) error {
+	defer runtime.KeepAlive(rasterBand.ref)
 	length := len(names)
 	cStrings := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -1323,11 +1648,14 @@ func (rasterBand RasterBand) SetRasterCategoryNames(names []string) error {
 	}
 	cStrings[length] = (*C.char)(unsafe.Pointer(nil))
 
-	return C.GDALSetRasterCategoryNames(rasterBand.cval, (**C.char)(unsafe.Pointer(&cStrings[0]))).Err()
+	return cplCall("GDALSetRasterCategoryNames", func() C.CPLErr {
+		return C.GDALSetRasterCategoryNames(rasterBand.cval, (**C.char)(unsafe.Pointer(&cStrings[0])))
+	})
 }
 
 // Fetch the minimum value for this band
 func (rasterBand RasterBand) GetMinimum() (val float64, valid bool) {
+	defer runtime.KeepAlive(rasterBand.ref)
 	var success int
 	min := C.GDALGetRasterMinimum(rasterBand.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(min), success != 0
@@ -1335,6 +1663,7 @@ func (rasterBand RasterBand) GetMinimum() (val float64, valid bool) {
 
 // Fetch the maximum value for this band
 func (rasterBand RasterBand) GetMaximum() (val float64, valid bool) {
+	defer runtime.KeepAlive(rasterBand.ref)
 	var success int
 	max := C.GDALGetRasterMaximum(rasterBand.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(max), success != 0
@@ -1342,6 +1671,7 @@ func (rasterBand RasterBand) GetMaximum() (val float64, valid bool) {
 
 // Fetch image statistics
 func (rasterBand RasterBand) GetStatistics(approxOK, force int) (min, max, mean, stdDev float64) {
+	defer runtime.KeepAlive(rasterBand.ref)
 	C.GDALGetRasterStatistics(
 		rasterBand.cval,
 		C.int(approxOK),
@@ -1360,6 +1690,7 @@ func (rasterBand RasterBand) ComputeStatistics(
 	progress ProgressFunc,
 	data interface{},
 ) (min, max, mean, stdDev float64) {
+	defer runtime.KeepAlive(rasterBand.ref)
 	arg := &goGDALProgressFuncProxyArgs{progress, data}
 
 	C.GDALComputeRasterStatistics(
@@ -1377,31 +1708,39 @@ func (rasterBand RasterBand) ComputeStatistics(
 
 // Set statistics on raster band
 func (rasterBand RasterBand) SetStatistics(min, max, mean, stdDev float64) error {
-	return C.GDALSetRasterStatistics(
-		rasterBand.cval,
-		C.double(min),
-		C.double(max),
-		C.double(mean),
-		C.double(stdDev),
-	).Err()
+	defer runtime.KeepAlive(rasterBand.ref)
+	return cplCall("GDALSetRasterStatistics", func() C.CPLErr {
+		return C.GDALSetRasterStatistics(
+			rasterBand.cval,
+			C.double(min),
+			C.double(max),
+			C.double(mean),
+			C.double(stdDev),
+		)
+	})
 }
 
 // Return raster unit type
 func (rasterBand RasterBand) GetUnitType() string {
+	defer runtime.KeepAlive(rasterBand.ref)
 	cString := C.GDALGetRasterUnitType(rasterBand.cval)
 	return C.GoString(cString)
 }
 
 // Set unit type
 func (rasterBand RasterBand) SetUnitType(unit string) error {
+	defer runtime.KeepAlive(rasterBand.ref)
 	cString := C.CString(unit)
 	defer C.free(unsafe.Pointer(cString))
 
-	return C.GDALSetRasterUnitType(rasterBand.cval, cString).Err()
+	return cplCall("GDALSetRasterUnitType", func() C.CPLErr {
+		return C.GDALSetRasterUnitType(rasterBand.cval, cString)
+	})
 }
 
 // Fetch the raster value offset
 func (rasterBand RasterBand) GetOffset() (float64, bool) {
+	defer runtime.KeepAlive(rasterBand.ref)
 	var success int
 	val := C.GDALGetRasterOffset(rasterBand.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(val), success != 0
@@ -1409,11 +1748,15 @@ func (rasterBand RasterBand) GetOffset() (float64, bool) {
 
 // Set scaling offset
 func (rasterBand RasterBand) SetOffset(offset float64) error {
-	return C.GDALSetRasterOffset(rasterBand.cval, C.double(offset)).Err()
+	defer runtime.KeepAlive(rasterBand.ref)
+	return cplCall("GDALSetRasterOffset", func() C.CPLErr {
+		return C.GDALSetRasterOffset(rasterBand.cval, C.double(offset))
+	})
 }
 
 // Fetch the raster value scale
 func (rasterBand RasterBand) GetScale() (float64, bool) {
+	defer runtime.KeepAlive(rasterBand.ref)
 	var success int
 	val := C.GDALGetRasterScale(rasterBand.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(val), success != 0
@@ -1421,11 +1764,15 @@ func (rasterBand RasterBand) GetScale() (float64, bool) {
 
 // Set scaling ratio
 func (rasterBand RasterBand) SetScale(scale float64) error {
-	return C.GDALSetRasterScale(rasterBand.cval, C.double(scale)).Err()
+	defer runtime.KeepAlive(rasterBand.ref)
+	return cplCall("GDALSetRasterScale", func() C.CPLErr {
+		return C.GDALSetRasterScale(rasterBand.cval, C.double(scale))
+	})
 }
 
 // Compute the min / max values for a band
 func (rasterBand RasterBand) ComputeMinMax(approxOK int) (min, max float64) {
+	defer runtime.KeepAlive(rasterBand.ref)
 	var minmax [2]float64
 	C.GDALComputeRasterMinMax(
 		rasterBand.cval,
@@ -1436,6 +1783,7 @@ func (rasterBand RasterBand) ComputeMinMax(approxOK int) (min, max float64) {
 
 // Flush raster data cache
 func (rasterBand RasterBand) FlushCache() {
+	defer runtime.KeepAlive(rasterBand.ref)
 	C.GDALFlushRasterCache(rasterBand.cval)
 }
 
@@ -1447,23 +1795,26 @@ func (rasterBand RasterBand) Histogram(
 	progress ProgressFunc,
 	data interface{},
 ) ([]int, error) {
+	defer runtime.KeepAlive(rasterBand.ref)
 	arg := &goGDALProgressFuncProxyArgs{
 		progress, data,
 	}
 
 	histogram := make([]C.GUIntBig, buckets)
 
-	if err := C.GDALGetRasterHistogramEx(
-		rasterBand.cval,
-		C.double(min),
-		C.double(max),
-		C.int(buckets),
-		(*C.GUIntBig)(unsafe.Pointer(&histogram[0])),
-		C.int(includeOutOfRange),
-		C.int(approxOK),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
-	).Err(); err != nil {
+	if err := cplCall("GDALGetRasterHistogramEx", func() C.CPLErr {
+		return C.GDALGetRasterHistogramEx(
+			rasterBand.cval,
+			C.double(min),
+			C.double(max),
+			C.int(buckets),
+			(*C.GUIntBig)(unsafe.Pointer(&histogram[0])),
+			C.int(includeOutOfRange),
+			C.int(approxOK),
+			C.goGDALProgressFuncProxyB(),
+			unsafe.Pointer(arg),
+		)
+	}); err != nil {
 		return nil, err
 	} else {
 		return CUIntBigSliceToInt(histogram), nil
@@ -1476,22 +1827,25 @@ func (rasterBand RasterBand) DefaultHistogram(
 	progress ProgressFunc,
 	data interface{},
 ) (min, max float64, buckets int, histogram []int, err error) {
+	defer runtime.KeepAlive(rasterBand.ref)
 	arg := &goGDALProgressFuncProxyArgs{
 		progress, data,
 	}
 
 	var cHistogram *C.GUIntBig
 
-	err = C.GDALGetDefaultHistogramEx(
-		rasterBand.cval,
-		(*C.double)(&min),
-		(*C.double)(&max),
-		(*C.int)(unsafe.Pointer(&buckets)),
-		&cHistogram,
-		C.int(force),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
-	).Err()
+	err = cplCall("GDALGetDefaultHistogramEx", func() C.CPLErr {
+		return C.GDALGetDefaultHistogramEx(
+			rasterBand.cval,
+			(*C.double)(&min),
+			(*C.double)(&max),
+			(*C.int)(unsafe.Pointer(&buckets)),
+			&cHistogram,
+			C.int(force),
+			C.goGDALProgressFuncProxyB(),
+			unsafe.Pointer(arg),
+		)
+	})
 
 	sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&histogram))
 	sliceHeader.Cap = buckets
@@ -1502,50 +1856,148 @@ func (rasterBand RasterBand) DefaultHistogram(
 }
 
 // Set default raster histogram
-// Unimplemented: SetDefaultHistogram
+func (rasterBand RasterBand) SetDefaultHistogram(min, max float64, histogram []int) error {
+	defer runtime.KeepAlive(rasterBand.ref)
+	if len(histogram) == 0 {
+		return fmt.Errorf("empty histogram")
+	}
+	cHistogram := make([]C.GUIntBig, len(histogram))
+	for i, count := range histogram {
+		cHistogram[i] = C.GUIntBig(count)
+	}
 
-// Unimplemented: GetRandomRasterSample
+	return cplCall("GDALSetDefaultHistogramEx", func() C.CPLErr {
+		return C.GDALSetDefaultHistogramEx(
+			rasterBand.cval,
+			C.double(min),
+			C.double(max),
+			C.int(len(histogram)),
+			(*C.GUIntBig)(unsafe.Pointer(&cHistogram[0])),
+		)
+	})
+}
+
+// Fetch up to count pixel values spread over the band, skipping nodata
+func (rasterBand RasterBand) RandomRasterSample(count int) []float32 {
+	defer runtime.KeepAlive(rasterBand.ref)
+	if count <= 0 {
+		return nil
+	}
+	samples := make([]float32, count)
+	n := C.GDALGetRandomRasterSample(rasterBand.cval, C.int(count), (*C.float)(unsafe.Pointer(&samples[0])))
+	return samples[:int(n)]
+}
 
 // Fetch best sampling overviews
-// Unimplemented: GetRasterSampleOverview
+func (rasterBand RasterBand) RasterSampleOverview(desiredSamples int) RasterBand {
+	defer runtime.KeepAlive(rasterBand.ref)
+	overview := C.GDALGetRasterSampleOverviewEx(rasterBand.cval, C.GUIntBig(desiredSamples))
+	return RasterBand{overview, handle.Borrowed(rasterBand.ref)}
+}
 
 // Fill this band with a constant value
 func (rasterBand RasterBand) Fill(real, imaginary float64) error {
-	return C.GDALFillRaster(rasterBand.cval, C.double(real), C.double(imaginary)).Err()
+	defer runtime.KeepAlive(rasterBand.ref)
+	return cplCall("GDALFillRaster", func() C.CPLErr {
+		return C.GDALFillRaster(rasterBand.cval, C.double(real), C.double(imaginary))
+	})
 }
 
-// Unimplemented: ComputeBandStats
+// Compute the mean and standard deviation of every sampleStep-th line
+func (rasterBand RasterBand) ComputeBandStats(
+	sampleStep int,
+	progress ProgressFunc,
+	data interface{},
+) (mean, stdDev float64, err error) {
+	defer runtime.KeepAlive(rasterBand.ref)
+	key, release := registerProgress(progress, data)
+	defer release()
+
+	err = cplCall("GDALComputeBandStats", func() C.CPLErr {
+		return C.GDALComputeBandStats(
+			rasterBand.cval,
+			C.int(sampleStep),
+			(*C.double)(unsafe.Pointer(&mean)),
+			(*C.double)(unsafe.Pointer(&stdDev)),
+			C.goGDALProgressFuncRegistryProxyB(),
+			key,
+		)
+	})
+	return mean, stdDev, err
+}
 
-// Unimplemented: OverviewMagnitudeCorrection
+// Undo the attenuation of complex overviews computed by averaging
+func (rasterBand RasterBand) OverviewMagnitudeCorrection(
+	overviews []RasterBand,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	defer runtime.KeepAlive(rasterBand.ref)
+	defer runtime.KeepAlive(overviews)
+	if len(overviews) == 0 {
+		return nil
+	}
+	cOverviews := make([]C.GDALRasterBandH, len(overviews))
+	for i, overview := range overviews {
+		cOverviews[i] = overview.cval
+	}
+	key, release := registerProgress(progress, data)
+	defer release()
+
+	return cplCall("GDALOverviewMagnitudeCorrection", func() C.CPLErr {
+		return C.GDALOverviewMagnitudeCorrection(
+			rasterBand.cval,
+			C.int(len(overviews)),
+			(*C.GDALRasterBandH)(unsafe.Pointer(&cOverviews[0])),
+			C.goGDALProgressFuncRegistryProxyB(),
+			key,
+		)
+	})
+}
 
 // Fetch default Raster Attribute Table
 func (rasterBand RasterBand) GetDefaultRAT() RasterAttributeTable {
+	defer runtime.KeepAlive(rasterBand.ref)
 	rat := C.GDALGetDefaultRAT(rasterBand.cval)
 	return RasterAttributeTable{rat}
 }
 
 // Set default Raster Attribute Table
 func (rasterBand RasterBand) SetDefaultRAT(rat RasterAttributeTable) error {
-	return C.GDALSetDefaultRAT(rasterBand.cval, rat.cval).Err()
+	defer runtime.KeepAlive(rasterBand.ref)
+	return cplCall("GDALSetDefaultRAT", func() C.CPLErr {
+		return C.GDALSetDefaultRAT(rasterBand.cval, rat.cval)
+	})
 }
 
-// Unimplemented: AddDerivedBandPixelFunc
-
 // Return the mask band associated with the band
 func (rasterBand RasterBand) GetMaskBand() RasterBand {
+	defer runtime.KeepAlive(rasterBand.ref)
 	mask := C.GDALGetMaskBand(rasterBand.cval)
-	return RasterBand{mask}
+	return RasterBand{mask, handle.Borrowed(rasterBand.ref)}
 }
 
+// Status flags of mask bands, as returned by GetMaskFlags
+const (
+	GMF_AllValid   = int(C.GMF_ALL_VALID)
+	GMF_PerDataset = int(C.GMF_PER_DATASET)
+	GMF_Alpha      = int(C.GMF_ALPHA)
+	GMF_NoData     = int(C.GMF_NODATA)
+)
+
 // Return the status flags of the mask band associated with the band
 func (rasterBand RasterBand) GetMaskFlags() int {
+	defer runtime.KeepAlive(rasterBand.ref)
 	flags := C.GDALGetMaskFlags(rasterBand.cval)
 	return int(flags)
 }
 
 // Adds a mask band to the current band
 func (rasterBand RasterBand) CreateMaskBand(flags int) error {
-	return C.GDALCreateMaskBand(rasterBand.cval, C.int(flags)).Err()
+	defer runtime.KeepAlive(rasterBand.ref)
+	return cplCall("GDALCreateMaskBand", func() C.CPLErr {
+		return C.GDALCreateMaskBand(rasterBand.cval, C.int(flags))
+	})
 }
 
 // Copy all raster band raster data
@@ -1555,6 +2007,8 @@ func (sourceRaster RasterBand) RasterBandCopyWholeRaster(
 	progress ProgressFunc,
 	data interface{},
 ) error {
+	defer runtime.KeepAlive(sourceRaster.ref)
+	defer runtime.KeepAlive(destRaster.ref)
 	arg := &goGDALProgressFuncProxyArgs{progress, data}
 
 	length := len(options)
@@ -1565,25 +2019,50 @@ func (sourceRaster RasterBand) RasterBandCopyWholeRaster(
 	}
 	cOptions[length] = (*C.char)(unsafe.Pointer(nil))
 
-	return C.GDALRasterBandCopyWholeRaster(
-		sourceRaster.cval,
-		destRaster.cval,
-		(**C.char)(unsafe.Pointer(&cOptions[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
-	).Err()
+	return cplCall("GDALRasterBandCopyWholeRaster", func() C.CPLErr {
+		return C.GDALRasterBandCopyWholeRaster(
+			sourceRaster.cval,
+			destRaster.cval,
+			(**C.char)(unsafe.Pointer(&cOptions[0])),
+			C.goGDALProgressFuncProxyB(),
+			unsafe.Pointer(arg),
+		)
+	})
 }
 
 // Generate downsampled overviews
-// Unimplemented: RegenerateOverviews
-
-/* ==================================================================== */
-/*     GDALAsyncReader                                                  */
-/* ==================================================================== */
-
-// Unimplemented: GetNextUpdatedRegion
-// Unimplemented: LockBuffer
-// Unimplemented: UnlockBuffer
+// Generate the given overview bands from this band
+func (rasterBand RasterBand) RegenerateOverviews(
+	overviews []RasterBand,
+	resampling string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	defer runtime.KeepAlive(rasterBand.ref)
+	defer runtime.KeepAlive(overviews)
+	if len(overviews) == 0 {
+		return nil
+	}
+	cOverviews := make([]C.GDALRasterBandH, len(overviews))
+	for i, overview := range overviews {
+		cOverviews[i] = overview.cval
+	}
+	cResampling := C.CString(resampling)
+	defer C.free(unsafe.Pointer(cResampling))
+	key, release := registerProgress(progress, data)
+	defer release()
+
+	return cplCall("GDALRegenerateOverviews", func() C.CPLErr {
+		return C.GDALRegenerateOverviews(
+			rasterBand.cval,
+			C.int(len(overviews)),
+			(*C.GDALRasterBandH)(unsafe.Pointer(&cOverviews[0])),
+			cResampling,
+			C.goGDALProgressFuncRegistryProxyB(),
+			key,
+		)
+	})
+}
 
 /* ==================================================================== */
 /*      Color tables.                                                   */
@@ -1592,47 +2071,63 @@ func (sourceRaster RasterBand) RasterBandCopyWholeRaster(
 // Construct a new color table
 func CreateColorTable(interp PaletteInterp) ColorTable {
 	ct := C.GDALCreateColorTable(C.GDALPaletteInterp(interp))
-	return ColorTable{ct}
+	return ownedColorTable(ct)
 }
 
-// Destroy the color table
+// Destroy the color table. With handle tracking on, destroying it again or
+// destroying a borrowed color table is a no-op.
 func (ct ColorTable) Destroy() {
-	C.GDALDestroyColorTable(ct.cval)
+	if ct.ref.Close() {
+		C.GDALDestroyColorTable(ct.cval)
+	}
 }
 
 // Make a copy of the color table
 func (ct ColorTable) Clone() ColorTable {
+	defer runtime.KeepAlive(ct.ref)
 	newCT := C.GDALCloneColorTable(ct.cval)
-	return ColorTable{newCT}
+	return ownedColorTable(newCT)
 }
 
 // Fetch palette interpretation
 func (ct ColorTable) PaletteInterpretation() PaletteInterp {
+	defer runtime.KeepAlive(ct.ref)
 	pi := C.GDALGetPaletteInterpretation(ct.cval)
 	return PaletteInterp(pi)
 }
 
 // Get number of color entries in table
 func (ct ColorTable) EntryCount() int {
+	defer runtime.KeepAlive(ct.ref)
 	count := C.GDALGetColorEntryCount(ct.cval)
 	return int(count)
 }
 
 // Fetch a color entry from table
 func (ct ColorTable) Entry(index int) ColorEntry {
+	defer runtime.KeepAlive(ct.ref)
 	entry := C.GDALGetColorEntry(ct.cval, C.int(index))
 	return ColorEntry{*entry}
 }
 
-// Unimplemented: EntryAsRGB
+// Fetch a color entry from table, converted to RGB for gray, CMYK and HLS
+// tables
+func (ct ColorTable) EntryAsRGB(index int) (ColorEntry, bool) {
+	defer runtime.KeepAlive(ct.ref)
+	var entry C.GDALColorEntry
+	ok := C.GDALGetColorEntryAsRGB(ct.cval, C.int(index), &entry)
+	return ColorEntry{entry}, ok != 0
+}
 
 // Set entry in color table
 func (ct ColorTable) SetEntry(index int, entry ColorEntry) {
+	defer runtime.KeepAlive(ct.ref)
 	C.GDALSetColorEntry(ct.cval, C.int(index), &entry.cval)
 }
 
 // Create color ramp
 func (ct ColorTable) CreateColorRamp(start, end int, startColor, endColor ColorEntry) {
+	defer runtime.KeepAlive(ct.ref)
 	C.GDALCreateColorRamp(ct.cval, C.int(start), &startColor.cval, C.int(end), &endColor.cval)
 }
 
@@ -1763,12 +2258,16 @@ func (rat RasterAttributeTable) SetRowCount(count int) {
 func (rat RasterAttributeTable) CreateColumn(name string, rft RATFieldType, rfu RATFieldUsage) error {
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
-	return C.GDALRATCreateColumn(rat.cval, cName, C.GDALRATFieldType(rft), C.GDALRATFieldUsage(rfu)).Err()
+	return cplCall("GDALRATCreateColumn", func() C.CPLErr {
+		return C.GDALRATCreateColumn(rat.cval, cName, C.GDALRATFieldType(rft), C.GDALRATFieldUsage(rfu))
+	})
 }
 
 // Set linear binning information
 func (rat RasterAttributeTable) SetLinearBinning(row0min, binsize float64) error {
-	return C.GDALRATSetLinearBinning(rat.cval, C.double(row0min), C.double(binsize)).Err()
+	return cplCall("GDALRATSetLinearBinning", func() C.CPLErr {
+		return C.GDALRATSetLinearBinning(rat.cval, C.double(row0min), C.double(binsize))
+	})
 }
 
 // Fetch linear binning information
@@ -1779,13 +2278,16 @@ func (rat RasterAttributeTable) LinearBinning() (row0min, binsize float64, exist
 
 // Initialize RAT from color table
 func (rat RasterAttributeTable) FromColorTable(ct ColorTable) error {
-	return C.GDALRATInitializeFromColorTable(rat.cval, ct.cval).Err()
+	defer runtime.KeepAlive(ct.ref)
+	return cplCall("GDALRATInitializeFromColorTable", func() C.CPLErr {
+		return C.GDALRATInitializeFromColorTable(rat.cval, ct.cval)
+	})
 }
 
 // Translate RAT to a color table
 func (rat RasterAttributeTable) ToColorTable(count int) ColorTable {
 	ct := C.GDALRATTranslateToColorTable(rat.cval, C.int(count))
-	return ColorTable{ct}
+	return ownedColorTable(ct)
 }
 
 // Dump RAT in readable form to a file
@@ -26,3 +26,26 @@ func TestMissingMetadata(t *testing.T) {
 		t.Errorf("got %d items, want 0", len(metadata))
 	}
 }
+
+func TestTrackedClose(t *testing.T) {
+	TrackHandles(true, false)
+	defer TrackHandles(false, false)
+
+	ds, err := Open("testdata/tiles.gpkg", ReadOnly)
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	band := ds.RasterBand(1)
+	if borrowed := band.GetDataset(); borrowed.Owned() {
+		t.Errorf("dataset of a band should be borrowed")
+	} else {
+		borrowed.Close()
+	}
+	if band.XSize() != ds.RasterXSize() {
+		t.Errorf("closing a borrowed dataset closed its owner")
+	}
+
+	copied := ds
+	ds.Close()
+	copied.Close()
+}
@@ -0,0 +1,157 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Affine transforms                                               */
+/* -------------------------------------------------------------------- */
+
+// GeoTransform holds the affine transformation coefficients from pixel/line
+// space to georeferenced space, in the order used by GDAL:
+//
+//	x = gt[0] + pixel*gt[1] + line*gt[2]
+//	y = gt[3] + pixel*gt[4] + line*gt[5]
+type GeoTransform [6]float64
+
+// Inverse returns the transform from georeferenced to pixel/line space,
+// and false if the transform is not invertible.
+func (gt GeoTransform) Inverse() (GeoTransform, bool) {
+	var result GeoTransform
+	ok := C.GDALInvGeoTransform(
+		(*C.double)(unsafe.Pointer(&gt[0])),
+		(*C.double)(unsafe.Pointer(&result[0])),
+	)
+	return result, ok != 0
+}
+
+// PixelToGeo transforms a pixel/line position to georeferenced
+// coordinates. Pixel corners are at integer positions, pixel centers at
+// half-integer ones.
+func (gt GeoTransform) PixelToGeo(pixel, line float64) (x, y float64) {
+	return gt.apply(pixel, line)
+}
+
+// GeoToPixel transforms georeferenced coordinates to a fractional
+// pixel/line position. It fails if the transform is not invertible.
+func (gt GeoTransform) GeoToPixel(x, y float64) (pixel, line float64, err error) {
+	inv, ok := gt.Inverse()
+	if !ok {
+		return 0, 0, fmt.Errorf("geotransform %v is not invertible", [6]float64(gt))
+	}
+	pixel, line = inv.apply(x, y)
+	return pixel, line, nil
+}
+
+func (gt GeoTransform) apply(a, b float64) (float64, float64) {
+	return gt[0] + a*gt[1] + b*gt[2], gt[3] + a*gt[4] + b*gt[5]
+}
+
+// Resolution returns the size of a pixel along its columns and rows, in
+// georeferenced units. Both are positive, including for rotated
+// transforms.
+func (gt GeoTransform) Resolution() (xRes, yRes float64) {
+	return math.Hypot(gt[1], gt[4]), math.Hypot(gt[2], gt[5])
+}
+
+// IsNorthUp reports whether the transform has no rotation and lines go
+// from north to south.
+func (gt GeoTransform) IsNorthUp() bool {
+	return gt[2] == 0 && gt[4] == 0 && gt[5] < 0
+}
+
+// Bounds returns the georeferenced extent of a raster of xSize by ySize
+// pixels, enclosing all four corners if the transform is rotated.
+func (gt GeoTransform) Bounds(xSize, ySize int) Extent {
+	x, y := gt.apply(0, 0)
+	extent := Extent{MinX: x, MinY: y, MaxX: x, MaxY: y}
+	for _, corner := range [][2]float64{{float64(xSize), 0}, {0, float64(ySize)}, {float64(xSize), float64(ySize)}} {
+		x, y := gt.apply(corner[0], corner[1])
+		extent.MinX, extent.MaxX = math.Min(extent.MinX, x), math.Max(extent.MaxX, x)
+		extent.MinY, extent.MaxY = math.Min(extent.MinY, y), math.Max(extent.MaxY, y)
+	}
+	return extent
+}
+
+// windowEpsilon absorbs floating point noise when snapping fractional
+// pixel positions to whole pixels, so bounds on pixel edges don't pull in
+// an extra row or column.
+const windowEpsilon = 1e-8
+
+// WindowForBounds returns the smallest pixel window covering the given
+// georeferenced bounds. The window is not clipped to any raster, and may
+// have negative offsets; use Window.Clip or Dataset.WindowForBounds for
+// that. It fails if the transform is not invertible.
+func (gt GeoTransform) WindowForBounds(minX, minY, maxX, maxY float64) (Window, error) {
+	inv, ok := gt.Inverse()
+	if !ok {
+		return Window{}, fmt.Errorf("geotransform %v is not invertible", [6]float64(gt))
+	}
+	minP, minL := math.Inf(1), math.Inf(1)
+	maxP, maxL := math.Inf(-1), math.Inf(-1)
+	for _, corner := range [][2]float64{{minX, minY}, {maxX, minY}, {minX, maxY}, {maxX, maxY}} {
+		p, l := inv.apply(corner[0], corner[1])
+		minP, maxP = math.Min(minP, p), math.Max(maxP, p)
+		minL, maxL = math.Min(minL, l), math.Max(maxL, l)
+	}
+	xOff := int(math.Floor(minP + windowEpsilon))
+	yOff := int(math.Floor(minL + windowEpsilon))
+	return Window{
+		XOff:  xOff,
+		YOff:  yOff,
+		XSize: int(math.Ceil(maxP-windowEpsilon)) - xOff,
+		YSize: int(math.Ceil(maxL-windowEpsilon)) - yOff,
+	}, nil
+}
+
+// Clip returns the part of the window inside a raster of xSize by ySize
+// pixels, and false if there is none.
+func (win Window) Clip(xSize, ySize int) (Window, bool) {
+	x0, y0 := win.XOff, win.YOff
+	x1, y1 := win.XOff+win.XSize, win.YOff+win.YSize
+	if x0 < 0 {
+		x0 = 0
+	}
+	if y0 < 0 {
+		y0 = 0
+	}
+	if x1 > xSize {
+		x1 = xSize
+	}
+	if y1 > ySize {
+		y1 = ySize
+	}
+	if x1 <= x0 || y1 <= y0 {
+		return Window{}, false
+	}
+	return Window{x0, y0, x1 - x0, y1 - y0}, true
+}
+
+// WindowForBounds returns the pixel window of the dataset covering the
+// given georeferenced bounds, clipped to the raster, ready to be passed
+// to RasterBand.IO or ReadWindow. It fails if the bounds don't intersect
+// the raster.
+func (dataset Dataset) WindowForBounds(minX, minY, maxX, maxY float64) (Window, error) {
+	win, err := dataset.GeoTransform().WindowForBounds(minX, minY, maxX, maxY)
+	if err != nil {
+		return Window{}, err
+	}
+	clipped, ok := win.Clip(dataset.RasterXSize(), dataset.RasterYSize())
+	if !ok {
+		return Window{}, fmt.Errorf("bounds %v, %v, %v, %v are outside of the raster", minX, minY, maxX, maxY)
+	}
+	return clipped, nil
+}
@@ -0,0 +1,73 @@
+package gdal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeoTransform(t *testing.T) {
+	gt := GeoTransform{100, 2, 0, 200, 0, -2}
+	if x, y := gt.PixelToGeo(5, 5); x != 110 || y != 190 {
+		t.Errorf("PixelToGeo: got %v, %v, want 110, 190", x, y)
+	}
+	if p, l, err := gt.GeoToPixel(110, 190); err != nil || p != 5 || l != 5 {
+		t.Errorf("GeoToPixel: got %v, %v, %v, want 5, 5", p, l, err)
+	}
+	if _, _, err := (GeoTransform{}).GeoToPixel(0, 0); err == nil {
+		t.Error("GeoToPixel succeeded on a singular transform")
+	}
+	if xRes, yRes := gt.Resolution(); xRes != 2 || yRes != 2 {
+		t.Errorf("Resolution: got %v, %v, want 2, 2", xRes, yRes)
+	}
+	if !gt.IsNorthUp() {
+		t.Error("IsNorthUp: got false")
+	}
+	if extent := gt.Bounds(10, 20); extent != (Extent{MinX: 100, MinY: 160, MaxX: 120, MaxY: 200}) {
+		t.Errorf("Bounds: got %+v", extent)
+	}
+
+	rotated := GeoTransform{0, math.Sqrt2, math.Sqrt2, 0, math.Sqrt2, -math.Sqrt2}
+	if rotated.IsNorthUp() {
+		t.Error("IsNorthUp: got true for a rotated transform")
+	}
+	if xRes, yRes := rotated.Resolution(); math.Abs(xRes-2) > 1e-9 || math.Abs(yRes-2) > 1e-9 {
+		t.Errorf("Resolution: got %v, %v, want 2, 2", xRes, yRes)
+	}
+
+	for _, tc := range []struct {
+		minX, minY, maxX, maxY float64
+		want                   Window
+	}{
+		{100, 160, 120, 200, Window{0, 0, 10, 20}},
+		{103, 181, 107, 195, Window{1, 2, 3, 8}},
+		{90, 150, 104, 210, Window{-5, -5, 7, 30}},
+	} {
+		win, err := gt.WindowForBounds(tc.minX, tc.minY, tc.maxX, tc.maxY)
+		if err != nil || win != tc.want {
+			t.Errorf("WindowForBounds(%v, %v, %v, %v): got %+v, %v, want %+v", tc.minX, tc.minY, tc.maxX, tc.maxY, win, err, tc.want)
+		}
+	}
+
+	if win, ok := (Window{-5, -5, 7, 30}).Clip(10, 20); !ok || win != (Window{0, 0, 2, 20}) {
+		t.Errorf("Clip: got %+v, %v", win, ok)
+	}
+	if _, ok := (Window{10, 0, 5, 5}).Clip(10, 20); ok {
+		t.Error("Clip: window outside of the raster is not empty")
+	}
+
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("", 10, 20, 1, Byte, nil)
+	defer ds.Close()
+	if err := ds.SetGeoTransform(gt); err != nil {
+		t.Fatal(err)
+	}
+	if win, err := ds.WindowForBounds(90, 150, 104, 210); err != nil || win != (Window{0, 0, 2, 20}) {
+		t.Errorf("Dataset.WindowForBounds: got %+v, %v", win, err)
+	}
+	if _, err := ds.WindowForBounds(0, 0, 10, 10); err == nil {
+		t.Error("Dataset.WindowForBounds succeeded outside of the raster")
+	}
+}
@@ -2,4 +2,4 @@ module github.com/airmap/gdal
 
 replace github.com/airmap/gdal => /home/hibrid/go/src/github.com/airmap/gdal
 
-go 1.14
+go 1.18
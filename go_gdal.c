@@ -7,6 +7,7 @@
 
 #include <cpl_conv.h>
 #include <cpl_error.h>
+#include <gdal_version.h>
 
 static int goGDALProgressFuncProxyB_(
 	double complete, 
@@ -18,6 +19,14 @@ static int goGDALProgressFuncProxyB_(
 	return (int)returnVal;
 }
 
+static int goGDALProgressFuncRegistryProxyB_(
+	double complete,
+	const char *message,
+	void *progressArg
+) {
+	return goGDALProgressFuncRegistryProxyA(complete, (char*)message, (uintptr_t)progressArg);
+}
+
 static void errorHandler(CPLErr err, CPLErrorNum num, const char* s) {
 	cplErrorHandler(err, num, (char*)s);
 }
@@ -26,8 +35,85 @@ GDALProgressFunc goGDALProgressFuncProxyB() {
 	return goGDALProgressFuncProxyB_;
 }
 
+GDALProgressFunc goGDALProgressFuncRegistryProxyB() {
+	return goGDALProgressFuncRegistryProxyB_;
+}
+
+void *goGDALProgressKey(uintptr_t key) {
+	return (void*)key;
+}
+
 CPLErrorHandler goCPLErrorHandlerProxy() {
 	return errorHandler;
 }
 
+static void scopedErrorHandler(CPLErr err, CPLErrorNum num, const char* s) {
+	cplScopedErrorHandler(err, num, (char*)s, (uintptr_t)CPLGetErrorHandlerUserData());
+}
+
+void goCPLPushErrorHandler(uintptr_t key) {
+	CPLPushErrorHandlerEx(scopedErrorHandler, (void*)key);
+}
+
+void goCPLError(CPLErr err, CPLErrorNum num, const char *msg) {
+	CPLError(err, num, "%s", msg);
+}
+
+#define GO_GDAL_PIXEL_FUNC(n) \
+	static CPLErr goGDALPixelFunc##n( \
+		void **papoSources, int nSources, void *pData, \
+		int nBufXSize, int nBufYSize, \
+		GDALDataType eSrcType, GDALDataType eBufType, \
+		int nPixelSpace, int nLineSpace \
+	) { \
+		return goGDALPixelFuncProxy(n, papoSources, nSources, pData, \
+			nBufXSize, nBufYSize, eSrcType, eBufType, nPixelSpace, nLineSpace); \
+	}
+
+GO_GDAL_PIXEL_FUNC(0)  GO_GDAL_PIXEL_FUNC(1)  GO_GDAL_PIXEL_FUNC(2)  GO_GDAL_PIXEL_FUNC(3)
+GO_GDAL_PIXEL_FUNC(4)  GO_GDAL_PIXEL_FUNC(5)  GO_GDAL_PIXEL_FUNC(6)  GO_GDAL_PIXEL_FUNC(7)
+GO_GDAL_PIXEL_FUNC(8)  GO_GDAL_PIXEL_FUNC(9)  GO_GDAL_PIXEL_FUNC(10) GO_GDAL_PIXEL_FUNC(11)
+GO_GDAL_PIXEL_FUNC(12) GO_GDAL_PIXEL_FUNC(13) GO_GDAL_PIXEL_FUNC(14) GO_GDAL_PIXEL_FUNC(15)
+GO_GDAL_PIXEL_FUNC(16) GO_GDAL_PIXEL_FUNC(17) GO_GDAL_PIXEL_FUNC(18) GO_GDAL_PIXEL_FUNC(19)
+GO_GDAL_PIXEL_FUNC(20) GO_GDAL_PIXEL_FUNC(21) GO_GDAL_PIXEL_FUNC(22) GO_GDAL_PIXEL_FUNC(23)
+GO_GDAL_PIXEL_FUNC(24) GO_GDAL_PIXEL_FUNC(25) GO_GDAL_PIXEL_FUNC(26) GO_GDAL_PIXEL_FUNC(27)
+GO_GDAL_PIXEL_FUNC(28) GO_GDAL_PIXEL_FUNC(29) GO_GDAL_PIXEL_FUNC(30) GO_GDAL_PIXEL_FUNC(31)
+
+static GDALDerivedPixelFunc goGDALPixelFuncs[GO_GDAL_PIXEL_FUNC_SLOTS] = {
+	goGDALPixelFunc0,  goGDALPixelFunc1,  goGDALPixelFunc2,  goGDALPixelFunc3,
+	goGDALPixelFunc4,  goGDALPixelFunc5,  goGDALPixelFunc6,  goGDALPixelFunc7,
+	goGDALPixelFunc8,  goGDALPixelFunc9,  goGDALPixelFunc10, goGDALPixelFunc11,
+	goGDALPixelFunc12, goGDALPixelFunc13, goGDALPixelFunc14, goGDALPixelFunc15,
+	goGDALPixelFunc16, goGDALPixelFunc17, goGDALPixelFunc18, goGDALPixelFunc19,
+	goGDALPixelFunc20, goGDALPixelFunc21, goGDALPixelFunc22, goGDALPixelFunc23,
+	goGDALPixelFunc24, goGDALPixelFunc25, goGDALPixelFunc26, goGDALPixelFunc27,
+	goGDALPixelFunc28, goGDALPixelFunc29, goGDALPixelFunc30, goGDALPixelFunc31,
+};
+
+GDALDerivedPixelFunc goGDALPixelFunc(int slot) {
+	if (slot < 0 || slot >= GO_GDAL_PIXEL_FUNC_SLOTS) {
+		return NULL;
+	}
+	return goGDALPixelFuncs[slot];
+}
+
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 7, 0)
+
+char *goGDALVectorInfo(GDALDatasetH hDataset, char **papszArgv) {
+	GDALVectorInfoOptions *psOptions = GDALVectorInfoOptionsNew(papszArgv, NULL);
+	if (psOptions == NULL) {
+		return NULL;
+	}
+	char *pszInfo = GDALVectorInfo(hDataset, psOptions);
+	GDALVectorInfoOptionsFree(psOptions);
+	return pszInfo;
+}
+
+#else
+
+char *goGDALVectorInfo(GDALDatasetH hDataset, char **papszArgv) {
+	CPLError(CE_Failure, CPLE_NotSupported, "GDALVectorInfo() requires gdal 3.7 or later");
+	return NULL;
+}
 
+#endif // GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 7, 0)
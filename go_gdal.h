@@ -5,6 +5,8 @@
 #ifndef GO_GDAL_H_
 #define GO_GDAL_H_
 
+#include <stdint.h>
+
 #include <gdal.h>
 #include <gdal_alg.h>
 #include <gdal_utils.h>
@@ -16,10 +18,37 @@
 // transform GDALProgressFunc to go func
 GDALProgressFunc goGDALProgressFuncProxyB();
 
+// goGDALProgressFuncRegistryProxyB returns a GDALProgressFunc calling the
+// go callback registered under the key passed as progress data.
+GDALProgressFunc goGDALProgressFuncRegistryProxyB();
+
+// goGDALProgressKey converts a registry key to progress data.
+void *goGDALProgressKey(uintptr_t key);
+
 // goCPLErrorHandlerProxy returns a CPLErrorHandler that calls
 // back into Go code.
 CPLErrorHandler goCPLErrorHandlerProxy();
 
+// goCPLPushErrorHandler pushes a thread local error handler calling back
+// into the go error scope registered under key.
+void goCPLPushErrorHandler(uintptr_t key);
+
+// GO_GDAL_PIXEL_FUNC_SLOTS is the number of go functions that can be
+// registered as VRT derived band pixel functions. GDALDerivedPixelFunc
+// carries no user data, so each one needs a static C trampoline.
+#define GO_GDAL_PIXEL_FUNC_SLOTS 32
+
+// goGDALPixelFunc returns the pixel function calling the go function
+// registered in the given slot.
+GDALDerivedPixelFunc goGDALPixelFunc(int slot);
+
+// goCPLError raises an error with a message that is not a format string.
+void goCPLError(CPLErr err, CPLErrorNum num, const char *msg);
+
+// goGDALVectorInfo wraps GDALVectorInfo, which only exists since gdal 3.7.
+// It returns NULL and raises a CPLE_NotSupported error on older versions.
+char *goGDALVectorInfo(GDALDatasetH hDataset, char **papszArgv);
+
 #endif // GO_GDAL_H_
 
 
@@ -0,0 +1,108 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"io"
+	"sync"
+
+	"github.com/airmap/gdal/internal/handle"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Handle ownership                                                */
+/* -------------------------------------------------------------------- */
+
+// TrackHandles turns ownership tracking on or off for the Dataset,
+// RasterBand and ColorTable handles of this package, and the Geometry,
+// Feature and SpatialReference handles of the ogr package, created
+// afterwards.
+//
+// A tracked handle knows whether it owns its GDAL object or borrows it
+// from another one (a band or layer from its dataset, a geometry from its
+// feature). Closing or destroying an owned handle is idempotent across all
+// of its copies, and doing so on a borrowed handle is a no-op. Owned
+// handles that become unreachable without being closed are freed by a
+// finalizer, and a borrowed handle keeps its owner from being finalized.
+//
+// With debugLeaks set, the creation stack of each owned handle is recorded
+// for ReportLeaks.
+func TrackHandles(on, debugLeaks bool) {
+	handle.Track(on, debugLeaks)
+}
+
+// ReportLeaks writes the owned handles created while debugging leaks that
+// are still open, or were only freed by their finalizer, to w. It returns
+// their number, and is meant to be deferred in main or run after tests.
+func ReportLeaks(w io.Writer) int {
+	return handle.Report(w)
+}
+
+// Owned reports whether closing the dataset closes the underlying GDAL
+// dataset. Untracked datasets always report true.
+func (dataset Dataset) Owned() bool {
+	return dataset.ref.Owned()
+}
+
+// Owned reports whether destroying the color table frees it. Untracked
+// color tables always report true.
+func (ct ColorTable) Owned() bool {
+	return ct.ref.Owned()
+}
+
+// ownedDataset wraps a dataset the caller is responsible for closing.
+func ownedDataset(h C.GDALDatasetH) Dataset {
+	if h == nil {
+		return Dataset{}
+	}
+	return Dataset{h, handle.Owned("Dataset", func() { closeDataset(h) })}
+}
+
+// datasetCleanups holds functions to run once a dataset is closed, such as
+// removing the /vsimem file it was opened from.
+var datasetCleanups = struct {
+	sync.Mutex
+	funcs map[C.GDALDatasetH]func()
+}{funcs: make(map[C.GDALDatasetH]func())}
+
+// onDatasetClose arranges for cleanup to run after the dataset is closed.
+func onDatasetClose(h C.GDALDatasetH, cleanup func()) {
+	datasetCleanups.Lock()
+	defer datasetCleanups.Unlock()
+	if previous, ok := datasetCleanups.funcs[h]; ok {
+		datasetCleanups.funcs[h] = func() { cleanup(); previous() }
+		return
+	}
+	datasetCleanups.funcs[h] = cleanup
+}
+
+// closeDataset closes a dataset and runs its cleanup function, if any.
+// The cleanup is taken before closing, as a dataset opened afterwards may
+// get the same handle and register its own.
+func closeDataset(h C.GDALDatasetH) {
+	datasetCleanups.Lock()
+	cleanup, ok := datasetCleanups.funcs[h]
+	delete(datasetCleanups.funcs, h)
+	datasetCleanups.Unlock()
+	C.GDALClose(h)
+	if ok {
+		cleanup()
+	}
+}
+
+// ownedColorTable wraps a color table the caller is responsible for
+// destroying.
+func ownedColorTable(h C.GDALColorTableH) ColorTable {
+	if h == nil {
+		return ColorTable{}
+	}
+	return ColorTable{h, handle.Owned("ColorTable", func() { C.GDALDestroyColorTable(h) })}
+}
@@ -0,0 +1,489 @@
+package gdal
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+)
+
+/* -------------------------------------------------------------------- */
+/*      image.Image adapters                                            */
+/* -------------------------------------------------------------------- */
+
+type imageKind int
+
+const (
+	imageGray imageKind = iota
+	imageGrayAlpha
+	imagePaletted
+	imageRGB
+	imageRGBA
+)
+
+// imageCacheBlocks is the number of blocks an Image keeps in memory.
+const imageCacheBlocks = 64
+
+// Image adapts bands of a dataset to image.Image. Pixels are read lazily,
+// block by block, up to 64 blocks being kept in memory.
+//
+// UInt16 bands map to the 16 bit color models, other bands to the 8 bit
+// ones, their values being clamped to [0, 255]. Read errors can't be
+// returned by At, which returns the zero color instead: check Err once
+// done.
+type Image struct {
+	bands                  []RasterBand
+	kind                   imageKind
+	deep                   bool
+	palette                color.Palette
+	bounds                 image.Rectangle
+	blockXSize, blockYSize int
+
+	mu     sync.Mutex
+	blocks map[image.Point]*imageBlock
+	err    error
+}
+
+type imageBlock struct {
+	win   Window
+	data  [][]uint16
+	dirty bool
+}
+
+// AsImage returns an image of the band: paletted if its color
+// interpretation is CI_PaletteIndex and it has a color table, gray
+// otherwise.
+func (rasterBand RasterBand) AsImage() (*Image, error) {
+	kind := imageGray
+	if rasterBand.ColorInterp() == CI_PaletteIndex {
+		kind = imagePaletted
+	}
+	return newImage([]RasterBand{rasterBand}, kind)
+}
+
+// AsImage returns an image of the dataset. Datasets with red, green and
+// blue bands, and possibly an alpha band, map to RGB or RGBA images, found
+// by their color interpretation. Other datasets map to an image of their
+// first band, as RasterBand.AsImage does, with the alpha band if any.
+func (dataset Dataset) AsImage() (*Image, error) {
+	count := dataset.RasterCount()
+	if count == 0 {
+		return nil, fmt.Errorf("dataset has no raster bands")
+	}
+	byInterp := map[ColorInterp]RasterBand{}
+	for i := count; i >= 1; i-- {
+		band := dataset.RasterBand(i)
+		byInterp[band.ColorInterp()] = band
+	}
+	red, hasRed := byInterp[CI_RedBand]
+	green, hasGreen := byInterp[CI_GreenBand]
+	blue, hasBlue := byInterp[CI_BlueBand]
+	alpha, hasAlpha := byInterp[CI_AlphaBand]
+	switch {
+	case hasRed && hasGreen && hasBlue && hasAlpha:
+		return newImage([]RasterBand{red, green, blue, alpha}, imageRGBA)
+	case hasRed && hasGreen && hasBlue:
+		return newImage([]RasterBand{red, green, blue}, imageRGB)
+	case hasAlpha && dataset.RasterBand(1).ColorInterp() != CI_AlphaBand:
+		return newImage([]RasterBand{dataset.RasterBand(1), alpha}, imageGrayAlpha)
+	}
+	return dataset.RasterBand(1).AsImage()
+}
+
+func newImage(bands []RasterBand, kind imageKind) (*Image, error) {
+	img := &Image{
+		bands:  bands,
+		kind:   kind,
+		deep:   true,
+		bounds: image.Rect(0, 0, bands[0].XSize(), bands[0].YSize()),
+		blocks: map[image.Point]*imageBlock{},
+	}
+	img.blockXSize, img.blockYSize = bands[0].BlockSize()
+	if img.blockXSize <= 0 || img.blockYSize <= 0 {
+		img.blockXSize, img.blockYSize = img.bounds.Dx(), 1
+	}
+	for _, band := range bands {
+		if band.XSize() != img.bounds.Dx() || band.YSize() != img.bounds.Dy() {
+			return nil, fmt.Errorf("bands of different sizes")
+		}
+		img.deep = img.deep && band.RasterDataType() == UInt16
+	}
+	if kind == imagePaletted {
+		ct := bands[0].ColorTable()
+		if ct.cval == nil {
+			img.kind = imageGray
+			return img, nil
+		}
+		for i := 0; i < ct.EntryCount(); i++ {
+			entry, _ := ct.EntryAsRGB(i)
+			r, g, b, a := entry.Get()
+			img.palette = append(img.palette, color.NRGBA{R: r, G: g, B: b, A: a})
+		}
+	}
+	return img, nil
+}
+
+// ColorModel returns the color model of the image.
+func (img *Image) ColorModel() color.Model {
+	switch img.kind {
+	case imagePaletted:
+		return img.palette
+	case imageRGB:
+		if img.deep {
+			return color.RGBA64Model
+		}
+		return color.RGBAModel
+	case imageRGBA, imageGrayAlpha:
+		if img.deep {
+			return color.NRGBA64Model
+		}
+		return color.NRGBAModel
+	}
+	if img.deep {
+		return color.Gray16Model
+	}
+	return color.GrayModel
+}
+
+// Bounds returns the bounds of the image, from (0, 0) to the raster size.
+func (img *Image) Bounds() image.Rectangle {
+	return img.bounds
+}
+
+// At returns the color of the pixel at (x, y).
+func (img *Image) At(x, y int) color.Color {
+	v := img.pixel(x, y)
+	s := func(i int) uint8 {
+		if v[i] > 255 {
+			return 255
+		}
+		return uint8(v[i])
+	}
+	switch img.kind {
+	case imagePaletted:
+		if int(v[0]) < len(img.palette) {
+			return img.palette[v[0]]
+		}
+		return color.Transparent
+	case imageRGB:
+		if img.deep {
+			return color.RGBA64{R: v[0], G: v[1], B: v[2], A: 0xffff}
+		}
+		return color.RGBA{R: s(0), G: s(1), B: s(2), A: 0xff}
+	case imageRGBA:
+		if img.deep {
+			return color.NRGBA64{R: v[0], G: v[1], B: v[2], A: v[3]}
+		}
+		return color.NRGBA{R: s(0), G: s(1), B: s(2), A: s(3)}
+	case imageGrayAlpha:
+		if img.deep {
+			return color.NRGBA64{R: v[0], G: v[0], B: v[0], A: v[1]}
+		}
+		return color.NRGBA{R: s(0), G: s(0), B: s(0), A: s(1)}
+	}
+	if img.deep {
+		return color.Gray16{Y: v[0]}
+	}
+	return color.Gray{Y: s(0)}
+}
+
+// ColorIndexAt returns the palette index of the pixel at (x, y), for
+// paletted images.
+func (img *Image) ColorIndexAt(x, y int) uint8 {
+	return uint8(img.pixel(x, y)[0])
+}
+
+// Err returns the first error met reading or writing the raster.
+func (img *Image) Err() error {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+	return img.err
+}
+
+// pixel returns the band values at (x, y), zero outside the image.
+func (img *Image) pixel(x, y int) (v [4]uint16) {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+	block, i := img.block(x, y)
+	if block != nil {
+		for b := range img.bands {
+			v[b] = block.data[b][i]
+		}
+	}
+	return v
+}
+
+// block returns the block holding (x, y), loading it if needed, and the
+// index of the pixel in it. img.mu must be held.
+func (img *Image) block(x, y int) (*imageBlock, int) {
+	if !image.Pt(x, y).In(img.bounds) {
+		return nil, 0
+	}
+	key := image.Pt(x/img.blockXSize, y/img.blockYSize)
+	block, ok := img.blocks[key]
+	if !ok {
+		if len(img.blocks) >= imageCacheBlocks {
+			for k, b := range img.blocks {
+				img.flushBlock(b)
+				delete(img.blocks, k)
+				break
+			}
+		}
+		win, _ := Window{
+			XOff: key.X * img.blockXSize, YOff: key.Y * img.blockYSize,
+			XSize: img.blockXSize, YSize: img.blockYSize,
+		}.Clip(img.bounds.Dx(), img.bounds.Dy())
+		block = &imageBlock{win: win, data: make([][]uint16, len(img.bands))}
+		for b, band := range img.bands {
+			data, err := ReadWindow[uint16](band, win)
+			if err != nil {
+				if img.err == nil {
+					img.err = err
+				}
+				return nil, 0
+			}
+			block.data[b] = data
+		}
+		img.blocks[key] = block
+	}
+	return block, (y-block.win.YOff)*block.win.XSize + x - block.win.XOff
+}
+
+// flushBlock writes block back if it was modified. img.mu must be held.
+func (img *Image) flushBlock(block *imageBlock) {
+	if !block.dirty {
+		return
+	}
+	for b, band := range img.bands {
+		if err := WriteWindow(band, block.win, block.data[b]); err != nil && img.err == nil {
+			img.err = err
+		}
+	}
+	block.dirty = false
+}
+
+// DrawImage is an Image which can be drawn on, implementing draw.Image.
+// Pixels are written to the cached blocks, which are written to the bands
+// when evicted from the cache or by Flush.
+type DrawImage struct {
+	*Image
+}
+
+// AsDrawImage returns a drawable image of the band, see AsImage.
+func (rasterBand RasterBand) AsDrawImage() (*DrawImage, error) {
+	img, err := rasterBand.AsImage()
+	if err != nil {
+		return nil, err
+	}
+	return &DrawImage{img}, nil
+}
+
+// AsDrawImage returns a drawable image of the dataset, see AsImage.
+func (dataset Dataset) AsDrawImage() (*DrawImage, error) {
+	img, err := dataset.AsImage()
+	if err != nil {
+		return nil, err
+	}
+	return &DrawImage{img}, nil
+}
+
+// Set sets the color of the pixel at (x, y), converted to the color model
+// of the image.
+func (img *DrawImage) Set(x, y int, c color.Color) {
+	v := img.values(c)
+	img.mu.Lock()
+	defer img.mu.Unlock()
+	block, i := img.block(x, y)
+	if block == nil {
+		return
+	}
+	for b := range img.bands {
+		block.data[b][i] = v[b]
+	}
+	block.dirty = true
+}
+
+// Flush writes the modified pixels to the bands, and returns the first
+// error met reading or writing them.
+func (img *DrawImage) Flush() error {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+	for _, block := range img.blocks {
+		img.flushBlock(block)
+	}
+	return img.err
+}
+
+// values converts c to band values, with the 8 bit color models unless the
+// image is deep so that 8 bit colors are kept as is.
+func (img *Image) values(c color.Color) (v [4]uint16) {
+	if img.kind == imagePaletted {
+		v[0] = uint16(img.palette.Index(c))
+		return v
+	}
+	if img.deep {
+		switch img.kind {
+		case imageRGB:
+			rgba := color.RGBA64Model.Convert(c).(color.RGBA64)
+			v = [4]uint16{rgba.R, rgba.G, rgba.B}
+		case imageRGBA:
+			n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+			v = [4]uint16{n.R, n.G, n.B, n.A}
+		case imageGrayAlpha:
+			n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+			gray := color.Gray16Model.Convert(color.NRGBA64{R: n.R, G: n.G, B: n.B, A: 0xffff}).(color.Gray16)
+			v = [4]uint16{gray.Y, n.A}
+		default:
+			v[0] = color.Gray16Model.Convert(c).(color.Gray16).Y
+		}
+		return v
+	}
+	switch img.kind {
+	case imageRGB:
+		rgba := color.RGBAModel.Convert(c).(color.RGBA)
+		v = [4]uint16{uint16(rgba.R), uint16(rgba.G), uint16(rgba.B)}
+	case imageRGBA:
+		n := color.NRGBAModel.Convert(c).(color.NRGBA)
+		v = [4]uint16{uint16(n.R), uint16(n.G), uint16(n.B), uint16(n.A)}
+	case imageGrayAlpha:
+		n := color.NRGBAModel.Convert(c).(color.NRGBA)
+		gray := color.GrayModel.Convert(color.NRGBA{R: n.R, G: n.G, B: n.B, A: 0xff}).(color.Gray)
+		v = [4]uint16{uint16(gray.Y), uint16(n.A)}
+	default:
+		v[0] = uint16(color.GrayModel.Convert(c).(color.Gray).Y)
+	}
+	return v
+}
+
+// NewDatasetFromImage creates a dataset with driver from img: one gray or
+// paletted band for image.Gray, image.Gray16 and image.Paletted, RGB bands
+// for opaque images and RGBA bands otherwise, of type UInt16 for 16 bit
+// images and Byte for the others. Drivers which can't create datasets
+// from scratch, such as PNG or JPEG, are given a copy of an in-memory one.
+func NewDatasetFromImage(img image.Image, driver Driver, name string) (Dataset, error) {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return Dataset{}, fmt.Errorf("empty image")
+	}
+	layout := &Image{kind: imageRGBA, bounds: bounds}
+	switch m := img.(type) {
+	case *image.Gray:
+		layout.kind = imageGray
+	case *image.Gray16:
+		layout.kind, layout.deep = imageGray, true
+	case *image.Paletted:
+		layout.kind, layout.palette = imagePaletted, m.Palette
+	case *image.RGBA64, *image.NRGBA64:
+		layout.deep = true
+	}
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && layout.kind == imageRGBA && opaque.Opaque() {
+		layout.kind = imageRGB
+	}
+	bandCount := map[imageKind]int{imageGray: 1, imagePaletted: 1, imageRGB: 3, imageRGBA: 4}[layout.kind]
+	dataType := Byte
+	if layout.deep {
+		dataType = UInt16
+	}
+
+	target, direct := driver, driver.MetadataItem(DCAP_CREATE, "") == "YES"
+	targetName := name
+	if !direct {
+		var err error
+		if target, err = GetDriverByName("MEM"); err != nil {
+			return Dataset{}, err
+		}
+		targetName = ""
+	}
+	dataset := target.Create(targetName, bounds.Dx(), bounds.Dy(), bandCount, dataType, nil)
+	if dataset.cval == nil {
+		return Dataset{}, fmt.Errorf("cannot create '%s' with %s", name, target.ShortName())
+	}
+	if err := writeImage(dataset, layout, img); err != nil {
+		dataset.Close()
+		return Dataset{}, err
+	}
+	if direct {
+		return dataset, nil
+	}
+	defer dataset.Close()
+	copied := driver.CreateCopy(name, dataset, 0, nil, nil, nil)
+	if copied.cval == nil {
+		return Dataset{}, fmt.Errorf("cannot create '%s' with %s", name, driver.ShortName())
+	}
+	return copied, nil
+}
+
+// writeImage writes img to the bands of dataset, laid out as layout.
+func writeImage(dataset Dataset, layout *Image, img image.Image) error {
+	interps := map[imageKind][]ColorInterp{
+		imageGray:     {CI_GrayIndex},
+		imagePaletted: {CI_PaletteIndex},
+		imageRGB:      {CI_RedBand, CI_GreenBand, CI_BlueBand},
+		imageRGBA:     {CI_RedBand, CI_GreenBand, CI_BlueBand, CI_AlphaBand},
+	}[layout.kind]
+	for i, interp := range interps {
+		if err := dataset.RasterBand(i + 1).SetColorInterp(interp); err != nil {
+			return err
+		}
+	}
+	if layout.kind == imagePaletted {
+		ct := CreateColorTable(PI_RGB)
+		defer ct.Destroy()
+		for i, c := range layout.palette {
+			n := color.NRGBAModel.Convert(c).(color.NRGBA)
+			var entry ColorEntry
+			entry.Set(uint(n.R), uint(n.G), uint(n.B), uint(n.A))
+			ct.SetEntry(i, entry)
+		}
+		if err := dataset.RasterBand(1).SetColorTable(ct); err != nil {
+			return err
+		}
+	}
+
+	bounds := img.Bounds()
+	data := make([][]uint16, len(interps))
+	for b := range data {
+		data[b] = make([]uint16, bounds.Dx()*bounds.Dy())
+	}
+	// The pixels of the common 8 bit images are copied as is, their
+	// channels being in band order.
+	var pix []uint8
+	var pixOffset func(x, y int) int
+	switch m := img.(type) {
+	case *image.Paletted:
+		pix, pixOffset = m.Pix, m.PixOffset
+	case *image.Gray:
+		pix, pixOffset = m.Pix, m.PixOffset
+	case *image.NRGBA:
+		pix, pixOffset = m.Pix, m.PixOffset
+	case *image.RGBA:
+		// Premultiplied colors only match the bands when opaque.
+		if layout.kind == imageRGB {
+			pix, pixOffset = m.Pix, m.PixOffset
+		}
+	}
+	i := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if pix != nil {
+				j := pixOffset(x, y)
+				for b := range data {
+					data[b][i] = uint16(pix[j+b])
+				}
+			} else {
+				v := layout.values(img.At(x, y))
+				for b := range data {
+					data[b][i] = v[b]
+				}
+			}
+			i++
+		}
+	}
+	win := Window{XSize: bounds.Dx(), YSize: bounds.Dy()}
+	for b := range data {
+		if err := WriteWindow(dataset.RasterBand(b+1), win, data[b]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
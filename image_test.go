@@ -0,0 +1,156 @@
+package gdal
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"testing"
+)
+
+func TestDatasetAsImage(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("", 3, 2, 3, Byte, nil)
+	defer ds.Close()
+	win := Window{XSize: 3, YSize: 2}
+	for i, interp := range []ColorInterp{CI_RedBand, CI_GreenBand, CI_BlueBand} {
+		band := ds.RasterBand(i + 1)
+		if err := band.SetColorInterp(interp); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteWindow(band, win, []uint8{0, 1, 2, 3, 4, uint8(10 * (i + 1))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	img, err := ds.AsImage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 3, 2) || img.ColorModel() != color.RGBAModel {
+		t.Fatalf("bounds %v, model %v", img.Bounds(), img.ColorModel())
+	}
+	if c := img.At(2, 1); c != (color.RGBA{R: 10, G: 20, B: 30, A: 255}) {
+		t.Errorf("At(2, 1) = %v", c)
+	}
+	if c := img.At(5, 5); c != (color.RGBA{}) {
+		t.Errorf("At outside the image = %v", c)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	if err := img.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var dst draw.Image
+	if dst, err = ds.AsDrawImage(); err != nil {
+		t.Fatal(err)
+	}
+	draw.Draw(dst, image.Rect(0, 0, 1, 2), image.NewUniform(color.RGBA{R: 200, G: 100, B: 50, A: 255}), image.Point{}, draw.Src)
+	if err := dst.(*DrawImage).Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	red, err := ReadWindow[uint8](ds.RasterBand(1), win)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint8{200, 1, 2, 200, 4, 10}; !bytes.Equal(red, want) {
+		t.Errorf("red band = %v, want %v", red, want)
+	}
+}
+
+func TestRasterBandAsImage(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("", 2, 1, 1, UInt16, nil)
+	defer ds.Close()
+	band := ds.RasterBand(1)
+	if err := WriteWindow(band, Window{XSize: 2, YSize: 1}, []uint16{7, 60000}); err != nil {
+		t.Fatal(err)
+	}
+	img, err := band.AsImage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.ColorModel() != color.Gray16Model || img.At(1, 0) != (color.Gray16{Y: 60000}) {
+		t.Errorf("model %v, At(1, 0) = %v", img.ColorModel(), img.At(1, 0))
+	}
+}
+
+func TestNewDatasetFromImage(t *testing.T) {
+	palette := color.Palette{color.NRGBA{R: 255, A: 255}, color.NRGBA{B: 255, A: 128}}
+	paletted := image.NewPaletted(image.Rect(0, 0, 2, 2), palette)
+	paletted.SetColorIndex(1, 1, 1)
+	mem, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := NewDatasetFromImage(paletted, mem, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+	if ds.RasterCount() != 1 || ds.RasterBand(1).ColorInterp() != CI_PaletteIndex {
+		t.Fatalf("%d bands, color interpretation %v", ds.RasterCount(), ds.RasterBand(1).ColorInterp())
+	}
+	img, err := ds.AsImage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := img.At(1, 1); c != palette[1] {
+		t.Errorf("At(1, 1) = %v, want %v", c, palette[1])
+	}
+
+	rgba := image.NewNRGBA(image.Rect(0, 0, 4, 3))
+	rgba.SetNRGBA(3, 2, color.NRGBA{R: 1, G: 2, B: 3, A: 4})
+	pngDriver, err := GetDriverByName("PNG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const filename = "/vsimem/image_test.png"
+	ds, err = NewDatasetFromImage(rgba, pngDriver, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer VSIUnlink(filename)
+	defer ds.Close()
+	if ds.RasterCount() != 4 || ds.RasterXSize() != 4 || ds.RasterYSize() != 3 {
+		t.Fatalf("%d bands of %dx%d", ds.RasterCount(), ds.RasterXSize(), ds.RasterYSize())
+	}
+	if img, err = ds.AsImage(); err != nil {
+		t.Fatal(err)
+	}
+	if c := img.At(3, 2); c != (color.NRGBA{R: 1, G: 2, B: 3, A: 4}) {
+		t.Errorf("At(3, 2) = %v", c)
+	}
+
+	// Translucent 8 bit colors are drawn without loss of precision.
+	ds, err = NewDatasetFromImage(rgba, mem, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+	dst, err := ds.AsDrawImage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	translucent := color.NRGBA{R: 5, G: 6, B: 7, A: 8}
+	dst.Set(0, 0, translucent)
+	if err := dst.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if img, err = ds.AsImage(); err != nil {
+		t.Fatal(err)
+	}
+	if c := img.At(0, 0); c != translucent {
+		t.Errorf("At(0, 0) = %v, want %v", c, translucent)
+	}
+}
@@ -0,0 +1,316 @@
+package gdal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/* --------------------------------------------- */
+/* Structured gdalinfo / ogrinfo reports         */
+/* --------------------------------------------- */
+
+// DatasetInfo is the decoded form of the gdalinfo -json report.
+type DatasetInfo struct {
+	Description       string                `json:"description"`
+	DriverShortName   string                `json:"driverShortName"`
+	DriverLongName    string                `json:"driverLongName"`
+	Files             []string              `json:"files"`
+	Size              [2]int                `json:"size"`
+	CoordinateSystem  *CoordinateSystemInfo `json:"coordinateSystem"`
+	GeoTransform      *GeoTransform         `json:"geoTransform"`
+	Metadata          MetadataDomains       `json:"metadata"`
+	CornerCoordinates *CornerCoordinates    `json:"cornerCoordinates"`
+	WGS84Extent       *GeoJSONPolygon       `json:"wgs84Extent"`
+	Bands             []BandInfo            `json:"bands"`
+}
+
+// CoordinateSystemInfo describes a spatial reference in a report.
+type CoordinateSystemInfo struct {
+	WKT                      string `json:"wkt"`
+	DataAxisToSRSAxisMapping []int  `json:"dataAxisToSRSAxisMapping"`
+}
+
+// CornerCoordinates are the georeferenced corners and center of a raster.
+type CornerCoordinates struct {
+	UpperLeft  [2]float64 `json:"upperLeft"`
+	LowerLeft  [2]float64 `json:"lowerLeft"`
+	UpperRight [2]float64 `json:"upperRight"`
+	LowerRight [2]float64 `json:"lowerRight"`
+	Center     [2]float64 `json:"center"`
+}
+
+// GeoJSONPolygon is a GeoJSON polygon geometry.
+type GeoJSONPolygon struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+// Extent returns the bounding box of the polygon's exterior ring.
+func (polygon GeoJSONPolygon) Extent() Extent {
+	var extent Extent
+	for i, pt := range polygon.exteriorRing() {
+		if i == 0 || pt[0] < extent.MinX {
+			extent.MinX = pt[0]
+		}
+		if i == 0 || pt[0] > extent.MaxX {
+			extent.MaxX = pt[0]
+		}
+		if i == 0 || pt[1] < extent.MinY {
+			extent.MinY = pt[1]
+		}
+		if i == 0 || pt[1] > extent.MaxY {
+			extent.MaxY = pt[1]
+		}
+	}
+	return extent
+}
+
+func (polygon GeoJSONPolygon) exteriorRing() [][2]float64 {
+	if len(polygon.Coordinates) == 0 {
+		return nil
+	}
+	return polygon.Coordinates[0]
+}
+
+// BandInfo describes a raster band in a gdalinfo report. Optional values
+// are nil when gdalinfo did not report them; statistics are only present
+// when they were requested (-stats, -mm) or already known.
+type BandInfo struct {
+	Band                int             `json:"band"`
+	Block               [2]int          `json:"block"`
+	Type                string          `json:"type"`
+	Description         string          `json:"description"`
+	ColorInterpretation string          `json:"colorInterpretation"`
+	NoDataValue         *float64        `json:"-"`
+	Offset              *float64        `json:"offset"`
+	Scale               *float64        `json:"scale"`
+	Unit                string          `json:"unit"`
+	Minimum             *float64        `json:"-"`
+	Maximum             *float64        `json:"-"`
+	Mean                *float64        `json:"-"`
+	StdDev              *float64        `json:"-"`
+	ComputedMin         *float64        `json:"-"`
+	ComputedMax         *float64        `json:"-"`
+	Checksum            *int            `json:"checksum"`
+	Overviews           []OverviewInfo  `json:"overviews"`
+	Mask                *MaskInfo       `json:"mask"`
+	Metadata            MetadataDomains `json:"metadata"`
+	Histogram           *HistogramInfo  `json:"histogram"`
+	ColorTable          *ColorTableInfo `json:"colorTable"`
+}
+
+// UnmarshalJSON decodes a band, accepting the strings gdalinfo uses for
+// non-finite values ("nan", "inf", ...) in place of numbers.
+func (band *BandInfo) UnmarshalJSON(data []byte) error {
+	type plainBandInfo BandInfo
+	aux := struct {
+		*plainBandInfo
+		NoDataValue *jsonFloat `json:"noDataValue"`
+		Minimum     *jsonFloat `json:"minimum"`
+		Maximum     *jsonFloat `json:"maximum"`
+		Mean        *jsonFloat `json:"mean"`
+		StdDev      *jsonFloat `json:"stdDev"`
+		ComputedMin *jsonFloat `json:"computedMin"`
+		ComputedMax *jsonFloat `json:"computedMax"`
+	}{plainBandInfo: (*plainBandInfo)(band)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	band.NoDataValue = aux.NoDataValue.ptr()
+	band.Minimum = aux.Minimum.ptr()
+	band.Maximum = aux.Maximum.ptr()
+	band.Mean = aux.Mean.ptr()
+	band.StdDev = aux.StdDev.ptr()
+	band.ComputedMin = aux.ComputedMin.ptr()
+	band.ComputedMax = aux.ComputedMax.ptr()
+	return nil
+}
+
+// OverviewInfo describes one overview level of a band.
+type OverviewInfo struct {
+	Size     [2]int `json:"size"`
+	Checksum *int   `json:"checksum"`
+}
+
+// MaskInfo describes the mask of a band.
+type MaskInfo struct {
+	Flags     []string       `json:"flags"`
+	Overviews []OverviewInfo `json:"overviews"`
+}
+
+// HistogramInfo is a band histogram.
+type HistogramInfo struct {
+	Count   int     `json:"count"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Buckets []int   `json:"buckets"`
+}
+
+// ColorTableInfo is a band color table.
+type ColorTableInfo struct {
+	Palette string   `json:"palette"`
+	Count   int      `json:"count"`
+	Entries [][4]int `json:"entries"`
+}
+
+// MetadataDomains maps metadata domain names ("" for the default domain)
+// to their key/value items. The items of list valued domains, such as the
+// xml: domains, are joined by newlines under the "" key.
+type MetadataDomains map[string]map[string]string
+
+// UnmarshalJSON decodes the metadata domains of a report.
+func (md *MetadataDomains) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*md = make(MetadataDomains, len(raw))
+	for domain, value := range raw {
+		var items map[string]interface{}
+		if err := json.Unmarshal(value, &items); err == nil {
+			(*md)[domain] = make(map[string]string, len(items))
+			for key, item := range items {
+				(*md)[domain][key] = metadataString(item)
+			}
+			continue
+		}
+		var lines []string
+		if err := json.Unmarshal(value, &lines); err != nil {
+			return fmt.Errorf("metadata domain %q: %v", domain, err)
+		}
+		(*md)[domain] = map[string]string{"": strings.Join(lines, "\n")}
+	}
+	return nil
+}
+
+func metadataString(item interface{}) string {
+	switch v := item.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// jsonFloat is a number that may also be encoded as one of the strings
+// GDAL uses for non-finite values.
+type jsonFloat float64
+
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		val, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		*f = jsonFloat(val)
+		return nil
+	}
+	var val float64
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	*f = jsonFloat(val)
+	return nil
+}
+
+func (f *jsonFloat) ptr() *float64 {
+	if f == nil {
+		return nil
+	}
+	val := float64(*f)
+	return &val
+}
+
+// UnmarshalJSON decodes an extent from its [minX, minY, maxX, maxY] form.
+func (extent *Extent) UnmarshalJSON(data []byte) error {
+	var bounds []float64
+	if err := json.Unmarshal(data, &bounds); err != nil {
+		return err
+	}
+	if len(bounds) != 4 {
+		return fmt.Errorf("extent has %d values, want 4", len(bounds))
+	}
+	*extent = Extent{MinX: bounds[0], MinY: bounds[1], MaxX: bounds[2], MaxY: bounds[3]}
+	return nil
+}
+
+// InfoJSON runs gdalinfo -json on sourceDS and decodes the report. options
+// are passed to gdalinfo, e.g. "-stats" or "-hist".
+func InfoJSON(sourceDS Dataset, options []string) (*DatasetInfo, error) {
+	if !stringArrayContains(options, "-json") {
+		options = append([]string{"-json"}, options...)
+	}
+	report := Info(sourceDS, options)
+	if report == "" {
+		return nil, fmt.Errorf("gdalinfo returned no report")
+	}
+	info := &DatasetInfo{}
+	if err := json.Unmarshal([]byte(report), info); err != nil {
+		return nil, fmt.Errorf("decoding gdalinfo report: %v", err)
+	}
+	return info, nil
+}
+
+// VectorDatasetInfo is the decoded form of the ogrinfo -json report.
+type VectorDatasetInfo struct {
+	Description     string          `json:"description"`
+	DriverShortName string          `json:"driverShortName"`
+	DriverLongName  string          `json:"driverLongName"`
+	Metadata        MetadataDomains `json:"metadata"`
+	Layers          []LayerInfo     `json:"layers"`
+}
+
+// LayerInfo describes a vector layer in an ogrinfo report.
+type LayerInfo struct {
+	Name           string              `json:"name"`
+	Metadata       MetadataDomains     `json:"metadata"`
+	GeometryFields []GeometryFieldInfo `json:"geometryFields"`
+	FeatureCount   *int64              `json:"featureCount"`
+	FIDColumnName  string              `json:"fidColumnName"`
+	Fields         []FieldInfo         `json:"fields"`
+}
+
+// GeometryFieldInfo describes a geometry field of a layer.
+type GeometryFieldInfo struct {
+	Name             string                `json:"name"`
+	Type             string                `json:"type"`
+	Nullable         bool                  `json:"nullable"`
+	Extent           *Extent               `json:"extent"`
+	CoordinateSystem *CoordinateSystemInfo `json:"coordinateSystem"`
+}
+
+// FieldInfo describes an attribute field of a layer.
+type FieldInfo struct {
+	Name             string `json:"name"`
+	Type             string `json:"type"`
+	SubType          string `json:"subType"`
+	Width            int    `json:"width"`
+	Precision        int    `json:"precision"`
+	Nullable         bool   `json:"nullable"`
+	UniqueConstraint bool   `json:"uniqueConstraint"`
+	Alias            string `json:"alias"`
+	DefaultValue     string `json:"defaultValue"`
+}
+
+// VectorInfoJSON runs ogrinfo -json on sourceDS and decodes the report.
+// It requires GDAL 3.7 or later.
+func VectorInfoJSON(sourceDS Dataset, options []string) (*VectorDatasetInfo, error) {
+	if !stringArrayContains(options, "-json") {
+		options = append([]string{"-json"}, options...)
+	}
+	report, err := VectorInfo(sourceDS, options)
+	if err != nil {
+		return nil, err
+	}
+	info := &VectorDatasetInfo{}
+	if err := json.Unmarshal([]byte(report), info); err != nil {
+		return nil, fmt.Errorf("decoding ogrinfo report: %v", err)
+	}
+	return info, nil
+}
@@ -0,0 +1,86 @@
+package gdal
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+const sampleInfoReport = `{
+  "description": "dem.tif",
+  "driverShortName": "GTiff",
+  "driverLongName": "GeoTIFF",
+  "files": ["dem.tif"],
+  "size": [512, 256],
+  "coordinateSystem": {"wkt": "GEOGCRS[\"WGS 84\"]", "dataAxisToSRSAxisMapping": [2, 1]},
+  "geoTransform": [10, 0.5, 0, 50, 0, -0.5],
+  "metadata": {
+    "": {"AREA_OR_POINT": "Area"},
+    "IMAGE_STRUCTURE": {"INTERLEAVE": "BAND"},
+    "xml:XMP": ["<x:xmpmeta/>"]
+  },
+  "cornerCoordinates": {
+    "upperLeft": [10, 50], "lowerLeft": [10, -78],
+    "lowerRight": [266, -78], "upperRight": [266, 50], "center": [138, -14]
+  },
+  "wgs84Extent": {"type": "Polygon", "coordinates": [[[10, 50], [10, -78], [266, -78], [266, 50], [10, 50]]]},
+  "bands": [{
+    "band": 1,
+    "block": [512, 16],
+    "type": "Float32",
+    "colorInterpretation": "Gray",
+    "noDataValue": "nan",
+    "minimum": 1.5, "maximum": 8848, "mean": 412.25, "stdDev": 11,
+    "overviews": [{"size": [256, 128]}, {"size": [128, 64]}],
+    "mask": {"flags": ["NODATA"]},
+    "metadata": {"": {"STATISTICS_VALID_PERCENT": "97.5"}}
+  }]
+}`
+
+func TestDecodeInfoReport(t *testing.T) {
+	var info DatasetInfo
+	if err := json.Unmarshal([]byte(sampleInfoReport), &info); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if info.Size != [2]int{512, 256} || info.GeoTransform == nil || info.GeoTransform[1] != 0.5 {
+		t.Errorf("got size %v and geotransform %v", info.Size, info.GeoTransform)
+	}
+	if info.Metadata["IMAGE_STRUCTURE"]["INTERLEAVE"] != "BAND" || info.Metadata["xml:XMP"][""] != "<x:xmpmeta/>" {
+		t.Errorf("got metadata %v", info.Metadata)
+	}
+	if extent := info.WGS84Extent.Extent(); extent != (Extent{MinX: 10, MinY: -78, MaxX: 266, MaxY: 50}) {
+		t.Errorf("got wgs84 extent %+v", extent)
+	}
+	if len(info.Bands) != 1 {
+		t.Fatalf("got %d bands, want 1", len(info.Bands))
+	}
+	band := info.Bands[0]
+	if band.NoDataValue == nil || !math.IsNaN(*band.NoDataValue) {
+		t.Errorf("got nodata %v, want NaN", band.NoDataValue)
+	}
+	if band.Maximum == nil || *band.Maximum != 8848 || band.ComputedMin != nil {
+		t.Errorf("got maximum %v and computed min %v", band.Maximum, band.ComputedMin)
+	}
+	if len(band.Overviews) != 2 || band.Overviews[1].Size != [2]int{128, 64} {
+		t.Errorf("got overviews %v", band.Overviews)
+	}
+}
+
+func TestInfoJSON(t *testing.T) {
+	ds, err := Open("testdata/tiles.gpkg", ReadOnly)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer ds.Close()
+
+	info, err := InfoJSON(ds, nil)
+	if err != nil {
+		t.Fatalf("InfoJSON: %v", err)
+	}
+	if info.DriverShortName != "GPKG" {
+		t.Errorf("got driver %q, want GPKG", info.DriverShortName)
+	}
+	if info.Size != [2]int{ds.RasterXSize(), ds.RasterYSize()} || len(info.Bands) != ds.RasterCount() {
+		t.Errorf("got size %v with %d bands", info.Size, len(info.Bands))
+	}
+}
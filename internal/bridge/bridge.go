@@ -0,0 +1,13 @@
+// Package bridge lets the gdal package build ogr values from state that
+// neither package exports, such as the *handle.Ref of a dataset.
+package bridge
+
+import (
+	"unsafe"
+
+	"github.com/airmap/gdal/internal/handle"
+)
+
+// Layer wraps an OGRLayerH owned by the dataset whose Ref is owner into an
+// ogr.Layer. It is set by package ogr when initialized.
+var Layer func(h unsafe.Pointer, owner *handle.Ref) interface{}
@@ -0,0 +1,225 @@
+// Package handle implements the opt-in ownership tracking shared by the
+// handle types of the gdal and ogr packages.
+//
+// A tracked handle value carries a *Ref. All copies of the value share it,
+// which makes closing idempotent, and the Ref remembers whether the Go side
+// owns the C object or merely borrows it from another object. Owned Refs
+// get a finalizer that frees the C object if it is never closed, and
+// borrowed Refs keep their owner reachable for as long as they are.
+package handle
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	tracking  int32
+	debugging int32
+)
+
+// Track turns ownership tracking on or off for handles created afterwards.
+// With debugLeaks set, the creation stack of every owned handle is kept for
+// Leaks.
+func Track(on, debugLeaks bool) {
+	var t, d int32
+	if on {
+		t = 1
+		if debugLeaks {
+			d = 1
+		}
+	}
+	atomic.StoreInt32(&tracking, t)
+	atomic.StoreInt32(&debugging, d)
+}
+
+// Tracking reports whether handles created now are tracked.
+func Tracking() bool {
+	return atomic.LoadInt32(&tracking) != 0
+}
+
+// Ref is the ownership state of one C object. A nil *Ref stands for an
+// untracked handle: it is always considered owned and open, and closing it
+// always frees the C object.
+type Ref struct {
+	mu      sync.Mutex
+	owned   bool
+	closed  bool
+	release func()
+	parent  *Ref
+	id      uint64
+}
+
+// Owned returns a Ref for a C object owned by the caller, which release
+// frees. It returns nil when tracking is off.
+//
+// The finalizer may run as soon as the handle value holding the Ref is
+// last used, which can be before a C call on the object returns: methods
+// must keep the Ref alive with runtime.KeepAlive until then.
+func Owned(kind string, release func()) *Ref {
+	if !Tracking() {
+		return nil
+	}
+	ref := &Ref{owned: true, release: release}
+	if atomic.LoadInt32(&debugging) != 0 {
+		ref.id = leaks.add(kind, debug.Stack())
+	}
+	runtime.SetFinalizer(ref, (*Ref).finalize)
+	return ref
+}
+
+// Borrowed returns a Ref for a C object owned by the object of parent,
+// which may be nil if the owner is unknown or untracked. It returns nil
+// when tracking is off.
+func Borrowed(parent *Ref) *Ref {
+	if !Tracking() {
+		return nil
+	}
+	return &Ref{parent: parent}
+}
+
+// Close marks the object closed and reports whether the caller must free
+// it: only the first Close of an owned object does. Closing a borrowed
+// object is a no-op.
+func (ref *Ref) Close() bool {
+	if ref == nil {
+		return true
+	}
+	ref.mu.Lock()
+	defer ref.mu.Unlock()
+	if ref.closed {
+		return false
+	}
+	ref.closed = true
+	if !ref.owned {
+		return false
+	}
+	ref.forget()
+	return true
+}
+
+// Disown records that ownership of the object moved to another object,
+// for instance a geometry handed to a feature, so it is no longer freed
+// through this Ref. parent, if not nil, is the new owner.
+func (ref *Ref) Disown(parent *Ref) {
+	if ref == nil {
+		return
+	}
+	ref.mu.Lock()
+	defer ref.mu.Unlock()
+	if ref.owned {
+		ref.forget()
+	}
+	ref.owned = false
+	ref.parent = parent
+}
+
+// Owned reports whether the object is owned through this Ref.
+func (ref *Ref) Owned() bool {
+	if ref == nil {
+		return true
+	}
+	ref.mu.Lock()
+	defer ref.mu.Unlock()
+	return ref.owned
+}
+
+// forget drops the finalizer and leak record. ref.mu must be held.
+func (ref *Ref) forget() {
+	runtime.SetFinalizer(ref, nil)
+	if ref.id != 0 {
+		leaks.remove(ref.id)
+	}
+}
+
+func (ref *Ref) finalize() {
+	if ref.closed || !ref.owned {
+		return
+	}
+	ref.closed = true
+	if ref.id != 0 {
+		leaks.finalized(ref.id)
+	}
+	ref.release()
+}
+
+// Leak describes an owned handle that was not closed.
+type Leak struct {
+	Kind  string
+	Stack string
+	// Finalized is set when the handle was freed by its finalizer rather
+	// than still being open.
+	Finalized bool
+}
+
+var leaks = leakSet{open: make(map[uint64]*Leak)}
+
+type leakSet struct {
+	sync.Mutex
+	next  uint64
+	open  map[uint64]*Leak
+	freed []*Leak
+}
+
+func (set *leakSet) add(kind string, stack []byte) uint64 {
+	set.Lock()
+	defer set.Unlock()
+	set.next++
+	set.open[set.next] = &Leak{Kind: kind, Stack: string(stack)}
+	return set.next
+}
+
+func (set *leakSet) remove(id uint64) {
+	set.Lock()
+	delete(set.open, id)
+	set.Unlock()
+}
+
+func (set *leakSet) finalized(id uint64) {
+	set.Lock()
+	if leak, ok := set.open[id]; ok {
+		delete(set.open, id)
+		leak.Finalized = true
+		set.freed = append(set.freed, leak)
+	}
+	set.Unlock()
+}
+
+// Leaks returns the owned handles created in debug mode that are still
+// open or were freed by their finalizer, in creation order for the open
+// ones.
+func Leaks() []Leak {
+	leaks.Lock()
+	defer leaks.Unlock()
+	ids := make([]uint64, 0, len(leaks.open))
+	for id := range leaks.open {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	result := make([]Leak, 0, len(ids)+len(leaks.freed))
+	for _, leak := range leaks.freed {
+		result = append(result, *leak)
+	}
+	for _, id := range ids {
+		result = append(result, *leaks.open[id])
+	}
+	return result
+}
+
+// Report writes Leaks to w and returns how many there were.
+func Report(w io.Writer) int {
+	list := Leaks()
+	for _, leak := range list {
+		state := "not closed"
+		if leak.Finalized {
+			state = "freed by finalizer"
+		}
+		fmt.Fprintf(w, "leaked %s (%s), created at:\n%s\n", leak.Kind, state, leak.Stack)
+	}
+	return len(list)
+}
@@ -0,0 +1,77 @@
+package handle
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUntracked(t *testing.T) {
+	Track(false, false)
+	ref := Owned("test", func() {})
+	if ref != nil {
+		t.Fatalf("got a Ref with tracking off")
+	}
+	if !ref.Close() || !ref.Close() || !ref.Owned() {
+		t.Errorf("untracked handles must always be freed by Close")
+	}
+}
+
+func TestCloseIsIdempotent(t *testing.T) {
+	Track(true, false)
+	defer Track(false, false)
+
+	ref := Owned("test", func() {})
+	if !ref.Close() {
+		t.Errorf("first Close of an owned handle must free it")
+	}
+	if ref.Close() {
+		t.Errorf("second Close must not free it again")
+	}
+	if Borrowed(ref).Close() {
+		t.Errorf("Close of a borrowed handle must not free it")
+	}
+
+	stolen := Owned("test", func() {})
+	stolen.Disown(nil)
+	if stolen.Owned() || stolen.Close() {
+		t.Errorf("Close of a disowned handle must not free it")
+	}
+}
+
+func TestFinalizerAndLeaks(t *testing.T) {
+	Track(true, true)
+	defer Track(false, false)
+
+	released := make(chan struct{})
+	Owned("finalized", func() { close(released) })
+	open := Owned("open", func() {})
+	closed := Owned("closed", func() {})
+	closed.Close()
+
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+		select {
+		case <-released:
+			i = 10
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	select {
+	case <-released:
+	default:
+		t.Fatalf("finalizer did not release the handle")
+	}
+
+	var b strings.Builder
+	if n := Report(&b); n != 2 {
+		t.Errorf("got %d leaks, want 2:\n%s", n, b.String())
+	}
+	report := b.String()
+	if !strings.Contains(report, "leaked finalized (freed by finalizer)") ||
+		!strings.Contains(report, "leaked open (not closed)") || strings.Contains(report, "leaked closed") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+	runtime.KeepAlive(open)
+}
@@ -0,0 +1,249 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Raster I/O with extra arguments                                 */
+/* -------------------------------------------------------------------- */
+
+// RIOResampleAlg is the resampling algorithm used by IOEx when the buffer
+// and window sizes differ. It is distinct from the ResampleAlg of the
+// warper.
+type RIOResampleAlg int
+
+const (
+	GRIORA_NearestNeighbour = RIOResampleAlg(C.GRIORA_NearestNeighbour)
+	GRIORA_Bilinear         = RIOResampleAlg(C.GRIORA_Bilinear)
+	GRIORA_Cubic            = RIOResampleAlg(C.GRIORA_Cubic)
+	GRIORA_CubicSpline      = RIOResampleAlg(C.GRIORA_CubicSpline)
+	GRIORA_Lanczos          = RIOResampleAlg(C.GRIORA_Lanczos)
+	GRIORA_Average          = RIOResampleAlg(C.GRIORA_Average)
+	GRIORA_Mode             = RIOResampleAlg(C.GRIORA_Mode)
+	GRIORA_Gauss            = RIOResampleAlg(C.GRIORA_Gauss)
+)
+
+// FloatWindow is a window of a raster with fractional offsets and sizes,
+// in pixels.
+type FloatWindow struct {
+	XOff, YOff   float64
+	XSize, YSize float64
+}
+
+// IOExtraArg holds the optional arguments of IOEx. The zero value reads
+// like IO does.
+type IOExtraArg struct {
+	// Resampling is the algorithm used when the buffer and window sizes
+	// differ.
+	Resampling RIOResampleAlg
+	// SrcWindow, if set, is the exact source window to resample from,
+	// the integer window passed to IOEx then being the one enclosing it.
+	SrcWindow *FloatWindow
+	// Progress, if not nil, is called with ProgressData as the data is
+	// read. Returning 0 cancels the read.
+	Progress     ProgressFunc
+	ProgressData interface{}
+	// Context, if not nil, cancels the read once done.
+	Context context.Context
+}
+
+// cArg fills a GDALRasterIOExtraArg. The returned function must be called
+// once the I/O is done.
+func (extra *IOExtraArg) cArg() (C.GDALRasterIOExtraArg, func()) {
+	var arg C.GDALRasterIOExtraArg
+	arg.nVersion = C.RASTERIO_EXTRA_ARG_CURRENT_VERSION
+	if extra == nil {
+		return arg, func() {}
+	}
+	arg.eResampleAlg = C.GDALRIOResampleAlg(extra.Resampling)
+	if win := extra.SrcWindow; win != nil {
+		arg.bFloatingPointWindowValidity = 1
+		arg.dfXOff, arg.dfYOff = C.double(win.XOff), C.double(win.YOff)
+		arg.dfXSize, arg.dfYSize = C.double(win.XSize), C.double(win.YSize)
+	}
+	key, release := contextProgress(extra.context(), extra.Progress, extra.ProgressData)
+	if key != nil {
+		arg.pfnProgress = C.goGDALProgressFuncRegistryProxyB()
+		arg.pProgressData = key
+	}
+	return arg, release
+}
+
+func (extra *IOExtraArg) context() context.Context {
+	if extra == nil || extra.Context == nil {
+		return context.Background()
+	}
+	return extra.Context
+}
+
+// ioError returns ctx.Err() for a read interrupted by its context.
+func (extra *IOExtraArg) ioError(err error) error {
+	var gdalErr *Error
+	if err != nil && errors.As(err, &gdalErr) {
+		return contextError(extra.context(), gdalErr)
+	}
+	return err
+}
+
+// checkIOBuffer checks that buffer, of elements of dataType, holds
+// bufXSize by bufYSize pixels of bandCount bands laid out with the given
+// spacings in bytes, 0 meaning contiguous, so that GDAL stays within it.
+func checkIOBuffer(
+	buffer interface{},
+	dataType DataType,
+	bufXSize, bufYSize, bandCount int,
+	pixelSpace, lineSpace, bandSpace int,
+) error {
+	if bufXSize <= 0 || bufYSize <= 0 || bandCount <= 0 {
+		return fmt.Errorf("invalid buffer of %dx%d pixels and %d bands", bufXSize, bufYSize, bandCount)
+	}
+	if pixelSpace < 0 || lineSpace < 0 || bandSpace < 0 {
+		return fmt.Errorf("negative buffer spacing")
+	}
+	size := dataType.Size() / 8
+	if pixelSpace == 0 {
+		pixelSpace = size
+	}
+	if lineSpace == 0 {
+		lineSpace = pixelSpace * bufXSize
+	}
+	if bandSpace == 0 {
+		bandSpace = lineSpace * bufYSize
+	}
+	need := (bufXSize-1)*pixelSpace + (bufYSize-1)*lineSpace + (bandCount-1)*bandSpace + size
+	if n := reflect.ValueOf(buffer).Len() * size; n < need {
+		return fmt.Errorf("buffer has %d bytes, %dx%d pixels of %d bands need %d", n, bufXSize, bufYSize, bandCount, need)
+	}
+	return nil
+}
+
+// IOEx is IO with extra arguments: the resampling algorithm, a fractional
+// source window and progress reporting or cancellation. When reading to a
+// buffer smaller than the window, GDAL reads from the overview best
+// matching the buffer size, if any, rather than the full resolution band.
+func (rasterBand RasterBand) IOEx(
+	rwFlag RWFlag,
+	xOff, yOff, xSize, ySize int,
+	buffer interface{},
+	bufXSize, bufYSize int,
+	pixelSpace, lineSpace int,
+	extra *IOExtraArg,
+) error {
+	defer runtime.KeepAlive(rasterBand.ref)
+	dataType, dataPtr, err := determineBufferType(buffer)
+	if err != nil {
+		return err
+	}
+	if err := checkIOBuffer(buffer, dataType, bufXSize, bufYSize, 1, pixelSpace, lineSpace, 0); err != nil {
+		return err
+	}
+	arg, release := extra.cArg()
+	defer release()
+
+	return extra.ioError(cplCall("GDALRasterIOEx", func() C.CPLErr {
+		return C.GDALRasterIOEx(
+			rasterBand.cval,
+			C.GDALRWFlag(rwFlag),
+			C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize),
+			dataPtr,
+			C.int(bufXSize), C.int(bufYSize),
+			C.GDALDataType(dataType),
+			C.GSpacing(pixelSpace), C.GSpacing(lineSpace),
+			&arg,
+		)
+	}))
+}
+
+// IOEx is IO with extra arguments, see RasterBand.IOEx. An empty bandMap
+// stands for the first bandCount bands.
+func (dataset Dataset) IOEx(
+	rwFlag RWFlag,
+	xOff, yOff, xSize, ySize int,
+	buffer interface{},
+	bufXSize, bufYSize int,
+	bandCount int,
+	bandMap []int,
+	pixelSpace, lineSpace, bandSpace int,
+	extra *IOExtraArg,
+) error {
+	defer runtime.KeepAlive(dataset.ref)
+	dataType, dataPtr, err := determineBufferType(buffer)
+	if err != nil {
+		return err
+	}
+	if err := checkIOBuffer(buffer, dataType, bufXSize, bufYSize, bandCount, pixelSpace, lineSpace, bandSpace); err != nil {
+		return err
+	}
+	var cBandMap *C.int
+	if len(bandMap) > 0 {
+		if len(bandMap) < bandCount {
+			return fmt.Errorf("band map has %d bands, %d needed", len(bandMap), bandCount)
+		}
+		cBandMap = (*C.int)(unsafe.Pointer(&IntSliceToCInt(bandMap)[0]))
+	}
+	arg, release := extra.cArg()
+	defer release()
+
+	return extra.ioError(cplCall("GDALDatasetRasterIOEx", func() C.CPLErr {
+		return C.GDALDatasetRasterIOEx(
+			dataset.cval,
+			C.GDALRWFlag(rwFlag),
+			C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize),
+			dataPtr,
+			C.int(bufXSize), C.int(bufYSize),
+			C.GDALDataType(dataType),
+			C.int(bandCount),
+			cBandMap,
+			C.GSpacing(pixelSpace), C.GSpacing(lineSpace), C.GSpacing(bandSpace),
+			&arg,
+		)
+	}))
+}
+
+// ReadResampled reads win of band resampled to bufXSize by bufYSize
+// pixels of type T, for instance to build a thumbnail.
+func ReadResampled[T Numeric](band RasterBand, win Window, bufXSize, bufYSize int, resampling RIOResampleAlg) ([]T, error) {
+	defer runtime.KeepAlive(band.ref)
+	if err := win.within(band.XSize(), band.YSize()); err != nil {
+		return nil, err
+	}
+	data := make([]T, bufXSize*bufYSize)
+	if len(data) == 0 {
+		return data, nil
+	}
+	arg, release := (&IOExtraArg{Resampling: resampling}).cArg()
+	defer release()
+
+	err := cplCall("GDALRasterIOEx", func() C.CPLErr {
+		return C.GDALRasterIOEx(
+			band.cval,
+			C.GDALRWFlag(Read),
+			C.int(win.XOff), C.int(win.YOff), C.int(win.XSize), C.int(win.YSize),
+			unsafe.Pointer(&data[0]),
+			C.int(bufXSize), C.int(bufYSize),
+			C.GDALDataType(DataTypeOf[T]()),
+			0, 0,
+			&arg,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,66 @@
+package gdal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIOEx(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("", 4, 4, 1, Float32, nil)
+	defer ds.Close()
+	band := ds.RasterBand(1)
+	values := make([]float32, 16)
+	for i := range values {
+		values[i] = float32(i)
+	}
+	if err := WriteWindow(band, Window{XSize: 4, YSize: 4}, values); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadResampled[float32](band, Window{XSize: 4, YSize: 4}, 2, 2, GRIORA_Average)
+	if err != nil {
+		t.Fatalf("ReadResampled: %v", err)
+	}
+	if fmt.Sprint(got) != "[2.5 4.5 10.5 12.5]" {
+		t.Errorf("average: got %v, want [2.5 4.5 10.5 12.5]", got)
+	}
+
+	buffer := make([]float32, 1)
+	extra := &IOExtraArg{
+		Resampling: GRIORA_Average,
+		SrcWindow:  &FloatWindow{XOff: 0.5, YOff: 0, XSize: 2, YSize: 1},
+	}
+	if err := band.IOEx(Read, 0, 0, 3, 1, buffer, 1, 1, 0, 0, extra); err != nil {
+		t.Fatalf("IOEx: %v", err)
+	}
+	if buffer[0] != 1 {
+		t.Errorf("fractional window: got %v, want 1", buffer[0])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	thumb := make([]float32, 4)
+	err = ds.IOEx(Read, 0, 0, 4, 4, thumb, 2, 2, 1, []int{1}, 0, 0, 0, &IOExtraArg{Resampling: GRIORA_Average, Context: ctx})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled read: got %v, want context.Canceled", err)
+	}
+
+	if err := ds.IOEx(Read, 0, 0, 4, 4, thumb, 2, 2, 1, nil, 0, 0, 0, nil); err != nil {
+		t.Errorf("IOEx without a band map: %v", err)
+	}
+	if err := band.IOEx(Read, 0, 0, 4, 4, thumb, 4, 4, 0, 0, nil); err == nil {
+		t.Error("IOEx into a short buffer: got no error")
+	}
+	if err := band.IOEx(Read, 0, 0, 4, 4, make([]float32, 16), 4, 4, 8, 0, nil); err == nil {
+		t.Error("IOEx with a pixel spacing overflowing the buffer: got no error")
+	}
+	if err := ds.IOEx(Read, 0, 0, 4, 4, make([]float32, 8), 2, 2, 2, []int{1}, 0, 0, 0, nil); err == nil {
+		t.Error("IOEx with a short band map: got no error")
+	}
+}
@@ -0,0 +1,1048 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"github.com/airmap/gdal/internal/handle"
+)
+
+/* ==================================================================== */
+/*      Multidimensional API                                            */
+/* ==================================================================== */
+
+// The multidimensional API exposes datasets such as netCDF, HDF5 or Zarr
+// files as a hierarchy of groups holding N-dimensional arrays, their
+// dimensions and attributes. Datasets must be opened with
+// OFMultidimRaster, or created with Driver.CreateMultiDimensional, and
+// kept open while their objects are in use. All objects must be released.
+
+// Group is a named container of arrays, dimensions, attributes and other
+// groups.
+type Group struct {
+	cval C.GDALGroupH
+	ref  *handle.Ref
+}
+
+// MDArray is an N-dimensional array.
+type MDArray struct {
+	cval C.GDALMDArrayH
+	ref  *handle.Ref
+}
+
+// Dimension is a dimension of arrays.
+type Dimension struct {
+	cval C.GDALDimensionH
+	ref  *handle.Ref
+}
+
+// Attribute is a named value, possibly an array, attached to a group or an
+// array.
+type Attribute struct {
+	cval C.GDALAttributeH
+	ref  *handle.Ref
+}
+
+// ExtendedDataType is the data type of arrays and attributes: a numeric
+// DataType, a string or a compound type.
+type ExtendedDataType struct {
+	cval C.GDALExtendedDataTypeH
+	ref  *handle.Ref
+}
+
+// ExtendedDataTypeClass is the class of an ExtendedDataType.
+type ExtendedDataTypeClass int
+
+const (
+	EDTC_Numeric  = ExtendedDataTypeClass(C.GEDTC_NUMERIC)
+	EDTC_String   = ExtendedDataTypeClass(C.GEDTC_STRING)
+	EDTC_Compound = ExtendedDataTypeClass(C.GEDTC_COMPOUND)
+)
+
+func ownedGroup(h C.GDALGroupH) Group {
+	return Group{h, handle.Owned("Group", func() { C.GDALGroupRelease(h) })}
+}
+
+func ownedMDArray(h C.GDALMDArrayH) MDArray {
+	return MDArray{h, handle.Owned("MDArray", func() { C.GDALMDArrayRelease(h) })}
+}
+
+func ownedDimension(h C.GDALDimensionH) Dimension {
+	return Dimension{h, handle.Owned("Dimension", func() { C.GDALDimensionRelease(h) })}
+}
+
+func ownedAttribute(h C.GDALAttributeH) Attribute {
+	return Attribute{h, handle.Owned("Attribute", func() { C.GDALAttributeRelease(h) })}
+}
+
+func ownedExtendedDataType(h C.GDALExtendedDataTypeH) ExtendedDataType {
+	return ExtendedDataType{h, handle.Owned("ExtendedDataType", func() { C.GDALExtendedDataTypeRelease(h) })}
+}
+
+/* -------------------------------------------------------------------- */
+/*      Datasets                                                        */
+/* -------------------------------------------------------------------- */
+
+// RootGroup returns the root group of a dataset opened with
+// OFMultidimRaster.
+func (dataset Dataset) RootGroup() (Group, error) {
+	defer runtime.KeepAlive(dataset.ref)
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	group := C.GDALDatasetGetRootGroup(dataset.cval)
+	if group == nil {
+		return Group{}, failure("GDALDatasetGetRootGroup", "dataset has no multidimensional root group")
+	}
+	return ownedGroup(group), nil
+}
+
+// CreateMultiDimensional creates a dataset to be filled through its root
+// group, for the drivers supporting it (MEM, netCDF, Zarr, ...).
+func (driver Driver) CreateMultiDimensional(filename string, rootGroupOptions, options []string) (Dataset, error) {
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+	cRootGroupOptions, freeRootGroupOptions := cStringList(rootGroupOptions)
+	defer freeRootGroupOptions()
+	cOptions, freeOptions := cStringList(options)
+	defer freeOptions()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	dataset := C.GDALCreateMultiDimensional(driver.cval, cFilename, cRootGroupOptions, cOptions)
+	if dataset == nil {
+		return Dataset{}, failure("GDALCreateMultiDimensional", fmt.Sprintf("cannot create '%s'", filename))
+	}
+	return ownedDataset(dataset), nil
+}
+
+/* -------------------------------------------------------------------- */
+/*      Groups                                                          */
+/* -------------------------------------------------------------------- */
+
+// Release releases the group. It must be called once, unless handle
+// tracking is on, in which case releasing it again is a no-op.
+func (group Group) Release() {
+	if group.cval != nil && group.ref.Close() {
+		C.GDALGroupRelease(group.cval)
+	}
+}
+
+// Name returns the name of the group.
+func (group Group) Name() string {
+	defer runtime.KeepAlive(group.ref)
+	return C.GoString(C.GDALGroupGetName(group.cval))
+}
+
+// FullName returns the path of the group from the root group.
+func (group Group) FullName() string {
+	defer runtime.KeepAlive(group.ref)
+	return C.GoString(C.GDALGroupGetFullName(group.cval))
+}
+
+// MDArrayNames lists the arrays of the group.
+func (group Group) MDArrayNames(options []string) []string {
+	defer runtime.KeepAlive(group.ref)
+	cOptions, free := cStringList(options)
+	defer free()
+	names := C.GDALGroupGetMDArrayNames(group.cval, cOptions)
+	defer C.CSLDestroy(names)
+	return goStringList(names)
+}
+
+// OpenMDArray opens an array of the group.
+func (group Group) OpenMDArray(name string, options []string) (MDArray, error) {
+	defer runtime.KeepAlive(group.ref)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cOptions, free := cStringList(options)
+	defer free()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	array := C.GDALGroupOpenMDArray(group.cval, cName, cOptions)
+	if array == nil {
+		return MDArray{}, failure("GDALGroupOpenMDArray", fmt.Sprintf("no array '%s'", name))
+	}
+	return ownedMDArray(array), nil
+}
+
+// GroupNames lists the subgroups of the group.
+func (group Group) GroupNames(options []string) []string {
+	defer runtime.KeepAlive(group.ref)
+	cOptions, free := cStringList(options)
+	defer free()
+	names := C.GDALGroupGetGroupNames(group.cval, cOptions)
+	defer C.CSLDestroy(names)
+	return goStringList(names)
+}
+
+// OpenGroup opens a subgroup of the group.
+func (group Group) OpenGroup(name string, options []string) (Group, error) {
+	defer runtime.KeepAlive(group.ref)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cOptions, free := cStringList(options)
+	defer free()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	sub := C.GDALGroupOpenGroup(group.cval, cName, cOptions)
+	if sub == nil {
+		return Group{}, failure("GDALGroupOpenGroup", fmt.Sprintf("no group '%s'", name))
+	}
+	return ownedGroup(sub), nil
+}
+
+// Dimensions lists the dimensions declared in the group.
+func (group Group) Dimensions(options []string) []Dimension {
+	defer runtime.KeepAlive(group.ref)
+	cOptions, free := cStringList(options)
+	defer free()
+	var count C.size_t
+	dims := C.GDALGroupGetDimensions(group.cval, &count, cOptions)
+	return dimensionList(dims, count)
+}
+
+// Attribute returns an attribute of the group.
+func (group Group) Attribute(name string) (Attribute, error) {
+	defer runtime.KeepAlive(group.ref)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	attr := C.GDALGroupGetAttribute(group.cval, cName)
+	if attr == nil {
+		return Attribute{}, failure("GDALGroupGetAttribute", fmt.Sprintf("no attribute '%s'", name))
+	}
+	return ownedAttribute(attr), nil
+}
+
+// Attributes lists the attributes of the group.
+func (group Group) Attributes(options []string) []Attribute {
+	defer runtime.KeepAlive(group.ref)
+	cOptions, free := cStringList(options)
+	defer free()
+	var count C.size_t
+	attrs := C.GDALGroupGetAttributes(group.cval, &count, cOptions)
+	return attributeList(attrs, count)
+}
+
+// CreateGroup creates a subgroup.
+func (group Group) CreateGroup(name string, options []string) (Group, error) {
+	defer runtime.KeepAlive(group.ref)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cOptions, free := cStringList(options)
+	defer free()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	sub := C.GDALGroupCreateGroup(group.cval, cName, cOptions)
+	if sub == nil {
+		return Group{}, failure("GDALGroupCreateGroup", fmt.Sprintf("cannot create group '%s'", name))
+	}
+	return ownedGroup(sub), nil
+}
+
+// CreateDimension creates a dimension of the given size. dimType (such as
+// "HORIZONTAL_X" or "TEMPORAL") and direction (such as "EAST") may be
+// empty.
+func (group Group) CreateDimension(name, dimType, direction string, size uint64, options []string) (Dimension, error) {
+	defer runtime.KeepAlive(group.ref)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cType := C.CString(dimType)
+	defer C.free(unsafe.Pointer(cType))
+	cDirection := C.CString(direction)
+	defer C.free(unsafe.Pointer(cDirection))
+	cOptions, free := cStringList(options)
+	defer free()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	dim := C.GDALGroupCreateDimension(group.cval, cName, cType, cDirection, C.GUInt64(size), cOptions)
+	if dim == nil {
+		return Dimension{}, failure("GDALGroupCreateDimension", fmt.Sprintf("cannot create dimension '%s'", name))
+	}
+	return ownedDimension(dim), nil
+}
+
+// CreateMDArray creates an array with the given dimensions, slowest
+// varying first, and data type.
+func (group Group) CreateMDArray(name string, dims []Dimension, dataType ExtendedDataType, options []string) (MDArray, error) {
+	defer runtime.KeepAlive(group.ref)
+	defer runtime.KeepAlive(dataType.ref)
+	defer runtime.KeepAlive(dims)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cOptions, free := cStringList(options)
+	defer free()
+	cDims := make([]C.GDALDimensionH, len(dims)+1)
+	for i, dim := range dims {
+		cDims[i] = dim.cval
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	array := C.GDALGroupCreateMDArray(
+		group.cval, cName,
+		C.size_t(len(dims)), (*C.GDALDimensionH)(unsafe.Pointer(&cDims[0])),
+		dataType.cval, cOptions,
+	)
+	if array == nil {
+		return MDArray{}, failure("GDALGroupCreateMDArray", fmt.Sprintf("cannot create array '%s'", name))
+	}
+	return ownedMDArray(array), nil
+}
+
+// CreateAttribute creates an attribute of the group, a scalar if sizes is
+// empty.
+func (group Group) CreateAttribute(name string, sizes []uint64, dataType ExtendedDataType, options []string) (Attribute, error) {
+	defer runtime.KeepAlive(group.ref)
+	defer runtime.KeepAlive(dataType.ref)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cOptions, free := cStringList(options)
+	defer free()
+	cSizes := uint64List(sizes)
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	attr := C.GDALGroupCreateAttribute(group.cval, cName, C.size_t(len(sizes)), &cSizes[0], dataType.cval, cOptions)
+	if attr == nil {
+		return Attribute{}, failure("GDALGroupCreateAttribute", fmt.Sprintf("cannot create attribute '%s'", name))
+	}
+	return ownedAttribute(attr), nil
+}
+
+/* -------------------------------------------------------------------- */
+/*      Arrays                                                          */
+/* -------------------------------------------------------------------- */
+
+// Release releases the array. It must be called once, unless handle
+// tracking is on, in which case releasing it again is a no-op.
+func (array MDArray) Release() {
+	if array.cval != nil && array.ref.Close() {
+		C.GDALMDArrayRelease(array.cval)
+	}
+}
+
+// Name returns the name of the array.
+func (array MDArray) Name() string {
+	defer runtime.KeepAlive(array.ref)
+	return C.GoString(C.GDALMDArrayGetName(array.cval))
+}
+
+// FullName returns the path of the array from the root group.
+func (array MDArray) FullName() string {
+	defer runtime.KeepAlive(array.ref)
+	return C.GoString(C.GDALMDArrayGetFullName(array.cval))
+}
+
+// ElementCount returns the number of elements of the array.
+func (array MDArray) ElementCount() uint64 {
+	defer runtime.KeepAlive(array.ref)
+	return uint64(C.GDALMDArrayGetTotalElementsCount(array.cval))
+}
+
+// DimensionCount returns the number of dimensions of the array.
+func (array MDArray) DimensionCount() int {
+	defer runtime.KeepAlive(array.ref)
+	return int(C.GDALMDArrayGetDimensionCount(array.cval))
+}
+
+// Dimensions returns the dimensions of the array, slowest varying first.
+func (array MDArray) Dimensions() []Dimension {
+	defer runtime.KeepAlive(array.ref)
+	var count C.size_t
+	dims := C.GDALMDArrayGetDimensions(array.cval, &count)
+	return dimensionList(dims, count)
+}
+
+// Shape returns the size of each dimension of the array.
+func (array MDArray) Shape() []uint64 {
+	dims := array.Dimensions()
+	shape := make([]uint64, len(dims))
+	for i, dim := range dims {
+		shape[i] = dim.Size()
+		dim.Release()
+	}
+	return shape
+}
+
+// DataType returns the data type of the array.
+func (array MDArray) DataType() ExtendedDataType {
+	defer runtime.KeepAlive(array.ref)
+	return ownedExtendedDataType(C.GDALMDArrayGetDataType(array.cval))
+}
+
+// Attribute returns an attribute of the array.
+func (array MDArray) Attribute(name string) (Attribute, error) {
+	defer runtime.KeepAlive(array.ref)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	attr := C.GDALMDArrayGetAttribute(array.cval, cName)
+	if attr == nil {
+		return Attribute{}, failure("GDALMDArrayGetAttribute", fmt.Sprintf("no attribute '%s'", name))
+	}
+	return ownedAttribute(attr), nil
+}
+
+// Attributes lists the attributes of the array.
+func (array MDArray) Attributes(options []string) []Attribute {
+	defer runtime.KeepAlive(array.ref)
+	cOptions, free := cStringList(options)
+	defer free()
+	var count C.size_t
+	attrs := C.GDALMDArrayGetAttributes(array.cval, &count, cOptions)
+	return attributeList(attrs, count)
+}
+
+// CreateAttribute creates an attribute of the array, a scalar if sizes is
+// empty.
+func (array MDArray) CreateAttribute(name string, sizes []uint64, dataType ExtendedDataType, options []string) (Attribute, error) {
+	defer runtime.KeepAlive(array.ref)
+	defer runtime.KeepAlive(dataType.ref)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cOptions, free := cStringList(options)
+	defer free()
+	cSizes := uint64List(sizes)
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	attr := C.GDALMDArrayCreateAttribute(array.cval, cName, C.size_t(len(sizes)), &cSizes[0], dataType.cval, cOptions)
+	if attr == nil {
+		return Attribute{}, failure("GDALMDArrayCreateAttribute", fmt.Sprintf("cannot create attribute '%s'", name))
+	}
+	return ownedAttribute(attr), nil
+}
+
+// NoDataValue returns the nodata value of the array, if any.
+func (array MDArray) NoDataValue() (float64, bool) {
+	defer runtime.KeepAlive(array.ref)
+	var hasNoData C.int
+	value := C.GDALMDArrayGetNoDataValueAsDouble(array.cval, &hasNoData)
+	return float64(value), hasNoData != 0
+}
+
+// SetNoDataValue sets the nodata value of the array.
+func (array MDArray) SetNoDataValue(value float64) error {
+	defer runtime.KeepAlive(array.ref)
+	return mdCall("GDALMDArraySetNoDataValueAsDouble", func() C.int {
+		return C.GDALMDArraySetNoDataValueAsDouble(array.cval, C.double(value))
+	})
+}
+
+// Unit returns the unit of the values of the array.
+func (array MDArray) Unit() string {
+	defer runtime.KeepAlive(array.ref)
+	return C.GoString(C.GDALMDArrayGetUnit(array.cval))
+}
+
+// SetUnit sets the unit of the values of the array.
+func (array MDArray) SetUnit(unit string) error {
+	defer runtime.KeepAlive(array.ref)
+	cUnit := C.CString(unit)
+	defer C.free(unsafe.Pointer(cUnit))
+	return mdCall("GDALMDArraySetUnit", func() C.int {
+		return C.GDALMDArraySetUnit(array.cval, cUnit)
+	})
+}
+
+// Offset returns the offset to apply to the values of the array, if any.
+func (array MDArray) Offset() (float64, bool) {
+	defer runtime.KeepAlive(array.ref)
+	var hasValue C.int
+	value := C.GDALMDArrayGetOffset(array.cval, &hasValue)
+	return float64(value), hasValue != 0
+}
+
+// Scale returns the scale to apply to the values of the array, if any.
+func (array MDArray) Scale() (float64, bool) {
+	defer runtime.KeepAlive(array.ref)
+	var hasValue C.int
+	value := C.GDALMDArrayGetScale(array.cval, &hasValue)
+	return float64(value), hasValue != 0
+}
+
+// View returns a view of the array selected by a NumPy-like expression,
+// such as "[0,:,::2]" to take the first index of the first dimension and
+// every other index of the last one, or "['field']" for a compound field.
+func (array MDArray) View(expr string) (MDArray, error) {
+	defer runtime.KeepAlive(array.ref)
+	cExpr := C.CString(expr)
+	defer C.free(unsafe.Pointer(cExpr))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	view := C.GDALMDArrayGetView(array.cval, cExpr)
+	if view == nil {
+		return MDArray{}, failure("GDALMDArrayGetView", fmt.Sprintf("invalid view '%s'", expr))
+	}
+	return ownedMDArray(view), nil
+}
+
+// Transpose returns a view of the array with its dimensions reordered:
+// dimension i of the view is dimension axes[i] of the array, -1 adding a
+// dimension of size 1.
+func (array MDArray) Transpose(axes []int) (MDArray, error) {
+	defer runtime.KeepAlive(array.ref)
+	cAxes := make([]C.int, len(axes)+1)
+	for i, axis := range axes {
+		cAxes[i] = C.int(axis)
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	view := C.GDALMDArrayTranspose(array.cval, C.size_t(len(axes)), (*C.int)(unsafe.Pointer(&cAxes[0])))
+	if view == nil {
+		return MDArray{}, failure("GDALMDArrayTranspose", fmt.Sprintf("invalid axes %v", axes))
+	}
+	return ownedMDArray(view), nil
+}
+
+// AsClassicDataset returns a classic dataset of the array, with xDim and
+// yDim (0-based indices of the array's dimensions) as its columns and
+// rows, and a band for each combination of indices of the other
+// dimensions. The dataset must be closed.
+func (array MDArray) AsClassicDataset(xDim, yDim int) (Dataset, error) {
+	defer runtime.KeepAlive(array.ref)
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	dataset := C.GDALMDArrayAsClassicDataset(array.cval, C.size_t(xDim), C.size_t(yDim))
+	if dataset == nil {
+		return Dataset{}, failure("GDALMDArrayAsClassicDataset", "cannot expose array as a classic dataset")
+	}
+	return ownedDataset(dataset), nil
+}
+
+// Hyperslab selects part of an array: count[i] elements of dimension i,
+// from Start[i], every Step[i]. Start defaults to zeros and Step to ones;
+// Count is required for arrays with dimensions.
+type Hyperslab struct {
+	Start []uint64
+	Count []int
+	Step  []int64
+}
+
+// Len returns the number of elements of the hyperslab.
+func (slab Hyperslab) Len() int {
+	n := 1
+	for _, count := range slab.Count {
+		n *= count
+	}
+	return n
+}
+
+// cArgs checks the hyperslab against the array and returns its C
+// arguments, nil meaning the defaults.
+func (slab Hyperslab) cArgs(array MDArray) (start *C.GUInt64, count *C.size_t, step *C.GInt64, err error) {
+	dims := array.DimensionCount()
+	if len(slab.Count) != dims || (slab.Start != nil && len(slab.Start) != dims) || (slab.Step != nil && len(slab.Step) != dims) {
+		return nil, nil, nil, fmt.Errorf("hyperslab %+v does not match the %d dimensions of the array", slab, dims)
+	}
+	if dims == 0 {
+		return nil, nil, nil, nil
+	}
+	cStart := make([]C.GUInt64, dims)
+	cCount := make([]C.size_t, dims)
+	for i := 0; i < dims; i++ {
+		if slab.Start != nil {
+			cStart[i] = C.GUInt64(slab.Start[i])
+		}
+		if slab.Count[i] <= 0 {
+			return nil, nil, nil, fmt.Errorf("hyperslab %+v is empty", slab)
+		}
+		cCount[i] = C.size_t(slab.Count[i])
+	}
+	if slab.Step != nil {
+		cStep := make([]C.GInt64, dims)
+		for i, s := range slab.Step {
+			cStep[i] = C.GInt64(s)
+		}
+		step = &cStep[0]
+	}
+	return &cStart[0], &cCount[0], step, nil
+}
+
+// ReadMDArray reads a hyperslab of array into a new slice, in row major
+// order, converting the values to T.
+func ReadMDArray[T Numeric](array MDArray, slab Hyperslab) ([]T, error) {
+	data := make([]T, slab.Len())
+	if err := ReadMDArrayInto(array, slab, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ReadMDArrayInto is ReadMDArray reading into data, which must hold
+// exactly the elements of the hyperslab.
+func ReadMDArrayInto[T Numeric](array MDArray, slab Hyperslab, data []T) error {
+	return mdArrayIO(array, slab, DataTypeOf[T](), sliceData(data), len(data), false)
+}
+
+// WriteMDArray writes data, holding the elements of the hyperslab in row
+// major order, to array.
+func WriteMDArray[T Numeric](array MDArray, slab Hyperslab, data []T) error {
+	return mdArrayIO(array, slab, DataTypeOf[T](), sliceData(data), len(data), true)
+}
+
+func mdArrayIO(array MDArray, slab Hyperslab, dataType DataType, data unsafe.Pointer, length int, write bool) error {
+	defer runtime.KeepAlive(array.ref)
+	if length != slab.Len() {
+		return fmt.Errorf("buffer has %d elements, hyperslab %+v needs %d", length, slab, slab.Len())
+	}
+	start, count, step, err := slab.cArgs(array)
+	if err != nil {
+		return err
+	}
+	bufferType := C.GDALExtendedDataTypeCreate(C.GDALDataType(dataType))
+	defer C.GDALExtendedDataTypeRelease(bufferType)
+	size := C.size_t(length * dataType.Size() / 8)
+
+	if write {
+		return mdCall("GDALMDArrayWrite", func() C.int {
+			return C.GDALMDArrayWrite(array.cval, start, count, step, nil, bufferType, data, data, size)
+		})
+	}
+	return mdCall("GDALMDArrayRead", func() C.int {
+		return C.GDALMDArrayRead(array.cval, start, count, step, nil, bufferType, data, data, size)
+	})
+}
+
+// ReadStrings reads a hyperslab of a string array.
+func (array MDArray) ReadStrings(slab Hyperslab) ([]string, error) {
+	defer runtime.KeepAlive(array.ref)
+	start, count, step, err := slab.cArgs(array)
+	if err != nil {
+		return nil, err
+	}
+	bufferType := C.GDALExtendedDataTypeCreateString(0)
+	defer C.GDALExtendedDataTypeRelease(bufferType)
+	// GDAL allocates the strings, so the buffer of pointers to them is
+	// allocated in C memory too.
+	n := slab.Len()
+	size := C.size_t(n) * C.size_t(unsafe.Sizeof((*C.char)(nil)))
+	buffer := C.VSICalloc(C.size_t(n), C.size_t(unsafe.Sizeof((*C.char)(nil))))
+	if buffer == nil {
+		return nil, fmt.Errorf("cannot allocate %d strings", n)
+	}
+	defer C.VSIFree(buffer)
+
+	err = mdCall("GDALMDArrayRead", func() C.int {
+		return C.GDALMDArrayRead(array.cval, start, count, step, nil, bufferType, buffer, buffer, size)
+	})
+	ptrs := unsafe.Slice((**C.char)(buffer), n)
+	strings := make([]string, n)
+	for i, p := range ptrs {
+		if p != nil {
+			strings[i] = C.GoString(p)
+			C.VSIFree(unsafe.Pointer(p))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings, nil
+}
+
+/* -------------------------------------------------------------------- */
+/*      Dimensions                                                      */
+/* -------------------------------------------------------------------- */
+
+// Release releases the dimension. It must be called once, unless handle
+// tracking is on, in which case releasing it again is a no-op.
+func (dim Dimension) Release() {
+	if dim.cval != nil && dim.ref.Close() {
+		C.GDALDimensionRelease(dim.cval)
+	}
+}
+
+// Name returns the name of the dimension.
+func (dim Dimension) Name() string {
+	defer runtime.KeepAlive(dim.ref)
+	return C.GoString(C.GDALDimensionGetName(dim.cval))
+}
+
+// FullName returns the path of the dimension from the root group.
+func (dim Dimension) FullName() string {
+	defer runtime.KeepAlive(dim.ref)
+	return C.GoString(C.GDALDimensionGetFullName(dim.cval))
+}
+
+// Type returns the type of the dimension, such as "HORIZONTAL_X",
+// "VERTICAL" or "TEMPORAL", or "" if unknown.
+func (dim Dimension) Type() string {
+	defer runtime.KeepAlive(dim.ref)
+	return C.GoString(C.GDALDimensionGetType(dim.cval))
+}
+
+// Direction returns the direction of the dimension, such as "EAST" or
+// "FUTURE", or "" if unknown.
+func (dim Dimension) Direction() string {
+	defer runtime.KeepAlive(dim.ref)
+	return C.GoString(C.GDALDimensionGetDirection(dim.cval))
+}
+
+// Size returns the number of indices of the dimension.
+func (dim Dimension) Size() uint64 {
+	defer runtime.KeepAlive(dim.ref)
+	return uint64(C.GDALDimensionGetSize(dim.cval))
+}
+
+// IndexingVariable returns the array holding the coordinates of the
+// dimension, if any.
+func (dim Dimension) IndexingVariable() (MDArray, bool) {
+	defer runtime.KeepAlive(dim.ref)
+	array := C.GDALDimensionGetIndexingVariable(dim.cval)
+	if array == nil {
+		return MDArray{}, false
+	}
+	return ownedMDArray(array), true
+}
+
+// SetIndexingVariable sets the array holding the coordinates of the
+// dimension.
+func (dim Dimension) SetIndexingVariable(array MDArray) error {
+	defer runtime.KeepAlive(dim.ref)
+	defer runtime.KeepAlive(array.ref)
+	return mdCall("GDALDimensionSetIndexingVariable", func() C.int {
+		return C.GDALDimensionSetIndexingVariable(dim.cval, array.cval)
+	})
+}
+
+/* -------------------------------------------------------------------- */
+/*      Attributes                                                      */
+/* -------------------------------------------------------------------- */
+
+// Release releases the attribute. It must be called once, unless handle
+// tracking is on, in which case releasing it again is a no-op.
+func (attr Attribute) Release() {
+	if attr.cval != nil && attr.ref.Close() {
+		C.GDALAttributeRelease(attr.cval)
+	}
+}
+
+// Name returns the name of the attribute.
+func (attr Attribute) Name() string {
+	defer runtime.KeepAlive(attr.ref)
+	return C.GoString(C.GDALAttributeGetName(attr.cval))
+}
+
+// FullName returns the path of the attribute from the root group.
+func (attr Attribute) FullName() string {
+	defer runtime.KeepAlive(attr.ref)
+	return C.GoString(C.GDALAttributeGetFullName(attr.cval))
+}
+
+// ElementCount returns the number of values of the attribute.
+func (attr Attribute) ElementCount() uint64 {
+	defer runtime.KeepAlive(attr.ref)
+	return uint64(C.GDALAttributeGetTotalElementsCount(attr.cval))
+}
+
+// Shape returns the size of each dimension of the attribute, none for a
+// scalar.
+func (attr Attribute) Shape() []uint64 {
+	defer runtime.KeepAlive(attr.ref)
+	var count C.size_t
+	sizes := C.GDALAttributeGetDimensionsSize(attr.cval, &count)
+	if sizes == nil {
+		return nil
+	}
+	defer C.VSIFree(unsafe.Pointer(sizes))
+	shape := make([]uint64, int(count))
+	for i, size := range unsafe.Slice(sizes, int(count)) {
+		shape[i] = uint64(size)
+	}
+	return shape
+}
+
+// DataType returns the data type of the attribute.
+func (attr Attribute) DataType() ExtendedDataType {
+	defer runtime.KeepAlive(attr.ref)
+	return ownedExtendedDataType(C.GDALAttributeGetDataType(attr.cval))
+}
+
+// String returns the value of the attribute as a string, converting it if
+// needed.
+func (attr Attribute) String() string {
+	defer runtime.KeepAlive(attr.ref)
+	return C.GoString(C.GDALAttributeReadAsString(attr.cval))
+}
+
+// Int returns the value of the attribute as an integer.
+func (attr Attribute) Int() int {
+	defer runtime.KeepAlive(attr.ref)
+	return int(C.GDALAttributeReadAsInt(attr.cval))
+}
+
+// Float64 returns the value of the attribute as a float64.
+func (attr Attribute) Float64() float64 {
+	defer runtime.KeepAlive(attr.ref)
+	return float64(C.GDALAttributeReadAsDouble(attr.cval))
+}
+
+// Strings returns the values of the attribute as strings.
+func (attr Attribute) Strings() []string {
+	defer runtime.KeepAlive(attr.ref)
+	values := C.GDALAttributeReadAsStringArray(attr.cval)
+	defer C.CSLDestroy(values)
+	return goStringList(values)
+}
+
+// Float64s returns the values of the attribute as float64s.
+func (attr Attribute) Float64s() []float64 {
+	defer runtime.KeepAlive(attr.ref)
+	var count C.size_t
+	values := C.GDALAttributeReadAsDoubleArray(attr.cval, &count)
+	if values == nil {
+		return nil
+	}
+	defer C.VSIFree(unsafe.Pointer(values))
+	result := make([]float64, int(count))
+	for i, value := range unsafe.Slice(values, int(count)) {
+		result[i] = float64(value)
+	}
+	return result
+}
+
+// WriteString writes a string value.
+func (attr Attribute) WriteString(value string) error {
+	defer runtime.KeepAlive(attr.ref)
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+	return mdCall("GDALAttributeWriteString", func() C.int {
+		return C.GDALAttributeWriteString(attr.cval, cValue)
+	})
+}
+
+// WriteInt writes an integer value.
+func (attr Attribute) WriteInt(value int) error {
+	defer runtime.KeepAlive(attr.ref)
+	return mdCall("GDALAttributeWriteInt", func() C.int {
+		return C.GDALAttributeWriteInt(attr.cval, C.int(value))
+	})
+}
+
+// WriteFloat64 writes a float64 value.
+func (attr Attribute) WriteFloat64(value float64) error {
+	defer runtime.KeepAlive(attr.ref)
+	return mdCall("GDALAttributeWriteDouble", func() C.int {
+		return C.GDALAttributeWriteDouble(attr.cval, C.double(value))
+	})
+}
+
+// WriteStrings writes string values, as many as the attribute has.
+func (attr Attribute) WriteStrings(values []string) error {
+	defer runtime.KeepAlive(attr.ref)
+	cValues, free := cStringList(values)
+	defer free()
+	return mdCall("GDALAttributeWriteStringArray", func() C.int {
+		return C.GDALAttributeWriteStringArray(attr.cval, cValues)
+	})
+}
+
+// WriteFloat64s writes float64 values, as many as the attribute has.
+func (attr Attribute) WriteFloat64s(values []float64) error {
+	defer runtime.KeepAlive(attr.ref)
+	if len(values) == 0 {
+		return fmt.Errorf("no values")
+	}
+	return mdCall("GDALAttributeWriteDoubleArray", func() C.int {
+		return C.GDALAttributeWriteDoubleArray(attr.cval, (*C.double)(unsafe.Pointer(&values[0])), C.size_t(len(values)))
+	})
+}
+
+/* -------------------------------------------------------------------- */
+/*      Extended data types                                             */
+/* -------------------------------------------------------------------- */
+
+// NewExtendedDataType returns the extended data type of a numeric
+// DataType. It must be released.
+func NewExtendedDataType(dataType DataType) ExtendedDataType {
+	return ownedExtendedDataType(C.GDALExtendedDataTypeCreate(C.GDALDataType(dataType)))
+}
+
+// NewStringDataType returns a string data type, of unbounded length if
+// maxLength is 0. It must be released.
+func NewStringDataType(maxLength int) ExtendedDataType {
+	return ownedExtendedDataType(C.GDALExtendedDataTypeCreateString(C.size_t(maxLength)))
+}
+
+// Release releases the data type. It must be called once, unless handle
+// tracking is on, in which case releasing it again is a no-op.
+func (edt ExtendedDataType) Release() {
+	if edt.cval != nil && edt.ref.Close() {
+		C.GDALExtendedDataTypeRelease(edt.cval)
+	}
+}
+
+// Name returns the name of the data type, set for compound types only.
+func (edt ExtendedDataType) Name() string {
+	defer runtime.KeepAlive(edt.ref)
+	return C.GoString(C.GDALExtendedDataTypeGetName(edt.cval))
+}
+
+// Class returns the class of the data type.
+func (edt ExtendedDataType) Class() ExtendedDataTypeClass {
+	defer runtime.KeepAlive(edt.ref)
+	return ExtendedDataTypeClass(C.GDALExtendedDataTypeGetClass(edt.cval))
+}
+
+// NumericDataType returns the DataType of a numeric data type, Unknown
+// for the other classes.
+func (edt ExtendedDataType) NumericDataType() DataType {
+	defer runtime.KeepAlive(edt.ref)
+	return DataType(C.GDALExtendedDataTypeGetNumericDataType(edt.cval))
+}
+
+// Size returns the size of a value of the data type, in bytes.
+func (edt ExtendedDataType) Size() int {
+	defer runtime.KeepAlive(edt.ref)
+	return int(C.GDALExtendedDataTypeGetSize(edt.cval))
+}
+
+// CanConvertTo reports whether values of the data type can be converted
+// to other.
+func (edt ExtendedDataType) CanConvertTo(other ExtendedDataType) bool {
+	defer runtime.KeepAlive(edt.ref)
+	defer runtime.KeepAlive(other.ref)
+	return C.GDALExtendedDataTypeCanConvertTo(edt.cval, other.cval) != 0
+}
+
+// Equals reports whether both data types are the same.
+func (edt ExtendedDataType) Equals(other ExtendedDataType) bool {
+	defer runtime.KeepAlive(edt.ref)
+	defer runtime.KeepAlive(other.ref)
+	return C.GDALExtendedDataTypeEquals(edt.cval, other.cval) != 0
+}
+
+/* -------------------------------------------------------------------- */
+/*      Helpers                                                         */
+/* -------------------------------------------------------------------- */
+
+// mdCall runs a multidimensional API call returning a boolean, turning
+// failures into errors like cplCall.
+func mdCall(op string, fn func() C.int) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	if fn() == 0 {
+		return failure(op, op+" failed")
+	}
+	return nil
+}
+
+// cStringList converts list to a NULL terminated C string list, NULL if
+// list is empty. free releases it.
+func cStringList(list []string) (**C.char, func()) {
+	if len(list) == 0 {
+		return nil, func() {}
+	}
+	cList := make([]*C.char, len(list)+1)
+	for i, s := range list {
+		cList[i] = C.CString(s)
+	}
+	return &cList[0], func() {
+		for _, s := range cList[:len(list)] {
+			C.free(unsafe.Pointer(s))
+		}
+	}
+}
+
+// goStringList copies a NULL terminated C string list.
+func goStringList(list **C.char) []string {
+	if list == nil {
+		return nil
+	}
+	var result []string
+	for p := list; *p != nil; p = (**C.char)(unsafe.Add(unsafe.Pointer(p), unsafe.Sizeof(*p))) {
+		result = append(result, C.GoString(*p))
+	}
+	return result
+}
+
+func sliceData[T any](s []T) unsafe.Pointer {
+	if len(s) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(&s[0])
+}
+
+func uint64List(values []uint64) []C.GUInt64 {
+	list := make([]C.GUInt64, len(values)+1)
+	for i, value := range values {
+		list[i] = C.GUInt64(value)
+	}
+	return list
+}
+
+func dimensionList(dims *C.GDALDimensionH, count C.size_t) []Dimension {
+	if dims == nil {
+		return nil
+	}
+	defer C.VSIFree(unsafe.Pointer(dims))
+	result := make([]Dimension, int(count))
+	for i, dim := range unsafe.Slice(dims, int(count)) {
+		result[i] = ownedDimension(dim)
+	}
+	return result
+}
+
+func attributeList(attrs *C.GDALAttributeH, count C.size_t) []Attribute {
+	if attrs == nil {
+		return nil
+	}
+	defer C.VSIFree(unsafe.Pointer(attrs))
+	result := make([]Attribute, int(count))
+	for i, attr := range unsafe.Slice(attrs, int(count)) {
+		result[i] = ownedAttribute(attr)
+	}
+	return result
+}
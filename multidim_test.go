@@ -0,0 +1,128 @@
+package gdal
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMultidim(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := driver.CreateMultiDimensional("", nil, nil)
+	if err != nil {
+		t.Fatalf("CreateMultiDimensional: %v", err)
+	}
+	defer ds.Close()
+	root, err := ds.RootGroup()
+	if err != nil {
+		t.Fatalf("RootGroup: %v", err)
+	}
+	defer root.Release()
+
+	var dims []Dimension
+	for _, d := range []struct {
+		name, dimType string
+		size          uint64
+	}{{"time", "TEMPORAL", 2}, {"y", "HORIZONTAL_Y", 2}, {"x", "HORIZONTAL_X", 3}} {
+		dim, err := root.CreateDimension(d.name, d.dimType, "", d.size, nil)
+		if err != nil {
+			t.Fatalf("CreateDimension: %v", err)
+		}
+		defer dim.Release()
+		dims = append(dims, dim)
+	}
+	dataType := NewExtendedDataType(Float32)
+	defer dataType.Release()
+	array, err := root.CreateMDArray("temperature", dims, dataType, nil)
+	if err != nil {
+		t.Fatalf("CreateMDArray: %v", err)
+	}
+	defer array.Release()
+
+	values := make([]float64, 12)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	if err := WriteMDArray(array, Hyperslab{Count: []int{2, 2, 3}}, values); err != nil {
+		t.Fatalf("WriteMDArray: %v", err)
+	}
+	if err := WriteMDArray(array, Hyperslab{Count: []int{2, 2}}, values[:4]); err == nil {
+		t.Error("hyperslab of the wrong rank: got no error")
+	}
+
+	if names := root.MDArrayNames(nil); len(names) != 1 || names[0] != "temperature" {
+		t.Errorf("got array names %v", names)
+	}
+	if shape := array.Shape(); fmt.Sprint(shape) != "[2 2 3]" {
+		t.Errorf("got shape %v", shape)
+	}
+	if dt := array.DataType(); dt.Class() != EDTC_Numeric || dt.NumericDataType() != Float32 || dt.Size() != 4 {
+		t.Errorf("got data type %v %v %v", dt.Class(), dt.NumericDataType(), dt.Size())
+	}
+
+	got, err := ReadMDArray[int32](array, Hyperslab{Start: []uint64{1, 0, 0}, Count: []int{1, 2, 2}, Step: []int64{1, 1, 2}})
+	if err != nil {
+		t.Fatalf("ReadMDArray: %v", err)
+	}
+	if fmt.Sprint(got) != "[6 8 9 11]" {
+		t.Errorf("hyperslab: got %v, want [6 8 9 11]", got)
+	}
+
+	view, err := array.View("[1,:,0]")
+	if err != nil {
+		t.Fatalf("View: %v", err)
+	}
+	defer view.Release()
+	if got, err := ReadMDArray[float32](view, Hyperslab{Count: []int{2}}); err != nil || fmt.Sprint(got) != "[6 9]" {
+		t.Errorf("view: got %v, %v, want [6 9]", got, err)
+	}
+
+	transposed, err := array.Transpose([]int{2, 1, 0})
+	if err != nil {
+		t.Fatalf("Transpose: %v", err)
+	}
+	defer transposed.Release()
+	if got, err := ReadMDArray[float32](transposed, Hyperslab{Start: []uint64{1, 0, 0}, Count: []int{1, 1, 2}}); err != nil || fmt.Sprint(got) != "[1 7]" {
+		t.Errorf("transpose: got %v, %v, want [1 7]", got, err)
+	}
+
+	classic, err := array.AsClassicDataset(2, 1)
+	if err != nil {
+		t.Fatalf("AsClassicDataset: %v", err)
+	}
+	defer classic.Close()
+	if classic.RasterXSize() != 3 || classic.RasterYSize() != 2 || classic.RasterCount() != 2 {
+		t.Errorf("got classic dataset of %dx%dx%d", classic.RasterXSize(), classic.RasterYSize(), classic.RasterCount())
+	}
+
+	stringType := NewStringDataType(0)
+	defer stringType.Release()
+	attr, err := array.CreateAttribute("units", nil, stringType, nil)
+	if err != nil {
+		t.Fatalf("CreateAttribute: %v", err)
+	}
+	defer attr.Release()
+	if err := attr.WriteString("K"); err != nil {
+		t.Fatalf("WriteString: %v", err)
+	}
+	doubles := NewExtendedDataType(Float64)
+	defer doubles.Release()
+	levels, err := root.CreateAttribute("levels", []uint64{3}, doubles, nil)
+	if err != nil {
+		t.Fatalf("CreateAttribute: %v", err)
+	}
+	defer levels.Release()
+	if err := levels.WriteFloat64s([]float64{850, 500, 250}); err != nil {
+		t.Fatalf("WriteFloat64s: %v", err)
+	}
+	if attr, err := array.Attribute("units"); err != nil || attr.String() != "K" {
+		t.Errorf("got attribute %v, %v", attr, err)
+	} else {
+		attr.Release()
+	}
+	if attrs := root.Attributes(nil); len(attrs) != 1 || fmt.Sprint(attrs[0].Float64s()) != "[850 500 250]" || fmt.Sprint(attrs[0].Shape()) != "[3]" {
+		t.Errorf("got attributes %v", attrs)
+	}
+}
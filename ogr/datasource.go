@@ -7,6 +7,7 @@ package ogr
 import "C"
 
 import (
+	"runtime"
 	"unsafe"
 )
 
@@ -71,7 +72,7 @@ func (ds DataSource) LayerCount() int {
 // Fetch a layer of this data source by index
 func (ds DataSource) LayerByIndex(index int) Layer {
 	layer := C.OGR_DS_GetLayer(ds.cval, C.int(index))
-	return Layer{layer}
+	return Layer{cval: layer}
 }
 
 // Fetch a layer of this data source by name
@@ -79,7 +80,7 @@ func (ds DataSource) LayerByName(name string) Layer {
 	cString := C.CString(name)
 	defer C.free(unsafe.Pointer(cString))
 	layer := C.OGR_DS_GetLayerByName(ds.cval, cString)
-	return Layer{layer}
+	return Layer{cval: layer}
 }
 
 // Delete the layer from the data source
@@ -100,6 +101,7 @@ func (ds DataSource) CreateLayer(
 	geomType GeometryType,
 	options []string,
 ) Layer {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 
@@ -118,7 +120,7 @@ func (ds DataSource) CreateLayer(
 		C.OGRwkbGeometryType(geomType),
 		(**C.char)(unsafe.Pointer(&opts[0])),
 	)
-	return Layer{layer}
+	return Layer{cval: layer}
 }
 
 // Duplicate an existing layer
@@ -127,6 +129,7 @@ func (ds DataSource) CopyLayer(
 	name string,
 	options []string,
 ) Layer {
+	defer runtime.KeepAlive(source.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 
@@ -144,7 +147,7 @@ func (ds DataSource) CopyLayer(
 		cName,
 		(**C.char)(unsafe.Pointer(&opts[0])),
 	)
-	return Layer{layer}
+	return Layer{cval: layer}
 }
 
 // Test if the data source has the indicated capability
@@ -157,16 +160,18 @@ func (ds DataSource) TestCapability(capability string) bool {
 
 // Execute an SQL statement against the data source
 func (ds DataSource) ExecuteSQL(sql string, filter Geometry, dialect string) Layer {
+	defer runtime.KeepAlive(filter.ref)
 	cSQL := C.CString(sql)
 	defer C.free(unsafe.Pointer(cSQL))
 	cDialect := C.CString(dialect)
 	defer C.free(unsafe.Pointer(cDialect))
 	layer := C.OGR_DS_ExecuteSQL(ds.cval, cSQL, filter.cval, cDialect)
-	return Layer{layer}
+	return Layer{cval: layer}
 }
 
 // Release the results of ExecuteSQL
 func (ds DataSource) ReleaseResultSet(layer Layer) {
+	defer runtime.KeepAlive(layer.ref)
 	C.OGR_DS_ReleaseResultSet(ds.cval, layer.cval)
 }
 
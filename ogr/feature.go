@@ -8,8 +8,11 @@ import "C"
 
 import (
 	"reflect"
+	"runtime"
 	"time"
 	"unsafe"
+
+	"github.com/airmap/gdal/internal/handle"
 )
 
 /* -------------------------------------------------------------------- */
@@ -18,73 +21,91 @@ import (
 
 type Feature struct {
 	cval C.OGRFeatureH
+	ref  *handle.Ref
 }
 
 // Create a feature from this feature definition
 func (fd FeatureDefinition) Create() Feature {
 	feature := C.OGR_F_Create(fd.cval)
-	return Feature{feature}
+	return ownedFeature(feature)
 }
 
-// Destroy this feature
+// Destroy this feature. With handle tracking on, destroying it again is a
+// no-op.
 func (feature Feature) Destroy() {
-	C.OGR_F_Destroy(feature.cval)
+	if feature.ref.Close() {
+		C.OGR_F_Destroy(feature.cval)
+	}
 }
 
 // Fetch feature definition
 func (feature Feature) Definition() FeatureDefinition {
+	defer runtime.KeepAlive(feature.ref)
 	fd := C.OGR_F_GetDefnRef(feature.cval)
 	return FeatureDefinition{fd}
 }
 
 // Set feature geometry
 func (feature Feature) SetGeometry(geom Geometry) error {
+	defer runtime.KeepAlive(feature.ref)
+	defer runtime.KeepAlive(geom.ref)
 	return C.OGR_F_SetGeometry(feature.cval, geom.cval).Err()
 }
 
 // Set feature geometry, passing ownership to the feature
 func (feature Feature) SetGeometryDirectly(geom Geometry) error {
+	defer runtime.KeepAlive(feature.ref)
+	defer runtime.KeepAlive(geom.ref)
+	geom.ref.Disown(feature.ref)
 	return C.OGR_F_SetGeometryDirectly(feature.cval, geom.cval).Err()
 }
 
 // Fetch geometry of this feature
 func (feature Feature) Geometry() Geometry {
+	defer runtime.KeepAlive(feature.ref)
 	geom := C.OGR_F_GetGeometryRef(feature.cval)
-	return Geometry{geom}
+	return Geometry{geom, handle.Borrowed(feature.ref)}
 }
 
 // Fetch geometry of this feature and assume ownership
 func (feature Feature) StealGeometry() Geometry {
+	defer runtime.KeepAlive(feature.ref)
 	geom := C.OGR_F_StealGeometry(feature.cval)
-	return Geometry{geom}
+	return ownedGeometry(geom)
 }
 
 // Duplicate feature
 func (feature Feature) Clone() Feature {
+	defer runtime.KeepAlive(feature.ref)
 	newFeature := C.OGR_F_Clone(feature.cval)
-	return Feature{newFeature}
+	return ownedFeature(newFeature)
 }
 
 // Test if two features are the same
 func (f1 Feature) Equal(f2 Feature) bool {
+	defer runtime.KeepAlive(f1.ref)
+	defer runtime.KeepAlive(f2.ref)
 	equal := C.OGR_F_Equal(f1.cval, f2.cval)
 	return equal != 0
 }
 
 // Fetch number of fields on this feature
 func (feature Feature) FieldCount() int {
+	defer runtime.KeepAlive(feature.ref)
 	count := C.OGR_F_GetFieldCount(feature.cval)
 	return int(count)
 }
 
 // Fetch definition for the indicated field
 func (feature Feature) FieldDefinition(index int) FieldDefinition {
+	defer runtime.KeepAlive(feature.ref)
 	defn := C.OGR_F_GetFieldDefnRef(feature.cval, C.int(index))
 	return FieldDefinition{defn}
 }
 
 // Fetch the field index for the given field name
 func (feature Feature) FieldIndex(name string) int {
+	defer runtime.KeepAlive(feature.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	index := C.OGR_F_GetFieldIndex(feature.cval, cName)
@@ -93,34 +114,40 @@ func (feature Feature) FieldIndex(name string) int {
 
 // Return if a field has ever been assigned a value
 func (feature Feature) IsFieldSet(index int) bool {
+	defer runtime.KeepAlive(feature.ref)
 	set := C.OGR_F_IsFieldSet(feature.cval, C.int(index))
 	return set != 0
 }
 
 // Clear a field and mark it as unset
 func (feature Feature) UnnsetField(index int) {
+	defer runtime.KeepAlive(feature.ref)
 	C.OGR_F_UnsetField(feature.cval, C.int(index))
 }
 
 // Test if a field is null.
 func (feature Feature) IsFieldNull(index int) bool {
+	defer runtime.KeepAlive(feature.ref)
 	isnull := C.OGR_F_IsFieldNull(feature.cval, C.int(index))
 	return int(isnull) == 1
 }
 
 // Test if a field is set and not null.
 func (feature Feature) IsFieldSetAndNotNull(index int) bool {
+	defer runtime.KeepAlive(feature.ref)
 	i := C.OGR_F_IsFieldSetAndNotNull(feature.cval, C.int(index))
 	return int(i) == 1
 }
 
 // Clear a field, marking it as null.
 func (feature Feature) SetFieldNull(index int) {
+	defer runtime.KeepAlive(feature.ref)
 	C.OGR_F_SetFieldNull(feature.cval, C.int(index))
 }
 
 // Fetch a reference to the internal field value
 func (feature Feature) RawField(index int) Field {
+	defer runtime.KeepAlive(feature.ref)
 	field := C.OGR_F_GetRawFieldRef(feature.cval, C.int(index))
 	return Field{field}
 }
@@ -134,30 +161,35 @@ func (feature Feature) RawField(index int) Field {
 
 // Fetch field value as integer
 func (feature Feature) FieldAsInteger(index int) int {
+	defer runtime.KeepAlive(feature.ref)
 	val := C.OGR_F_GetFieldAsInteger(feature.cval, C.int(index))
 	return int(val)
 }
 
 // Fetch field value as 64-bit integer
 func (feature Feature) FieldAsInteger64(index int) int64 {
+	defer runtime.KeepAlive(feature.ref)
 	val := C.OGR_F_GetFieldAsInteger64(feature.cval, C.int(index))
 	return int64(val)
 }
 
 // Fetch field value as float64
 func (feature Feature) FieldAsFloat64(index int) float64 {
+	defer runtime.KeepAlive(feature.ref)
 	val := C.OGR_F_GetFieldAsDouble(feature.cval, C.int(index))
 	return float64(val)
 }
 
 // Fetch field value as string
 func (feature Feature) FieldAsString(index int) string {
+	defer runtime.KeepAlive(feature.ref)
 	val := C.OGR_F_GetFieldAsString(feature.cval, C.int(index))
 	return C.GoString(val)
 }
 
 // Fetch field as list of integers
 func (feature Feature) FieldAsIntegerList(index int) []int {
+	defer runtime.KeepAlive(feature.ref)
 	var count int
 	cArray := C.OGR_F_GetFieldAsIntegerList(feature.cval, C.int(index), (*C.int)(unsafe.Pointer(&count)))
 	var goSlice []int
@@ -170,6 +202,7 @@ func (feature Feature) FieldAsIntegerList(index int) []int {
 
 // Fetch field as list of 64-bit integers
 func (feature Feature) FieldAsInteger64List(index int) []int64 {
+	defer runtime.KeepAlive(feature.ref)
 	var count int
 	cArray := C.OGR_F_GetFieldAsInteger64List(feature.cval, C.int(index), (*C.int)(unsafe.Pointer(&count)))
 	var goSlice []int64
@@ -182,6 +215,7 @@ func (feature Feature) FieldAsInteger64List(index int) []int64 {
 
 // Fetch field as list of float64
 func (feature Feature) FieldAsFloat64List(index int) []float64 {
+	defer runtime.KeepAlive(feature.ref)
 	var count int
 	cArray := C.OGR_F_GetFieldAsDoubleList(feature.cval, C.int(index), (*C.int)(unsafe.Pointer(&count)))
 	var goSlice []float64
@@ -194,6 +228,7 @@ func (feature Feature) FieldAsFloat64List(index int) []float64 {
 
 // Fetch field as list of strings
 func (feature Feature) FieldAsStringList(index int) []string {
+	defer runtime.KeepAlive(feature.ref)
 	p := C.OGR_F_GetFieldAsStringList(feature.cval, C.int(index))
 
 	var strings []string
@@ -212,6 +247,7 @@ func (feature Feature) FieldAsStringList(index int) []string {
 
 // Fetch field as binary data
 func (feature Feature) FieldAsBinary(index int) []uint8 {
+	defer runtime.KeepAlive(feature.ref)
 	var count int
 	cArray := C.OGR_F_GetFieldAsBinary(feature.cval, C.int(index), (*C.int)(unsafe.Pointer(&count)))
 	var goSlice []uint8
@@ -224,6 +260,7 @@ func (feature Feature) FieldAsBinary(index int) []uint8 {
 
 // Fetch field as date and time
 func (feature Feature) FieldAsDateTime(index int) (time.Time, bool) {
+	defer runtime.KeepAlive(feature.ref)
 	var year, month, day, hour, minute, second, tzFlag int
 	success := C.OGR_F_GetFieldAsDateTime(
 		feature.cval,
@@ -244,21 +281,25 @@ func (feature Feature) FieldAsDateTime(index int) (time.Time, bool) {
 
 // Set field to integer value
 func (feature Feature) SetFieldInteger(index, value int) {
+	defer runtime.KeepAlive(feature.ref)
 	C.OGR_F_SetFieldInteger(feature.cval, C.int(index), C.int(value))
 }
 
 // Set field to 64-bit integer value
 func (feature Feature) SetFieldInteger64(index int, value int64) {
+	defer runtime.KeepAlive(feature.ref)
 	C.OGR_F_SetFieldInteger64(feature.cval, C.int(index), C.GIntBig(value))
 }
 
 // Set field to float64 value
 func (feature Feature) SetFieldFloat64(index int, value float64) {
+	defer runtime.KeepAlive(feature.ref)
 	C.OGR_F_SetFieldDouble(feature.cval, C.int(index), C.double(value))
 }
 
 // Set field to string value
 func (feature Feature) SetFieldString(index int, value string) {
+	defer runtime.KeepAlive(feature.ref)
 	cVal := C.CString(value)
 	defer C.free(unsafe.Pointer(cVal))
 	C.OGR_F_SetFieldString(feature.cval, C.int(index), cVal)
@@ -266,6 +307,7 @@ func (feature Feature) SetFieldString(index int, value string) {
 
 // Set field to list of integers
 func (feature Feature) SetFieldIntegerList(index int, value []int) {
+	defer runtime.KeepAlive(feature.ref)
 	C.OGR_F_SetFieldIntegerList(
 		feature.cval,
 		C.int(index),
@@ -276,6 +318,7 @@ func (feature Feature) SetFieldIntegerList(index int, value []int) {
 
 // Set field to list of 64-bit integers
 func (feature Feature) SetFieldInteger64List(index int, value []int64) {
+	defer runtime.KeepAlive(feature.ref)
 	C.OGR_F_SetFieldIntegerList(
 		feature.cval,
 		C.int(index),
@@ -286,6 +329,7 @@ func (feature Feature) SetFieldInteger64List(index int, value []int64) {
 
 // Set field to list of float64
 func (feature Feature) SetFieldFloat64List(index int, value []float64) {
+	defer runtime.KeepAlive(feature.ref)
 	C.OGR_F_SetFieldDoubleList(
 		feature.cval,
 		C.int(index),
@@ -296,6 +340,7 @@ func (feature Feature) SetFieldFloat64List(index int, value []float64) {
 
 // Set field to list of strings
 func (feature Feature) SetFieldStringList(index int, value []string) {
+	defer runtime.KeepAlive(feature.ref)
 	length := len(value)
 	cValue := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -313,6 +358,7 @@ func (feature Feature) SetFieldStringList(index int, value []string) {
 
 // Set field from the raw field pointer
 func (feature Feature) SetFieldRaw(index int, field Field) {
+	defer runtime.KeepAlive(feature.ref)
 	C.OGR_F_SetFieldRaw(feature.cval, C.int(index), field.cval)
 }
 
@@ -328,6 +374,7 @@ func (feature Feature) SetFieldRaw(index int, field Field) {
 
 // Set field as date / time
 func (feature Feature) SetFieldDateTime(index int, dt time.Time) {
+	defer runtime.KeepAlive(feature.ref)
 	C.OGR_F_SetFieldDateTime(
 		feature.cval,
 		C.int(index),
@@ -343,6 +390,7 @@ func (feature Feature) SetFieldDateTime(index int, dt time.Time) {
 
 // Set field as date / time
 func (feature Feature) SetFieldDateTimeEx(index int, dt time.Time) {
+	defer runtime.KeepAlive(feature.ref)
 	C.OGR_F_SetFieldDateTimeEx(
 		feature.cval,
 		C.int(index),
@@ -358,6 +406,7 @@ func (feature Feature) SetFieldDateTimeEx(index int, dt time.Time) {
 
 // Fetch number of geometry fields on this feature This will always be the same as the geometry field count for the OGRFeatureDefn.
 func (feature Feature) GeometryFieldCount() int {
+	defer runtime.KeepAlive(feature.ref)
 	count := C.OGR_F_GetGeomFieldCount(feature.cval)
 	return int(count)
 }
@@ -365,12 +414,14 @@ func (feature Feature) GeometryFieldCount() int {
 // Fetch definition for this geometry field.
 // index: the field to fetch, from 0 to GetGeomFieldCount()-1.
 func (feature Feature) GeometryFieldDefition(index int) GeomFieldDefinition {
+	defer runtime.KeepAlive(feature.ref)
 	gfd := C.OGR_F_GetGeomFieldDefnRef(feature.cval, C.int(index))
 	return GeomFieldDefinition{gfd}
 }
 
 // Fetch the geometry field index given geometry field name.
 func (feature Feature) GeometryFieldIndex(name string) int {
+	defer runtime.KeepAlive(feature.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	index := C.OGR_F_GetGeomFieldIndex(feature.cval, cName)
@@ -379,14 +430,18 @@ func (feature Feature) GeometryFieldIndex(name string) int {
 
 // Fetch a handle to feature geometry.
 func (feature Feature) GeometryField(index int) Geometry {
+	defer runtime.KeepAlive(feature.ref)
 	geom := C.OGR_F_GetGeomFieldRef(feature.cval, C.int(index))
-	return Geometry{geom}
+	return Geometry{geom, handle.Borrowed(feature.ref)}
 }
 
 // Set feature geometry of a specified geometry field.
 // This function updates the features geometry, and operate exactly as SetGeomField(),
 //  except that this function assumes ownership of the passed geometry (even in case of failure of that function).
 func (feature Feature) SetGeometryFieldDirectly(index int, geom Geometry) error {
+	defer runtime.KeepAlive(feature.ref)
+	defer runtime.KeepAlive(geom.ref)
+	geom.ref.Disown(feature.ref)
 	return C.OGR_F_SetGeomFieldDirectly(feature.cval, C.int(index), geom.cval).Err()
 }
 
@@ -394,17 +449,21 @@ func (feature Feature) SetGeometryFieldDirectly(index int, geom Geometry) error
 // This function updates the features geometry, and operate exactly as SetGeometryDirectly(),
 //  except that this function does not assume ownership of the passed geometry, but instead makes a copy of it.
 func (feature Feature) SetGeometryField(index int, geom Geometry) error {
+	defer runtime.KeepAlive(feature.ref)
+	defer runtime.KeepAlive(geom.ref)
 	return C.OGR_F_SetGeomField(feature.cval, C.int(index), geom.cval).Err()
 }
 
 // Fetch feature indentifier
 func (feature Feature) FID() int64 {
+	defer runtime.KeepAlive(feature.ref)
 	fid := C.OGR_F_GetFID(feature.cval)
 	return int64(fid)
 }
 
 // Set feature identifier
 func (feature Feature) SetFID(fid int64) error {
+	defer runtime.KeepAlive(feature.ref)
 	return C.OGR_F_SetFID(feature.cval, C.GIntBig(fid)).Err()
 }
 
@@ -412,11 +471,15 @@ func (feature Feature) SetFID(fid int64) error {
 
 // Set one feature from another
 func (this Feature) SetFrom(other Feature, forgiving int) error {
+	defer runtime.KeepAlive(this.ref)
+	defer runtime.KeepAlive(other.ref)
 	return C.OGR_F_SetFrom(this.cval, other.cval, C.int(forgiving)).Err()
 }
 
 // Set one feature from another, using field map
 func (this Feature) SetFromWithMap(other Feature, forgiving int, fieldMap []int) error {
+	defer runtime.KeepAlive(this.ref)
+	defer runtime.KeepAlive(other.ref)
 	return C.OGR_F_SetFromWithMap(
 		this.cval,
 		other.cval,
@@ -427,12 +490,14 @@ func (this Feature) SetFromWithMap(other Feature, forgiving int, fieldMap []int)
 
 // Fetch style string for this feature
 func (feature Feature) StlyeString() string {
+	defer runtime.KeepAlive(feature.ref)
 	style := C.OGR_F_GetStyleString(feature.cval)
 	return C.GoString(style)
 }
 
 // Set style string for this feature
 func (feature Feature) SetStyleString(style string) {
+	defer runtime.KeepAlive(feature.ref)
 	cStyle := C.CString(style)
 	defer C.free(unsafe.Pointer(cStyle))
 	C.OGR_F_SetStyleStringDirectly(feature.cval, cStyle)
@@ -440,22 +505,26 @@ func (feature Feature) SetStyleString(style string) {
 
 // Returns the native data for the feature.
 func (feature Feature) NativeData() string {
+	defer runtime.KeepAlive(feature.ref)
 	nd := C.OGR_F_GetNativeData(feature.cval)
 	return C.GoString(nd)
 }
 
 func (feature Feature) SetNativeData(nativeData string) {
+	defer runtime.KeepAlive(feature.ref)
 	nd := C.CString(nativeData)
 	defer C.free(unsafe.Pointer(nd))
 	C.OGR_F_SetNativeData(feature.cval, nd)
 }
 
 func (feature Feature) NativeMediaType() string {
+	defer runtime.KeepAlive(feature.ref)
 	mt := C.OGR_F_GetNativeMediaType(feature.cval)
 	return C.GoString(mt)
 }
 
 func (feature Feature) SetNativeMediaType(mediatype string) {
+	defer runtime.KeepAlive(feature.ref)
 	mt := C.CString(mediatype)
 	defer C.free(unsafe.Pointer(mt))
 	C.OGR_F_SetNativeMediaType(feature.cval, mt)
@@ -464,16 +533,19 @@ func (feature Feature) SetNativeMediaType(mediatype string) {
 // Fill unset fields with default values that might be defined.
 // note: papszOptions: unused currently. Must be set to NULL.
 func (feature Feature) FillUnsetWithDefault(notNullableOnly bool) {
+	defer runtime.KeepAlive(feature.ref)
 	var papszOptions **C.char = nil
 	C.OGR_F_FillUnsetWithDefault(feature.cval, BoolToCInt(notNullableOnly), papszOptions)
 }
 
 func (feature Feature) Validate(validateFlags int, emitError int) int {
+	defer runtime.KeepAlive(feature.ref)
 	v := C.OGR_F_Validate(feature.cval, C.int(validateFlags), C.int(emitError))
 	return int(v)
 }
 
 // Returns true if this contains a null pointer
 func (feature Feature) IsNull() bool {
+	defer runtime.KeepAlive(feature.ref)
 	return feature.cval == nil
 }
@@ -7,7 +7,10 @@ package ogr
 import "C"
 
 import (
+	"runtime"
 	"unsafe"
+
+	"github.com/airmap/gdal/internal/handle"
 )
 
 /* -------------------------------------------------------------------- */
@@ -202,11 +205,12 @@ func (gfd GeomFieldDefinition) SetType(geomType GeometryType) {
 // Fetch spatial reference system of this field.
 func (gfd GeomFieldDefinition) SpatialReference() SpatialReference {
 	sr := C.OGR_GFld_GetSpatialRef(gfd.cval)
-	return SpatialReference{sr}
+	return SpatialReference{sr, handle.Borrowed(nil)}
 }
 
 // Set the spatial reference of this field.
 func (gfd GeomFieldDefinition) SetSpatialReference(sr SpatialReference) {
+	defer runtime.KeepAlive(sr.ref)
 	C.OGR_GFld_SetSpatialRef(gfd.cval, sr.cval)
 }
 
@@ -7,7 +7,10 @@ package ogr
 import "C"
 
 import (
+	"runtime"
 	"unsafe"
+
+	"github.com/airmap/gdal/internal/handle"
 )
 
 /* -------------------------------------------------------------------- */
@@ -16,45 +19,52 @@ import (
 
 type Geometry struct {
 	cval C.OGRGeometryH
+	ref  *handle.Ref
 }
 
 //Create a geometry object from its well known binary representation
 func CreateFromWKB(wkb []uint8, srs SpatialReference, bytes int) (Geometry, error) {
+	defer runtime.KeepAlive(srs.ref)
 	cString := unsafe.Pointer(&wkb[0])
-	var newGeom Geometry
-	return newGeom, C.go_CreateFromWkb(
-		cString, srs.cval, &newGeom.cval, C.int(bytes),
+	var newGeom C.OGRGeometryH
+	err := C.go_CreateFromWkb(
+		cString, srs.cval, &newGeom, C.int(bytes),
 	).Err()
+	return ownedGeometry(newGeom), err
 }
 
 //Create a geometry object from its well known text representation
 func CreateFromWKT(wkt string, srs SpatialReference) (Geometry, error) {
+	defer runtime.KeepAlive(srs.ref)
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
-	var newGeom Geometry
-	return newGeom, C.OGR_G_CreateFromWkt(
-		&cString, srs.cval, &newGeom.cval,
+	var newGeom C.OGRGeometryH
+	err := C.OGR_G_CreateFromWkt(
+		&cString, srs.cval, &newGeom,
 	).Err()
+	return ownedGeometry(newGeom), err
 }
 
 //Create a geometry object from its GeoJSON representation
 func CreateFromJson(_json string) Geometry {
 	cString := C.CString(_json)
 	defer C.free(unsafe.Pointer(cString))
-	var newGeom Geometry
-	newGeom.cval = C.OGR_G_CreateGeometryFromJson(cString)
-	return newGeom
+	newGeom := C.OGR_G_CreateGeometryFromJson(cString)
+	return ownedGeometry(newGeom)
 }
 
-// Destroy geometry object
+// Destroy geometry object. With handle tracking on, destroying it again or
+// destroying a borrowed geometry is a no-op.
 func (geometry Geometry) Destroy() {
-	C.OGR_G_DestroyGeometry(geometry.cval)
+	if geometry.ref.Close() {
+		C.OGR_G_DestroyGeometry(geometry.cval)
+	}
 }
 
 // Create an empty geometry of the desired type
 func Create(geomType GeometryType) Geometry {
 	geom := C.OGR_G_CreateGeometry(C.OGRwkbGeometryType(geomType))
-	return Geometry{geom}
+	return ownedGeometry(geom)
 }
 
 // Stroke arc to linestring
@@ -77,58 +87,71 @@ func ApproximateArcAngles(
 		C.double(startAngle),
 		C.double(endAngle),
 		C.double(stepSizeDegrees))
-	return Geometry{geom}
+	return ownedGeometry(geom)
 }
 
 // Convert to polygon
 func (geom Geometry) ForceToPolygon() Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_ForceToPolygon(geom.cval)
-	return Geometry{newGeom}
+	geom.ref.Disown(nil)
+	return ownedGeometry(newGeom)
 }
 
 // Convert to multipolygon
 func (geom Geometry) ForceToMultiPolygon() Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_ForceToMultiPolygon(geom.cval)
-	return Geometry{newGeom}
+	geom.ref.Disown(nil)
+	return ownedGeometry(newGeom)
 }
 
 // Convert to multipoint
 func (geom Geometry) ForceToMultiPoint() Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_ForceToMultiPoint(geom.cval)
-	return Geometry{newGeom}
+	geom.ref.Disown(nil)
+	return ownedGeometry(newGeom)
 }
 
 // Convert to multilinestring
 func (geom Geometry) ForceToMultiLineString() Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_ForceToMultiLineString(geom.cval)
-	return Geometry{newGeom}
+	geom.ref.Disown(nil)
+	return ownedGeometry(newGeom)
 }
 
 // Get the dimension of this geometry
 func (geom Geometry) Dimension() int {
+	defer runtime.KeepAlive(geom.ref)
 	dim := C.OGR_G_GetDimension(geom.cval)
 	return int(dim)
 }
 
 // Get the dimension of the coordinates in this geometry
 func (geom Geometry) CoordinateDimension() int {
+	defer runtime.KeepAlive(geom.ref)
 	dim := C.OGR_G_GetCoordinateDimension(geom.cval)
 	return int(dim)
 }
 
 // Set the dimension of the coordinates in this geometry
 func (geom Geometry) SetCoordinateDimension(dim int) {
+	defer runtime.KeepAlive(geom.ref)
 	C.OGR_G_SetCoordinateDimension(geom.cval, C.int(dim))
 }
 
 // Create a copy of this geometry
 func (geom Geometry) Clone() Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_Clone(geom.cval)
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 // Compute and return the bounding envelope for this geometry
 func (geom Geometry) Envelope() Envelope {
+	defer runtime.KeepAlive(geom.ref)
 	var env Envelope
 	C.OGR_G_GetEnvelope(geom.cval, &env.cval)
 	return env
@@ -138,12 +161,14 @@ func (geom Geometry) Envelope() Envelope {
 
 // Assign a geometry from well known binary data
 func (geom Geometry) FromWKB(wkb []uint8, bytes int) error {
+	defer runtime.KeepAlive(geom.ref)
 	cString := unsafe.Pointer(&wkb[0])
 	return C.go_ImportFromWkb(geom.cval, cString, C.int(bytes)).Err()
 }
 
 // Convert a geometry to well known binary data
 func (geom Geometry) ToWKB() ([]uint8, error) {
+	defer runtime.KeepAlive(geom.ref)
 	b := make([]uint8, geom.WKBSize())
 	cString := (*C.uchar)(unsafe.Pointer(&b[0]))
 	err := C.go_ExportToWkb(geom.cval, C.OGRwkbByteOrder(C.wkbNDR), cString).Err()
@@ -152,12 +177,14 @@ func (geom Geometry) ToWKB() ([]uint8, error) {
 
 // Returns size of related binary representation
 func (geom Geometry) WKBSize() int {
+	defer runtime.KeepAlive(geom.ref)
 	size := C.OGR_G_WkbSize(geom.cval)
 	return int(size)
 }
 
 // Assign geometry object from its well known text representation
 func (geom Geometry) FromWKT(wkt string) error {
+	defer runtime.KeepAlive(geom.ref)
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
 	return C.OGR_G_ImportFromWkt(geom.cval, &cString).Err()
@@ -165,6 +192,7 @@ func (geom Geometry) FromWKT(wkt string) error {
 
 // Fetch geometry as WKT
 func (geom Geometry) ToWKT() (string, error) {
+	defer runtime.KeepAlive(geom.ref)
 	var p *C.char
 	err := C.OGR_G_ExportToWkt(geom.cval, &p).Err()
 	wkt := C.GoString(p)
@@ -174,12 +202,14 @@ func (geom Geometry) ToWKT() (string, error) {
 
 // Fetch geometry type
 func (geom Geometry) Type() GeometryType {
+	defer runtime.KeepAlive(geom.ref)
 	gt := C.OGR_G_GetGeometryType(geom.cval)
 	return GeometryType(gt)
 }
 
 // Fetch geometry name
 func (geom Geometry) Name() string {
+	defer runtime.KeepAlive(geom.ref)
 	name := C.OGR_G_GetGeometryName(geom.cval)
 	return C.GoString(name)
 }
@@ -188,11 +218,13 @@ func (geom Geometry) Name() string {
 
 // Convert geometry to strictly 2D
 func (geom Geometry) FlattenTo2D() {
+	defer runtime.KeepAlive(geom.ref)
 	C.OGR_G_FlattenTo2D(geom.cval)
 }
 
 // Force rings to be closed
 func (geom Geometry) CloseRings() {
+	defer runtime.KeepAlive(geom.ref)
 	C.OGR_G_CloseRings(geom.cval)
 }
 
@@ -201,17 +233,19 @@ func CreateFromGML(gml string) Geometry {
 	cString := C.CString(gml)
 	defer C.free(unsafe.Pointer(cString))
 	geom := C.OGR_G_CreateFromGML(cString)
-	return Geometry{geom}
+	return ownedGeometry(geom)
 }
 
 // Convert a geometry to GML format
 func (geom Geometry) ToGML() string {
+	defer runtime.KeepAlive(geom.ref)
 	val := C.OGR_G_ExportToGML(geom.cval)
 	return C.GoString(val)
 }
 
 // Convert a geometry to GML format with options
 func (geom Geometry) ToGML_Ex(options []string) string {
+	defer runtime.KeepAlive(geom.ref)
 	length := len(options)
 	opts := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -226,6 +260,7 @@ func (geom Geometry) ToGML_Ex(options []string) string {
 
 // Convert a geometry to KML format
 func (geom Geometry) ToKML() string {
+	defer runtime.KeepAlive(geom.ref)
 	val := C.OGR_G_ExportToKML(geom.cval, nil)
 	result := C.GoString(val)
 	C.free(unsafe.Pointer(val))
@@ -234,12 +269,14 @@ func (geom Geometry) ToKML() string {
 
 // Convert a geometry to JSON format
 func (geom Geometry) ToJSON() string {
+	defer runtime.KeepAlive(geom.ref)
 	val := C.OGR_G_ExportToJson(geom.cval)
 	return C.GoString(val)
 }
 
 // Convert a geometry to JSON format with options
 func (geom Geometry) ToJSON_ex(options []string) string {
+	defer runtime.KeepAlive(geom.ref)
 	length := len(options)
 	opts := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -254,123 +291,156 @@ func (geom Geometry) ToJSON_ex(options []string) string {
 
 // Fetch the spatial reference associated with this geometry
 func (geom Geometry) SpatialReference() SpatialReference {
+	defer runtime.KeepAlive(geom.ref)
 	spatialRef := C.OGR_G_GetSpatialReference(geom.cval)
-	return SpatialReference{spatialRef}
+	return SpatialReference{spatialRef, handle.Borrowed(geom.ref)}
 }
 
 // Assign a spatial reference to this geometry
 func (geom Geometry) SetSpatialReference(spatialRef SpatialReference) {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(spatialRef.ref)
 	C.OGR_G_AssignSpatialReference(geom.cval, spatialRef.cval)
 }
 
 // Apply coordinate transformation to geometry
 func (geom Geometry) Transform(ct CoordinateTransform) error {
+	defer runtime.KeepAlive(geom.ref)
 	return C.OGR_G_Transform(geom.cval, ct.cval).Err()
 }
 
 // Transform geometry to new spatial reference system
 func (geom Geometry) TransformTo(sr SpatialReference) error {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(sr.ref)
 	return C.OGR_G_TransformTo(geom.cval, sr.cval).Err()
 }
 
 // Simplify the geometry
 func (geom Geometry) Simplify(tolerance float64) Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_Simplify(geom.cval, C.double(tolerance))
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 // Simplify the geometry while preserving topology
 func (geom Geometry) SimplifyPreservingTopology(tolerance float64) Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_SimplifyPreserveTopology(geom.cval, C.double(tolerance))
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 // Modify the geometry such that it has no line segment longer than the given distance
 func (geom Geometry) Segmentize(distance float64) {
+	defer runtime.KeepAlive(geom.ref)
 	C.OGR_G_Segmentize(geom.cval, C.double(distance))
 }
 
 // Return true if these features intersect
 func (geom Geometry) Intersects(other Geometry) bool {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	val := C.OGR_G_Intersects(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if these features are equal
 func (geom Geometry) Equals(other Geometry) bool {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	val := C.OGR_G_Equals(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if the features are disjoint
 func (geom Geometry) Disjoint(other Geometry) bool {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	val := C.OGR_G_Disjoint(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this feature touches the other
 func (geom Geometry) Touches(other Geometry) bool {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	val := C.OGR_G_Touches(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this feature crosses the other
 func (geom Geometry) Crosses(other Geometry) bool {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	val := C.OGR_G_Crosses(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this geometry is within the other
 func (geom Geometry) Within(other Geometry) bool {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	val := C.OGR_G_Within(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this geometry contains the other
 func (geom Geometry) Contains(other Geometry) bool {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	val := C.OGR_G_Contains(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this geometry overlaps the other
 func (geom Geometry) Overlaps(other Geometry) bool {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	val := C.OGR_G_Overlaps(geom.cval, other.cval)
 	return val != 0
 }
 
 // Compute boundary for the geometry
 func (geom Geometry) Boundary() Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_Boundary(geom.cval)
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 // Compute convex hull for the geometry
 func (geom Geometry) ConvexHull() Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_ConvexHull(geom.cval)
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 // Compute buffer of the geometry
 func (geom Geometry) Buffer(distance float64, segments int) Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_Buffer(geom.cval, C.double(distance), C.int(segments))
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 // Compute intersection of this geometry with the other
 func (geom Geometry) Intersection(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	newGeom := C.OGR_G_Intersection(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 // Compute union of this geometry with the other
 func (geom Geometry) Union(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	newGeom := C.OGR_G_Union(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 func (geom Geometry) UnionCascaded() Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_UnionCascaded(geom.cval)
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 // Unimplemented: PointOn Surface (until 2.0)
@@ -382,18 +452,24 @@ func (geom Geometry) UnionCascaded() Geometry {
 
 // Compute difference between this geometry and the other
 func (geom Geometry) Difference(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	newGeom := C.OGR_G_Difference(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 // Compute symmetric difference between this geometry and the other
 func (geom Geometry) SymmetricDifference(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	newGeom := C.OGR_G_SymDifference(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 // Compute distance between thie geometry and the other
 func (geom Geometry) Distance(other Geometry) float64 {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	dist := C.OGR_G_Distance(geom.cval, other.cval)
 	return float64(dist)
 }
@@ -403,24 +479,29 @@ func (geom Geometry) Distance(other Geometry) float64 {
 // check it for the definition of the geometry operation.
 // If OGR is built without the SFCGAL library, this method will always return -1.0
 func (geom Geometry) Distance3D(other Geometry) float64 {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	dist := C.OGR_G_Distance3D(geom.cval, other.cval)
 	return float64(dist)
 }
 
 // Compute length of geometry
 func (geom Geometry) Length() float64 {
+	defer runtime.KeepAlive(geom.ref)
 	length := C.OGR_G_Length(geom.cval)
 	return float64(length)
 }
 
 // Compute area of geometry
 func (geom Geometry) Area() float64 {
+	defer runtime.KeepAlive(geom.ref)
 	area := C.OGR_G_Area(geom.cval)
 	return float64(area)
 }
 
 // Compute centroid of geometry
 func (geom Geometry) Centroid() Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	var centroid Geometry
 	C.OGR_G_Centroid(geom.cval, centroid.cval)
 	return centroid
@@ -428,46 +509,54 @@ func (geom Geometry) Centroid() Geometry {
 
 // Clear the geometry to its uninitialized state
 func (geom Geometry) Empty() {
+	defer runtime.KeepAlive(geom.ref)
 	C.OGR_G_Empty(geom.cval)
 }
 
 // Test if the geometry is empty
 func (geom Geometry) IsEmpty() bool {
+	defer runtime.KeepAlive(geom.ref)
 	val := C.OGR_G_IsEmpty(geom.cval)
 	return val != 0
 }
 
 // Test if the geometry is null
 func (geom Geometry) IsNull() bool {
+	defer runtime.KeepAlive(geom.ref)
 	return geom.cval == nil
 }
 
 // Test if the geometry is valid
 func (geom Geometry) IsValid() bool {
+	defer runtime.KeepAlive(geom.ref)
 	val := C.OGR_G_IsValid(geom.cval)
 	return val != 0
 }
 
 // Test if the geometry is simple
 func (geom Geometry) IsSimple() bool {
+	defer runtime.KeepAlive(geom.ref)
 	val := C.OGR_G_IsSimple(geom.cval)
 	return val != 0
 }
 
 // Test if the geometry is a ring
 func (geom Geometry) IsRing() bool {
+	defer runtime.KeepAlive(geom.ref)
 	val := C.OGR_G_IsRing(geom.cval)
 	return val != 0
 }
 
 // Polygonize a set of sparse edges
 func (geom Geometry) Polygonize() Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_Polygonize(geom.cval)
-	return Geometry{newGeom}
+	return ownedGeometry(newGeom)
 }
 
 // Fetch number of points in the geometry
 func (geom Geometry) PointCount() int {
+	defer runtime.KeepAlive(geom.ref)
 	count := C.OGR_G_GetPointCount(geom.cval)
 	return int(count)
 }
@@ -476,24 +565,28 @@ func (geom Geometry) PointCount() int {
 
 // Fetch the X coordinate of a point in the geometry
 func (geom Geometry) X(index int) float64 {
+	defer runtime.KeepAlive(geom.ref)
 	x := C.OGR_G_GetX(geom.cval, C.int(index))
 	return float64(x)
 }
 
 // Fetch the Y coordinate of a point in the geometry
 func (geom Geometry) Y(index int) float64 {
+	defer runtime.KeepAlive(geom.ref)
 	y := C.OGR_G_GetY(geom.cval, C.int(index))
 	return float64(y)
 }
 
 // Fetch the Z coordinate of a point in the geometry
 func (geom Geometry) Z(index int) float64 {
+	defer runtime.KeepAlive(geom.ref)
 	z := C.OGR_G_GetZ(geom.cval, C.int(index))
 	return float64(z)
 }
 
 // Fetch the coordinates of a point in the geometry
 func (geom Geometry) Point(index int) (x, y, z float64) {
+	defer runtime.KeepAlive(geom.ref)
 	C.OGR_G_GetPoint(
 		geom.cval,
 		C.int(index),
@@ -505,6 +598,7 @@ func (geom Geometry) Point(index int) (x, y, z float64) {
 
 // Set the coordinates of a point in the geometry
 func (geom Geometry) SetPoint(index int, x, y, z float64) {
+	defer runtime.KeepAlive(geom.ref)
 	C.OGR_G_SetPoint(
 		geom.cval,
 		C.int(index),
@@ -515,48 +609,60 @@ func (geom Geometry) SetPoint(index int, x, y, z float64) {
 
 // Set the coordinates of a point in the geometry, ignoring the 3rd dimension
 func (geom Geometry) SetPoint2D(index int, x, y float64) {
+	defer runtime.KeepAlive(geom.ref)
 	C.OGR_G_SetPoint_2D(geom.cval, C.int(index), C.double(x), C.double(y))
 }
 
 // Add a new point to the geometry (line string or polygon only)
 func (geom Geometry) AddPoint(x, y, z float64) {
+	defer runtime.KeepAlive(geom.ref)
 	C.OGR_G_AddPoint(geom.cval, C.double(x), C.double(y), C.double(z))
 }
 
 // Add a new point to the geometry (line string or polygon only), ignoring the 3rd dimension
 func (geom Geometry) AddPoint2D(x, y float64) {
+	defer runtime.KeepAlive(geom.ref)
 	C.OGR_G_AddPoint_2D(geom.cval, C.double(x), C.double(y))
 }
 
 // Fetch the number of elements in the geometry, or number of geometries in the container
 func (geom Geometry) GeometryCount() int {
+	defer runtime.KeepAlive(geom.ref)
 	count := C.OGR_G_GetGeometryCount(geom.cval)
 	return int(count)
 }
 
 // Fetch geometry from a geometry container
 func (geom Geometry) Geometry(index int) Geometry {
+	defer runtime.KeepAlive(geom.ref)
 	newGeom := C.OGR_G_GetGeometryRef(geom.cval, C.int(index))
-	return Geometry{newGeom}
+	return Geometry{newGeom, handle.Borrowed(geom.ref)}
 }
 
 // Add a geometry to a geometry container
 func (geom Geometry) AddGeometry(other Geometry) error {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
 	return C.OGR_G_AddGeometry(geom.cval, other.cval).Err()
 }
 
 // Add a geometry to a geometry container and assign ownership to that container
 func (geom Geometry) AddGeometryDirectly(other Geometry) error {
+	defer runtime.KeepAlive(geom.ref)
+	defer runtime.KeepAlive(other.ref)
+	other.ref.Disown(geom.ref)
 	return C.OGR_G_AddGeometryDirectly(geom.cval, other.cval).Err()
 }
 
 // Remove a geometry from the geometry container
 func (geom Geometry) RemoveGeometry(index int, delete bool) error {
+	defer runtime.KeepAlive(geom.ref)
 	return C.OGR_G_RemoveGeometry(geom.cval, C.int(index), BoolToCInt(delete)).Err()
 }
 
 // Build a polygon / ring from a set of lines
 func (geom Geometry) BuildPolygonFromEdges(autoClose bool, tolerance float64) (Geometry, error) {
+	defer runtime.KeepAlive(geom.ref)
 	var cErr C.OGRErr
 	newGeom := C.OGRBuildPolygonFromEdges(
 		geom.cval,
@@ -565,5 +671,5 @@ func (geom Geometry) BuildPolygonFromEdges(autoClose bool, tolerance float64) (G
 		C.double(tolerance),
 		&cErr,
 	)
-	return Geometry{newGeom}, cErr.Err()
+	return ownedGeometry(newGeom), cErr.Err()
 }
@@ -0,0 +1,131 @@
+package ogr
+
+/*
+#include "go_ogr_wkb.h"
+#include "gdal_version.h"
+*/
+import "C"
+
+import (
+	"io"
+	"runtime"
+	"unsafe"
+
+	"github.com/airmap/gdal/internal/bridge"
+	"github.com/airmap/gdal/internal/handle"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Handle ownership                                                */
+/* -------------------------------------------------------------------- */
+
+// TrackHandles turns ownership tracking on or off for handles created
+// afterwards. It is the same switch as gdal.TrackHandles, see there.
+func TrackHandles(on, debugLeaks bool) {
+	handle.Track(on, debugLeaks)
+}
+
+// ReportLeaks writes the handles leaked while debugging leaks to w and
+// returns their number. It is the same report as gdal.ReportLeaks.
+func ReportLeaks(w io.Writer) int {
+	return handle.Report(w)
+}
+
+// Owned reports whether destroying the geometry frees it, as opposed to a
+// geometry borrowed from a feature or container. Untracked geometries
+// always report true.
+func (geometry Geometry) Owned() bool {
+	return geometry.ref.Owned()
+}
+
+// Owned reports whether destroying the feature frees it. Untracked
+// features always report true.
+func (feature Feature) Owned() bool {
+	return feature.ref.Owned()
+}
+
+// Owned reports whether destroying or releasing the spatial reference
+// frees it. Untracked spatial references always report true.
+func (sr SpatialReference) Owned() bool {
+	return sr.ref.Owned()
+}
+
+// ownedGeometry wraps a geometry the caller is responsible for destroying.
+func ownedGeometry(h C.OGRGeometryH) Geometry {
+	if h == nil {
+		return Geometry{}
+	}
+	return Geometry{h, handle.Owned("ogr.Geometry", func() { C.OGR_G_DestroyGeometry(h) })}
+}
+
+// ownedFeature wraps a feature the caller is responsible for destroying.
+func ownedFeature(h C.OGRFeatureH) Feature {
+	if h == nil {
+		return Feature{}
+	}
+	return Feature{h, handle.Owned("ogr.Feature", func() { C.OGR_F_Destroy(h) })}
+}
+
+// ownedSpatialReference wraps a spatial reference the caller holds a
+// reference to.
+func ownedSpatialReference(h C.OGRSpatialReferenceH) SpatialReference {
+	if h == nil {
+		return SpatialReference{}
+	}
+	return SpatialReference{h, handle.Owned("ogr.SpatialReference", func() { C.OSRRelease(h) })}
+}
+
+/* -------------------------------------------------------------------- */
+/*      Handle bridges                                                  */
+/* -------------------------------------------------------------------- */
+
+// The C handle types of cgo are distinct in each package, so the gdal
+// package exchanges layers, geometries and spatial references with this
+// one as plain pointers.
+
+// Handle returns the OGRLayerH of the layer.
+func (layer Layer) Handle() unsafe.Pointer {
+	defer runtime.KeepAlive(layer.ref)
+	return unsafe.Pointer(layer.cval)
+}
+
+// LayerFromHandle wraps an OGRLayerH. Layers are owned by their dataset.
+func LayerFromHandle(h unsafe.Pointer) Layer {
+	return layerFromHandle(h, nil)
+}
+
+func init() {
+	bridge.Layer = func(h unsafe.Pointer, owner *handle.Ref) interface{} {
+		return layerFromHandle(h, owner)
+	}
+}
+
+// layerFromHandle wraps an OGRLayerH whose dataset's Ref, if not nil, is
+// kept from being finalized while the layer is reachable.
+func layerFromHandle(h unsafe.Pointer, owner *handle.Ref) Layer {
+	return Layer{C.OGRLayerH(h), handle.Borrowed(owner)}
+}
+
+// Handle returns the OGRGeometryH of the geometry.
+func (geometry Geometry) Handle() unsafe.Pointer {
+	defer runtime.KeepAlive(geometry.ref)
+	return unsafe.Pointer(geometry.cval)
+}
+
+// GeometryFromHandle wraps an OGRGeometryH the caller keeps ownership
+// of.
+func GeometryFromHandle(h unsafe.Pointer) Geometry {
+	return Geometry{C.OGRGeometryH(h), handle.Borrowed(nil)}
+}
+
+// Handle returns the OGRSpatialReferenceH of the spatial reference.
+func (sr SpatialReference) Handle() unsafe.Pointer {
+	defer runtime.KeepAlive(sr.ref)
+	return unsafe.Pointer(sr.cval)
+}
+
+// SpatialReferenceFromHandle wraps an OGRSpatialReferenceH the caller
+// keeps ownership of.
+func SpatialReferenceFromHandle(h unsafe.Pointer) SpatialReference {
+	return SpatialReference{C.OGRSpatialReferenceH(h), handle.Borrowed(nil)}
+}
@@ -7,7 +7,10 @@ package ogr
 import "C"
 
 import (
+	"runtime"
 	"unsafe"
+
+	"github.com/airmap/gdal/internal/handle"
 )
 
 /* -------------------------------------------------------------------- */
@@ -16,38 +19,47 @@ import (
 
 type Layer struct {
 	cval C.OGRLayerH
+	// ref keeps the dataset owning the layer from being finalized.
+	ref *handle.Ref
 }
 
 // test for null geometry
 func (layer Layer) IsNull() bool {
+	defer runtime.KeepAlive(layer.ref)
 	return layer.cval == nil
 }
 
 // Return the layer name
 func (layer Layer) Name() string {
+	defer runtime.KeepAlive(layer.ref)
 	name := C.OGR_L_GetName(layer.cval)
 	return C.GoString(name)
 }
 
 // Return the layer geometry type
 func (layer Layer) Type() GeometryType {
+	defer runtime.KeepAlive(layer.ref)
 	gt := C.OGR_L_GetGeomType(layer.cval)
 	return GeometryType(gt)
 }
 
 // Return the current spatial filter for this layer
 func (layer Layer) SpatialFilter() Geometry {
+	defer runtime.KeepAlive(layer.ref)
 	geom := C.OGR_L_GetSpatialFilter(layer.cval)
-	return Geometry{geom}
+	return Geometry{geom, handle.Borrowed(layer.ref)}
 }
 
 // Set a new spatial filter for this layer
 func (layer Layer) SetSpatialFilter(filter Geometry) {
+	defer runtime.KeepAlive(layer.ref)
+	defer runtime.KeepAlive(filter.ref)
 	C.OGR_L_SetSpatialFilter(layer.cval, filter.cval)
 }
 
 // Set a new rectangular spatial filter for this layer
 func (layer Layer) SetSpatialFilterRect(minX, minY, maxX, maxY float64) {
+	defer runtime.KeepAlive(layer.ref)
 	C.OGR_L_SetSpatialFilterRect(
 		layer.cval,
 		C.double(minX), C.double(minY), C.double(maxX), C.double(maxY),
@@ -56,11 +68,14 @@ func (layer Layer) SetSpatialFilterRect(minX, minY, maxX, maxY float64) {
 
 // Set a new spatial filter for this layer and field
 func (layer Layer) SetSpatialFilterEx(index int, filter Geometry) {
+	defer runtime.KeepAlive(layer.ref)
+	defer runtime.KeepAlive(filter.ref)
 	C.OGR_L_SetSpatialFilterEx(layer.cval, C.int(index), filter.cval)
 }
 
 // Set a new rectangular spatial filter for this layer
 func (layer Layer) SetSpatialFilterRectEx(index int, minX, minY, maxX, maxY float64) {
+	defer runtime.KeepAlive(layer.ref)
 	C.OGR_L_SetSpatialFilterRectEx(
 		layer.cval,
 		C.int(index),
@@ -70,6 +85,7 @@ func (layer Layer) SetSpatialFilterRectEx(index int, minX, minY, maxX, maxY floa
 
 // Set a new attribute query filter
 func (layer Layer) SetAttributeFilter(filter string) error {
+	defer runtime.KeepAlive(layer.ref)
 	cFilter := C.CString(filter)
 	defer C.free(unsafe.Pointer(cFilter))
 	return C.OGR_L_SetAttributeFilter(layer.cval, cFilter).Err()
@@ -77,58 +93,71 @@ func (layer Layer) SetAttributeFilter(filter string) error {
 
 // Reset reading to start on the first featre
 func (layer Layer) ResetReading() {
+	defer runtime.KeepAlive(layer.ref)
 	C.OGR_L_ResetReading(layer.cval)
 }
 
 // Fetch the next available feature from this layer
 func (layer Layer) NextFeature() *Feature {
+	defer runtime.KeepAlive(layer.ref)
 	feature := C.OGR_L_GetNextFeature(layer.cval)
 	if feature == nil {
 		return nil
 	}
-	return &Feature{feature}
+	f := ownedFeature(feature)
+	return &f
 }
 
 // Move read cursor to the provided index
 func (layer Layer) SetNextByIndex(index int64) error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_SetNextByIndex(layer.cval, C.GIntBig(index)).Err()
 }
 
 // Fetch a feature by its index
 func (layer Layer) Feature(index int64) Feature {
+	defer runtime.KeepAlive(layer.ref)
 	feature := C.OGR_L_GetFeature(layer.cval, C.GIntBig(index))
-	return Feature{feature}
+	return ownedFeature(feature)
 }
 
 // Rewrite the provided feature
 func (layer Layer) SetFeature(feature Feature) error {
+	defer runtime.KeepAlive(layer.ref)
+	defer runtime.KeepAlive(feature.ref)
 	return C.OGR_L_SetFeature(layer.cval, feature.cval).Err()
 }
 
 // Create and write a new feature within a layer
 func (layer Layer) Create(feature Feature) error {
+	defer runtime.KeepAlive(layer.ref)
+	defer runtime.KeepAlive(feature.ref)
 	return C.OGR_L_CreateFeature(layer.cval, feature.cval).Err()
 }
 
 // Delete indicated feature from layer
 func (layer Layer) Delete(index int64) error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_DeleteFeature(layer.cval, C.GIntBig(index)).Err()
 }
 
 // Fetch the schema information for this layer
 func (layer Layer) Definition() FeatureDefinition {
+	defer runtime.KeepAlive(layer.ref)
 	defn := C.OGR_L_GetLayerDefn(layer.cval)
 	return FeatureDefinition{defn}
 }
 
 // Fetch the spatial reference system for this layer
 func (layer Layer) SpatialReference() SpatialReference {
+	defer runtime.KeepAlive(layer.ref)
 	sr := C.OGR_L_GetSpatialRef(layer.cval)
-	return SpatialReference{sr}
+	return SpatialReference{sr, handle.Borrowed(layer.ref)}
 }
 
 // Gets the index for a field name
 func (layer Layer) FindFieldIndex(field string, exactMatch bool) int {
+	defer runtime.KeepAlive(layer.ref)
 	cString := C.CString(field)
 	defer C.free(unsafe.Pointer(cString))
 	index := C.OGR_L_FindFieldIndex(layer.cval, cString, BoolToCInt(exactMatch))
@@ -137,24 +166,28 @@ func (layer Layer) FindFieldIndex(field string, exactMatch bool) int {
 
 // Fetch the feature count for this layer
 func (layer Layer) FeatureCount(force bool) (count int, ok bool) {
+	defer runtime.KeepAlive(layer.ref)
 	count = int(C.OGR_L_GetFeatureCount(layer.cval, BoolToCInt(force)))
 	return count, count != -1
 }
 
 // Fetch the extent of this layer
 func (layer Layer) Extent(force bool) (env Envelope, err error) {
+	defer runtime.KeepAlive(layer.ref)
 	err = C.OGR_L_GetExtent(layer.cval, &env.cval, BoolToCInt(force)).Err()
 	return
 }
 
 // Fetch the extent of this layer on the spacified geometry field
 func (layer Layer) ExtentEx(index int, force bool) (env Envelope, err error) {
+	defer runtime.KeepAlive(layer.ref)
 	err = C.OGR_L_GetExtentEx(layer.cval, C.int(index), &env.cval, BoolToCInt(force)).Err()
 	return
 }
 
 // Test if this layer supports the named capability
 func (layer Layer) TestCapability(capability string) bool {
+	defer runtime.KeepAlive(layer.ref)
 	cString := C.CString(capability)
 	defer C.free(unsafe.Pointer(cString))
 	val := C.OGR_L_TestCapability(layer.cval, cString)
@@ -163,71 +196,84 @@ func (layer Layer) TestCapability(capability string) bool {
 
 // Create a new field on a layer
 func (layer Layer) CreateField(fd FieldDefinition, approxOK bool) error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_CreateField(layer.cval, fd.cval, BoolToCInt(approxOK)).Err()
 }
 
 // Create a new geometry field on a layer
 func (layer Layer) CreateGeomField(fd FieldDefinition, approxOK bool) error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_CreateField(layer.cval, fd.cval, BoolToCInt(approxOK)).Err()
 }
 
 // Delete a field from the layer
 func (layer Layer) DeleteField(index int) error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_DeleteField(layer.cval, C.int(index)).Err()
 }
 
 // Reorder all the fields of a layer
 func (layer Layer) ReorderFields(layerMap []int) error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_ReorderFields(layer.cval, (*C.int)(unsafe.Pointer(&layerMap[0]))).Err()
 }
 
 // Reorder an existing field of a layer
 func (layer Layer) ReorderField(oldIndex, newIndex int) error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_ReorderField(layer.cval, C.int(oldIndex), C.int(newIndex)).Err()
 }
 
 // Alter the definition of an existing field of a layer
 func (layer Layer) AlterFieldDefn(index int, newDefn FieldDefinition, flags int) error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_AlterFieldDefn(layer.cval, C.int(index), newDefn.cval, C.int(flags)).Err()
 }
 
 // Begin a transation on data sources which support it
 // Note: as of GDAL 2.0, use of this API is discouraged when the dataset offers dataset level transaction with GDALDataset::StartTransaction().
 func (layer Layer) StartTransaction() error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_StartTransaction(layer.cval).Err()
 }
 
 // Commit a transaction on data sources which support it
 // Note: as of GDAL 2.0, use of this API is discouraged when the dataset offers dataset level transaction with GDALDataset::StartTransaction().
 func (layer Layer) CommitTransaction() error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_CommitTransaction(layer.cval).Err()
 }
 
 // Roll back the current transaction on data sources which support it
 // Note: as of GDAL 2.0, use of this API is discouraged when the dataset offers dataset level transaction with GDALDataset::StartTransaction().
 func (layer Layer) RollbackTransaction() error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_RollbackTransaction(layer.cval).Err()
 }
 
 // Flush pending changes to the layer
 func (layer Layer) Sync() error {
+	defer runtime.KeepAlive(layer.ref)
 	return C.OGR_L_SyncToDisk(layer.cval).Err()
 }
 
 // Fetch the name of the FID column
 func (layer Layer) FIDColumn() string {
+	defer runtime.KeepAlive(layer.ref)
 	name := C.OGR_L_GetFIDColumn(layer.cval)
 	return C.GoString(name)
 }
 
 // Fetch the name of the geometry column
 func (layer Layer) GeometryColumn() string {
+	defer runtime.KeepAlive(layer.ref)
 	name := C.OGR_L_GetGeometryColumn(layer.cval)
 	return C.GoString(name)
 }
 
 // Set which fields can be ignored when retrieving features from the layer
 func (layer Layer) SetIgnoredFields(names []string) error {
+	defer runtime.KeepAlive(layer.ref)
 	length := len(names)
 	cNames := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
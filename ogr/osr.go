@@ -12,7 +12,10 @@ package ogr
 import "C"
 import (
 	"reflect"
+	"runtime"
 	"unsafe"
+
+	"github.com/airmap/gdal/internal/handle"
 )
 
 type AxisMappingStrategy uint32
@@ -29,9 +32,11 @@ const (
 
 type SpatialReference struct {
 	cval C.OGRSpatialReferenceH
+	ref  *handle.Ref
 }
 
 func (sr SpatialReference) GetPointer() C.OGRSpatialReferenceH {
+	defer runtime.KeepAlive(sr.ref)
 	return sr.cval
 }
 
@@ -40,11 +45,12 @@ func CreateSpatialReference(wkt string) SpatialReference {
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
 	sr := C.OSRNewSpatialReference(cString)
-	return SpatialReference{sr}
+	return ownedSpatialReference(sr)
 }
 
 // Initialize SRS based on WKT string
 func (sr SpatialReference) FromWKT(wkt string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
 	return C.OSRImportFromWkt(sr.cval, &cString).Err()
@@ -52,6 +58,7 @@ func (sr SpatialReference) FromWKT(wkt string) error {
 
 // Export coordinate system to WKT
 func (sr SpatialReference) ToWKT() (string, error) {
+	defer runtime.KeepAlive(sr.ref)
 	var p *C.char
 	err := C.OSRExportToWkt(sr.cval, &p).Err()
 	wkt := C.GoString(p)
@@ -60,6 +67,7 @@ func (sr SpatialReference) ToWKT() (string, error) {
 
 // Export coordinate system to a nicely formatted WKT string
 func (sr SpatialReference) ToPrettyWKT(simplify bool) (string, error) {
+	defer runtime.KeepAlive(sr.ref)
 	var p *C.char
 	err := C.OSRExportToPrettyWkt(
 		sr.cval, &p, BoolToCInt(simplify),
@@ -70,50 +78,63 @@ func (sr SpatialReference) ToPrettyWKT(simplify bool) (string, error) {
 
 // Initialize SRS based on EPSG code
 func (sr SpatialReference) FromEPSG(code int) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRImportFromEPSG(sr.cval, C.int(code)).Err()
 }
 
 // Initialize SRS based on EPSG code, using EPSG lat/long ordering
 func (sr SpatialReference) FromEPSGA(code int) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRImportFromEPSGA(sr.cval, C.int(code)).Err()
 }
 
-// Destroy the spatial reference
+// Destroy the spatial reference. With handle tracking on, destroying or
+// releasing it again, or destroying a borrowed reference, is a no-op.
 func (sr SpatialReference) Destroy() {
-	C.OSRDestroySpatialReference(sr.cval)
+	if sr.ref.Close() {
+		C.OSRDestroySpatialReference(sr.cval)
+	}
 }
 
 // Make a duplicate of this spatial reference
 func (sr SpatialReference) Clone() SpatialReference {
+	defer runtime.KeepAlive(sr.ref)
 	newSR := C.OSRClone(sr.cval)
-	return SpatialReference{newSR}
+	return ownedSpatialReference(newSR)
 }
 
 // Make a duplicate of the GEOGCS node of this spatial reference
 func (sr SpatialReference) CloneGeogCS() SpatialReference {
+	defer runtime.KeepAlive(sr.ref)
 	newSR := C.OSRCloneGeogCS(sr.cval)
-	return SpatialReference{newSR}
+	return ownedSpatialReference(newSR)
 }
 
 // Increments the reference count by one, returning reference count
 func (sr SpatialReference) Reference() int {
+	defer runtime.KeepAlive(sr.ref)
 	count := C.OSRReference(sr.cval)
 	return int(count)
 }
 
 // Decrements the reference count by one, returning reference count
 func (sr SpatialReference) Dereference() int {
+	defer runtime.KeepAlive(sr.ref)
 	count := C.OSRDereference(sr.cval)
 	return int(count)
 }
 
-// Decrements the reference count by one and destroy if zero
+// Decrements the reference count by one and destroy if zero. With handle
+// tracking on, it is a no-op after the first Release or Destroy.
 func (sr SpatialReference) Release() {
-	C.OSRRelease(sr.cval)
+	if sr.ref.Close() {
+		C.OSRRelease(sr.cval)
+	}
 }
 
 // Validate spatial reference tokens
 func (sr SpatialReference) Validate() error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRValidate(sr.cval).Err()
 }
 
@@ -134,6 +155,7 @@ func (sr SpatialReference) Validate() error {
 
 // Import PROJ.4 coordinate string
 func (sr SpatialReference) FromProj4(input string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cString := C.CString(input)
 	defer C.free(unsafe.Pointer(cString))
 	return C.OSRImportFromProj4(sr.cval, cString).Err()
@@ -141,6 +163,7 @@ func (sr SpatialReference) FromProj4(input string) error {
 
 // Export coordinate system in PROJ.4 format
 func (sr SpatialReference) ToProj4() (string, error) {
+	defer runtime.KeepAlive(sr.ref)
 	var p *C.char
 	err := C.OSRExportToProj4(sr.cval, &p).Err()
 	proj4 := C.GoString(p)
@@ -149,6 +172,7 @@ func (sr SpatialReference) ToProj4() (string, error) {
 
 // Import coordinate system from ESRI .prj formats
 func (sr SpatialReference) FromESRI(input string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cString := C.CString(input)
 	defer C.free(unsafe.Pointer(cString))
 	return C.OSRImportFromProj4(sr.cval, cString).Err()
@@ -156,6 +180,7 @@ func (sr SpatialReference) FromESRI(input string) error {
 
 // Import coordinate system from PCI projection definition
 func (sr SpatialReference) FromPCI(proj, units string, params []float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	cProj := C.CString(proj)
 	defer C.free(unsafe.Pointer(cProj))
 	cUnits := C.CString(units)
@@ -171,6 +196,7 @@ func (sr SpatialReference) FromPCI(proj, units string, params []float64) error {
 
 // Import coordinate system from USGS projection definition
 func (sr SpatialReference) FromUSGS(projsys, zone int, params []float64, datum int) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRImportFromUSGS(
 		sr.cval,
 		C.long(projsys),
@@ -182,6 +208,7 @@ func (sr SpatialReference) FromUSGS(projsys, zone int, params []float64, datum i
 
 // Import coordinate system from XML format (GML only currently)
 func (sr SpatialReference) FromXML(xml string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cXml := C.CString(xml)
 	defer C.free(unsafe.Pointer(cXml))
 	return C.OSRImportFromXML(sr.cval, cXml).Err()
@@ -189,6 +216,7 @@ func (sr SpatialReference) FromXML(xml string) error {
 
 // Import coordinate system from ERMapper projection definitions
 func (sr SpatialReference) FromERM(proj, datum, units string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cProj := C.CString(proj)
 	defer C.free(unsafe.Pointer(cProj))
 	cDatum := C.CString(datum)
@@ -201,6 +229,7 @@ func (sr SpatialReference) FromERM(proj, datum, units string) error {
 
 // Import coordinate system from a URL
 func (sr SpatialReference) FromURL(url string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cURL := C.CString(url)
 	defer C.free(unsafe.Pointer(cURL))
 	return C.OSRImportFromXML(sr.cval, cURL).Err()
@@ -208,6 +237,7 @@ func (sr SpatialReference) FromURL(url string) error {
 
 // Export coordinate system in PCI format
 func (sr SpatialReference) ToPCI() (proj, units string, params []float64, errVal error) {
+	defer runtime.KeepAlive(sr.ref)
 	var p, u *C.char
 	err := C.OSRExportToPCI(
 		sr.cval, &p, &u, (**C.double)(unsafe.Pointer(&params[0])),
@@ -222,6 +252,7 @@ func (sr SpatialReference) ToPCI() (proj, units string, params []float64, errVal
 
 // Export coordinate system to USGS GCTP projection definition
 func (sr SpatialReference) ToUSGS() (proj, zone int, params []float64, datum int, errVal error) {
+	defer runtime.KeepAlive(sr.ref)
 	err := C.OSRExportToUSGS(
 		sr.cval,
 		(*C.long)(unsafe.Pointer(&proj)),
@@ -239,6 +270,7 @@ func (sr SpatialReference) ToUSGS() (proj, zone int, params []float64, datum int
 
 // Export coordinate system in XML format
 func (sr SpatialReference) ToXML() (xml string, errVal error) {
+	defer runtime.KeepAlive(sr.ref)
 	var x *C.char
 	err := C.OSRExportToXML(sr.cval, &x, nil).Err()
 	defer C.free(unsafe.Pointer(x))
@@ -247,6 +279,7 @@ func (sr SpatialReference) ToXML() (xml string, errVal error) {
 
 // Export coordinate system in Mapinfo style CoordSys format
 func (sr SpatialReference) ToMICoordSys() (output string, errVal error) {
+	defer runtime.KeepAlive(sr.ref)
 	var x *C.char
 	err := C.OSRExportToMICoordSys(sr.cval, &x).Err()
 	defer C.free(unsafe.Pointer(x))
@@ -258,16 +291,19 @@ func (sr SpatialReference) ToMICoordSys() (output string, errVal error) {
 
 // Convert in place to ESRI WKT format
 func (sr SpatialReference) MorphToESRI() error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRMorphToESRI(sr.cval).Err()
 }
 
 // Convert in place from ESRI WKT format
 func (sr SpatialReference) MorphFromESRI() error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRMorphFromESRI(sr.cval).Err()
 }
 
 // Fetch indicated attribute of named node
 func (sr SpatialReference) AttrValue(key string, child int) (value string, ok bool) {
+	defer runtime.KeepAlive(sr.ref)
 	cKey := C.CString(key)
 	defer C.free(unsafe.Pointer(cKey))
 	val := C.OSRGetAttrValue(sr.cval, cKey, C.int(child))
@@ -276,6 +312,7 @@ func (sr SpatialReference) AttrValue(key string, child int) (value string, ok bo
 
 // Set attribute value in spatial reference
 func (sr SpatialReference) SetAttrValue(path, value string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cPath := C.CString(path)
 	defer C.free(unsafe.Pointer(cPath))
 	cValue := C.CString(value)
@@ -285,6 +322,7 @@ func (sr SpatialReference) SetAttrValue(path, value string) error {
 
 // Set the angular units for the geographic coordinate system
 func (sr SpatialReference) SetAngularUnits(units string, radians float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	cUnits := C.CString(units)
 	defer C.free(unsafe.Pointer(cUnits))
 	return C.OSRSetAngularUnits(sr.cval, cUnits, C.double(radians)).Err()
@@ -292,6 +330,7 @@ func (sr SpatialReference) SetAngularUnits(units string, radians float64) error
 
 // Fetch the angular units for the geographic coordinate system
 func (sr SpatialReference) AngularUnits() (string, float64) {
+	defer runtime.KeepAlive(sr.ref)
 	var x *C.char
 	factor := C.OSRGetAngularUnits(sr.cval, &x)
 	defer C.free(unsafe.Pointer(x))
@@ -300,6 +339,7 @@ func (sr SpatialReference) AngularUnits() (string, float64) {
 
 // Set the linear units for the projection
 func (sr SpatialReference) SetLinearUnits(name string, toMeters float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetLinearUnits(sr.cval, cName, C.double(toMeters)).Err()
@@ -307,6 +347,7 @@ func (sr SpatialReference) SetLinearUnits(name string, toMeters float64) error {
 
 // Set the linear units for the target node
 func (sr SpatialReference) SetTargetLinearUnits(target, units string, toMeters float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	cUnits := C.CString(units)
@@ -316,6 +357,7 @@ func (sr SpatialReference) SetTargetLinearUnits(target, units string, toMeters f
 
 // Set the linear units for the target node and update all existing linear parameters
 func (sr SpatialReference) SetLinearUnitsAndUpdateParameters(name string, toMeters float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetLinearUnitsAndUpdateParameters(sr.cval, cName, C.double(toMeters)).Err()
@@ -323,6 +365,7 @@ func (sr SpatialReference) SetLinearUnitsAndUpdateParameters(name string, toMete
 
 // Fetch linear projection units
 func (sr SpatialReference) LinearUnits() (string, float64) {
+	defer runtime.KeepAlive(sr.ref)
 	var x *C.char
 	factor := C.OSRGetLinearUnits(sr.cval, &x)
 	defer C.free(unsafe.Pointer(x))
@@ -331,6 +374,7 @@ func (sr SpatialReference) LinearUnits() (string, float64) {
 
 // Fetch linear units for target
 func (sr SpatialReference) TargetLinearUnits(target string) (string, float64) {
+	defer runtime.KeepAlive(sr.ref)
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	var x *C.char
@@ -341,6 +385,7 @@ func (sr SpatialReference) TargetLinearUnits(target string) (string, float64) {
 
 // Fetch prime meridian information
 func (sr SpatialReference) PrimeMeridian() (string, float64) {
+	defer runtime.KeepAlive(sr.ref)
 	var x *C.char
 	offset := C.OSRGetPrimeMeridian(sr.cval, &x)
 	defer C.free(unsafe.Pointer(x))
@@ -349,60 +394,73 @@ func (sr SpatialReference) PrimeMeridian() (string, float64) {
 
 // Return true if geographic coordinate system
 func (sr SpatialReference) IsGeographic() bool {
+	defer runtime.KeepAlive(sr.ref)
 	val := C.OSRIsGeographic(sr.cval)
 	return val != 0
 }
 
 // Return true if local coordinate system
 func (sr SpatialReference) IsLocal() bool {
+	defer runtime.KeepAlive(sr.ref)
 	val := C.OSRIsLocal(sr.cval)
 	return val != 0
 }
 
 // Return true if projected coordinate system
 func (sr SpatialReference) IsProjected() bool {
+	defer runtime.KeepAlive(sr.ref)
 	val := C.OSRIsProjected(sr.cval)
 	return val != 0
 }
 
 // Return true if compound coordinate system
 func (sr SpatialReference) IsCompound() bool {
+	defer runtime.KeepAlive(sr.ref)
 	val := C.OSRIsCompound(sr.cval)
 	return val != 0
 }
 
 // Return true if geocentric coordinate system
 func (sr SpatialReference) IsGeocentric() bool {
+	defer runtime.KeepAlive(sr.ref)
 	val := C.OSRIsGeocentric(sr.cval)
 	return val != 0
 }
 
 // Return true if vertical coordinate system
 func (sr SpatialReference) IsVertical() bool {
+	defer runtime.KeepAlive(sr.ref)
 	val := C.OSRIsVertical(sr.cval)
 	return val != 0
 }
 
 // Return true if the geographic coordinate systems match
 func (sr SpatialReference) IsSameGeographicCS(other SpatialReference) bool {
+	defer runtime.KeepAlive(sr.ref)
+	defer runtime.KeepAlive(other.ref)
 	val := C.OSRIsSameGeogCS(sr.cval, other.cval)
 	return val != 0
 }
 
 // Return true if the vertical coordinate systems match
 func (sr SpatialReference) IsSameVerticalCS(other SpatialReference) bool {
+	defer runtime.KeepAlive(sr.ref)
+	defer runtime.KeepAlive(other.ref)
 	val := C.OSRIsSameVertCS(sr.cval, other.cval)
 	return val != 0
 }
 
 // Return true if the coordinate systems describe the same system
 func (sr SpatialReference) IsSame(other SpatialReference) bool {
+	defer runtime.KeepAlive(sr.ref)
+	defer runtime.KeepAlive(other.ref)
 	val := C.OSRIsSame(sr.cval, other.cval)
 	return val != 0
 }
 
 // Set the user visible local CS name
 func (sr SpatialReference) SetLocalCS(name string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetLocalCS(sr.cval, cName).Err()
@@ -410,6 +468,7 @@ func (sr SpatialReference) SetLocalCS(name string) error {
 
 // Set the user visible projected CS name
 func (sr SpatialReference) SetProjectedCS(name string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetProjCS(sr.cval, cName).Err()
@@ -417,6 +476,7 @@ func (sr SpatialReference) SetProjectedCS(name string) error {
 
 // Set the user visible geographic CS name
 func (sr SpatialReference) SetGeocentricCS(name string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetGeocCS(sr.cval, cName).Err()
@@ -424,6 +484,7 @@ func (sr SpatialReference) SetGeocentricCS(name string) error {
 
 // Set geographic CS based on well known name
 func (sr SpatialReference) SetWellKnownGeographicCS(name string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetWellKnownGeogCS(sr.cval, cName).Err()
@@ -431,6 +492,7 @@ func (sr SpatialReference) SetWellKnownGeographicCS(name string) error {
 
 // Set spatial reference from various text formats
 func (sr SpatialReference) SetFromUserInput(name string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetFromUserInput(sr.cval, cName).Err()
@@ -438,11 +500,14 @@ func (sr SpatialReference) SetFromUserInput(name string) error {
 
 // Copy geographic CS from another spatial reference
 func (sr SpatialReference) CopyGeographicCSFrom(other SpatialReference) error {
+	defer runtime.KeepAlive(sr.ref)
+	defer runtime.KeepAlive(other.ref)
 	return C.OSRCopyGeogCSFrom(sr.cval, other.cval).Err()
 }
 
 // Set the Bursa-Wolf conversion to WGS84
 func (sr SpatialReference) SetTOWGS84(dx, dy, dz, ex, ey, ez, ppm float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetTOWGS84(
 		sr.cval,
 		C.double(dx),
@@ -457,6 +522,7 @@ func (sr SpatialReference) SetTOWGS84(dx, dy, dz, ex, ey, ez, ppm float64) error
 
 // Fetch the TOWGS84 parameters if available
 func (sr SpatialReference) TOWGS84() (coeff [7]float64, err error) {
+	defer runtime.KeepAlive(sr.ref)
 	err = C.OSRGetTOWGS84(sr.cval, (*C.double)(unsafe.Pointer(&coeff[0])), 7).Err()
 	return
 }
@@ -466,6 +532,9 @@ func (sr SpatialReference) SetCompoundCS(
 	name string,
 	horizontal, vertical SpatialReference,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
+	defer runtime.KeepAlive(horizontal.ref)
+	defer runtime.KeepAlive(vertical.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetCompoundCS(sr.cval, cName, horizontal.cval, vertical.cval).Err()
@@ -480,6 +549,7 @@ func (sr SpatialReference) SetGeographicCS(
 	angularUnits string,
 	toRadians float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	cGeogName := C.CString(geogName)
 	defer C.free(unsafe.Pointer(cGeogName))
 	cDatumName := C.CString(datumName)
@@ -506,6 +576,7 @@ func (sr SpatialReference) SetGeographicCS(
 
 // Set up the vertical coordinate system
 func (sr SpatialReference) SetVerticalCS(csName, datumName string, datumType int) error {
+	defer runtime.KeepAlive(sr.ref)
 	cCSName := C.CString(csName)
 	defer C.free(unsafe.Pointer(cCSName))
 	cDatumName := C.CString(datumName)
@@ -515,6 +586,7 @@ func (sr SpatialReference) SetVerticalCS(csName, datumName string, datumType int
 
 // Get spheroid semi-major axis
 func (sr SpatialReference) SemiMajorAxis() (float64, error) {
+	defer runtime.KeepAlive(sr.ref)
 	var cErr C.OGRErr
 	axis := C.OSRGetSemiMajor(sr.cval, &cErr)
 	return float64(axis), cErr.Err()
@@ -522,6 +594,7 @@ func (sr SpatialReference) SemiMajorAxis() (float64, error) {
 
 // Get spheroid semi-minor axis
 func (sr SpatialReference) SemiMinorAxis() (float64, error) {
+	defer runtime.KeepAlive(sr.ref)
 	var cErr C.OGRErr
 	axis := C.OSRGetSemiMinor(sr.cval, &cErr)
 	return float64(axis), cErr.Err()
@@ -529,6 +602,7 @@ func (sr SpatialReference) SemiMinorAxis() (float64, error) {
 
 // Get spheroid inverse flattening axis
 func (sr SpatialReference) InverseFlattening() (float64, error) {
+	defer runtime.KeepAlive(sr.ref)
 	var cErr C.OGRErr
 	flat := C.OSRGetInvFlattening(sr.cval, &cErr)
 	return float64(flat), cErr.Err()
@@ -536,6 +610,7 @@ func (sr SpatialReference) InverseFlattening() (float64, error) {
 
 // Sets the authority for a node
 func (sr SpatialReference) SetAuthority(target, authority string, code int) error {
+	defer runtime.KeepAlive(sr.ref)
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	cAuthority := C.CString(authority)
@@ -545,6 +620,7 @@ func (sr SpatialReference) SetAuthority(target, authority string, code int) erro
 
 // Get the authority code for a node
 func (sr SpatialReference) AuthorityCode(target string) string {
+	defer runtime.KeepAlive(sr.ref)
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	code := C.OSRGetAuthorityCode(sr.cval, cTarget)
@@ -553,6 +629,7 @@ func (sr SpatialReference) AuthorityCode(target string) string {
 
 // Get the authority name for a node
 func (sr SpatialReference) AuthorityName(target string) string {
+	defer runtime.KeepAlive(sr.ref)
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	code := C.OSRGetAuthorityName(sr.cval, cTarget)
@@ -561,6 +638,7 @@ func (sr SpatialReference) AuthorityName(target string) string {
 
 // Set a projection by name
 func (sr SpatialReference) SetProjectionByName(name string) error {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetProjection(sr.cval, cName).Err()
@@ -568,6 +646,7 @@ func (sr SpatialReference) SetProjectionByName(name string) error {
 
 // Set a projection parameter value
 func (sr SpatialReference) SetProjectionParameter(name string, value float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetProjParm(sr.cval, cName, C.double(value)).Err()
@@ -575,6 +654,7 @@ func (sr SpatialReference) SetProjectionParameter(name string, value float64) er
 
 // Fetch a projection parameter value
 func (sr SpatialReference) ProjectionParameter(name string, defaultValue float64) (float64, error) {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	var cErr C.OGRErr
@@ -584,6 +664,7 @@ func (sr SpatialReference) ProjectionParameter(name string, defaultValue float64
 
 // Set a projection parameter with a normalized value
 func (sr SpatialReference) SetNormalizedProjectionParameter(name string, value float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetNormProjParm(sr.cval, cName, C.double(value)).Err()
@@ -593,6 +674,7 @@ func (sr SpatialReference) SetNormalizedProjectionParameter(name string, value f
 func (sr SpatialReference) NormalizedProjectionParameter(
 	name string, defaultValue float64,
 ) (float64, error) {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	var cErr C.OGRErr
@@ -601,20 +683,24 @@ func (sr SpatialReference) NormalizedProjectionParameter(
 }
 
 func (sr SpatialReference) GetAxisMappingStrategy() AxisMappingStrategy {
+	defer runtime.KeepAlive(sr.ref)
 	return AxisMappingStrategy(C.OSRGetAxisMappingStrategy(sr.cval))
 }
 
 func (sr SpatialReference) SetAxisMappingStrategy(ams AxisMappingStrategy) {
+	defer runtime.KeepAlive(sr.ref)
 	C.OSRSetAxisMappingStrategy(sr.cval, C.OSRAxisMappingStrategy(ams))
 }
 
 // Set UTM projection definition
 func (sr SpatialReference) SetUTM(zone int, north bool) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetUTM(sr.cval, C.int(zone), BoolToCInt(north)).Err()
 }
 
 // Get UTM zone information
 func (sr SpatialReference) UTMZone() (zone int, north bool) {
+	defer runtime.KeepAlive(sr.ref)
 	var northInt C.int
 	cZone := C.OSRGetUTMZone(sr.cval, &northInt)
 	return int(cZone), northInt != 0
@@ -622,6 +708,7 @@ func (sr SpatialReference) UTMZone() (zone int, north bool) {
 
 // Set State Plane projection definition
 func (sr SpatialReference) SetStatePlane(zone int, nad83 bool) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetStatePlane(sr.cval, C.int(zone), BoolToCInt(nad83)).Err()
 }
 
@@ -632,6 +719,7 @@ func (sr SpatialReference) SetStatePlaneWithUnits(
 	unitName string,
 	factor float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	cUnitName := C.CString(unitName)
 	defer C.free(unsafe.Pointer(cUnitName))
 	return C.OSRSetStatePlaneWithUnits(
@@ -645,11 +733,13 @@ func (sr SpatialReference) SetStatePlaneWithUnits(
 
 // Set EPSG authority info if possible
 func (sr SpatialReference) AutoIdentifyEPSG() error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRAutoIdentifyEPSG(sr.cval).Err()
 }
 
 // Return true if EPSG feels this coordinate system should be treated as having lat/long coordinate ordering
 func (sr SpatialReference) EPSGTreatsAsLatLong() bool {
+	defer runtime.KeepAlive(sr.ref)
 	val := C.OSREPSGTreatsAsLatLong(sr.cval)
 	return val != 0
 }
@@ -661,6 +751,7 @@ func (sr SpatialReference) EPSGTreatsAsLatLong() bool {
 func (sr SpatialReference) SetACEA(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetACEA(
 		sr.cval,
 		C.double(stdp1),
@@ -674,6 +765,7 @@ func (sr SpatialReference) SetACEA(
 
 // Set to Azimuthal Equidistant
 func (sr SpatialReference) SetAE(centerLat, centerLong, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetAE(
 		sr.cval,
 		C.double(centerLat),
@@ -685,6 +777,7 @@ func (sr SpatialReference) SetAE(centerLat, centerLong, falseEasting, falseNorth
 
 // Set to Bonne
 func (sr SpatialReference) SetBonne(standardParallel, centralMeridian, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetBonne(
 		sr.cval,
 		C.double(standardParallel),
@@ -696,6 +789,7 @@ func (sr SpatialReference) SetBonne(standardParallel, centralMeridian, falseEast
 
 // Set to Cylindrical Equal Area
 func (sr SpatialReference) SetCEA(stdp1, centralMeridian, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetCEA(
 		sr.cval,
 		C.double(stdp1),
@@ -707,6 +801,7 @@ func (sr SpatialReference) SetCEA(stdp1, centralMeridian, falseEasting, falseNor
 
 // Set to Cassini-Soldner
 func (sr SpatialReference) SetCS(centerLat, centerLong, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetCS(
 		sr.cval,
 		C.double(centerLat),
@@ -720,6 +815,7 @@ func (sr SpatialReference) SetCS(centerLat, centerLong, falseEasting, falseNorth
 func (sr SpatialReference) SetEC(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetEC(
 		sr.cval,
 		C.double(stdp1),
@@ -733,6 +829,7 @@ func (sr SpatialReference) SetEC(
 
 // Set to Eckert I-VI
 func (sr SpatialReference) SetEckert(variation int, centralMeridian, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetEckert(
 		sr.cval,
 		C.int(variation),
@@ -746,6 +843,7 @@ func (sr SpatialReference) SetEckert(variation int, centralMeridian, falseEastin
 func (sr SpatialReference) SetEquirectangular(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetEquirectangular(
 		sr.cval,
 		C.double(centerLat),
@@ -759,6 +857,7 @@ func (sr SpatialReference) SetEquirectangular(
 func (sr SpatialReference) SetEquirectangularGeneralized(
 	centerLat, centerLong, psuedoStdParallel, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetEquirectangular2(
 		sr.cval,
 		C.double(centerLat),
@@ -771,6 +870,7 @@ func (sr SpatialReference) SetEquirectangularGeneralized(
 
 // Set to Gall Stereographic
 func (sr SpatialReference) SetGS(centralMeridian, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetGS(
 		sr.cval,
 		C.double(centralMeridian),
@@ -781,6 +881,7 @@ func (sr SpatialReference) SetGS(centralMeridian, falseEasting, falseNorthing fl
 
 // Set to Goode Homolosine
 func (sr SpatialReference) SetGH(centralMeridian, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetGH(
 		sr.cval,
 		C.double(centralMeridian),
@@ -791,6 +892,7 @@ func (sr SpatialReference) SetGH(centralMeridian, falseEasting, falseNorthing fl
 
 // Set to Interrupted Goode Homolosine
 func (sr SpatialReference) SetIGH() error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetIGH(sr.cval).Err()
 }
 
@@ -798,6 +900,7 @@ func (sr SpatialReference) SetIGH() error {
 func (sr SpatialReference) SetGEOS(
 	centralMeridian, satelliteHeight, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetGEOS(
 		sr.cval,
 		C.double(centralMeridian),
@@ -811,6 +914,7 @@ func (sr SpatialReference) SetGEOS(
 func (sr SpatialReference) SetGSTM(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetGaussSchreiberTMercator(
 		sr.cval,
 		C.double(centerLat),
@@ -825,6 +929,7 @@ func (sr SpatialReference) SetGSTM(
 func (sr SpatialReference) SetGnomonic(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetGnomonic(
 		sr.cval,
 		C.double(centerLat),
@@ -838,6 +943,7 @@ func (sr SpatialReference) SetGnomonic(
 func (sr SpatialReference) SetHOM(
 	centerLat, centerLong, azimuth, rectToSkew, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetHOM(
 		sr.cval,
 		C.double(centerLat),
@@ -854,6 +960,7 @@ func (sr SpatialReference) SetHOM(
 func (sr SpatialReference) SetHOM2PNO(
 	centerLat, lat1, long1, lat2, long2, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetHOM2PNO(
 		sr.cval,
 		C.double(centerLat),
@@ -871,6 +978,7 @@ func (sr SpatialReference) SetHOM2PNO(
 func (sr SpatialReference) SetIWMPolyconic(
 	lat1, lat2, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetIWMPolyconic(
 		sr.cval,
 		C.double(lat1),
@@ -885,6 +993,7 @@ func (sr SpatialReference) SetIWMPolyconic(
 func (sr SpatialReference) SetKrovak(
 	centerLat, centerLong, azimuth, psuedoStdParallel, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetKrovak(
 		sr.cval,
 		C.double(centerLat),
@@ -901,6 +1010,7 @@ func (sr SpatialReference) SetKrovak(
 func (sr SpatialReference) SetLAEA(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetLAEA(
 		sr.cval,
 		C.double(centerLat),
@@ -914,6 +1024,7 @@ func (sr SpatialReference) SetLAEA(
 func (sr SpatialReference) SetLCC(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetLCC(
 		sr.cval,
 		C.double(stdp1),
@@ -929,6 +1040,7 @@ func (sr SpatialReference) SetLCC(
 func (sr SpatialReference) SetLCC1SP(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetLCC1SP(
 		sr.cval,
 		C.double(centerLat),
@@ -943,6 +1055,7 @@ func (sr SpatialReference) SetLCC1SP(
 func (sr SpatialReference) SetLCCB(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetLCCB(
 		sr.cval,
 		C.double(stdp1),
@@ -958,6 +1071,7 @@ func (sr SpatialReference) SetLCCB(
 func (sr SpatialReference) SetMC(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetMC(
 		sr.cval,
 		C.double(centerLat),
@@ -971,6 +1085,7 @@ func (sr SpatialReference) SetMC(
 func (sr SpatialReference) SetMercator(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetMercator(
 		sr.cval,
 		C.double(centerLat),
@@ -985,6 +1100,7 @@ func (sr SpatialReference) SetMercator(
 func (sr SpatialReference) SetMollweide(
 	centralMeridian, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetMollweide(
 		sr.cval,
 		C.double(centralMeridian),
@@ -997,6 +1113,7 @@ func (sr SpatialReference) SetMollweide(
 func (sr SpatialReference) SetNZMG(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetNZMG(
 		sr.cval,
 		C.double(centerLat),
@@ -1010,6 +1127,7 @@ func (sr SpatialReference) SetNZMG(
 func (sr SpatialReference) SetOS(
 	originLat, meridian, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetOS(
 		sr.cval,
 		C.double(originLat),
@@ -1024,6 +1142,7 @@ func (sr SpatialReference) SetOS(
 func (sr SpatialReference) SetOrthographic(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetOrthographic(
 		sr.cval,
 		C.double(centerLat),
@@ -1037,6 +1156,7 @@ func (sr SpatialReference) SetOrthographic(
 func (sr SpatialReference) SetPolyconic(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetPolyconic(
 		sr.cval,
 		C.double(centerLat),
@@ -1050,6 +1170,7 @@ func (sr SpatialReference) SetPolyconic(
 func (sr SpatialReference) SetPS(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetPS(
 		sr.cval,
 		C.double(centerLat),
@@ -1064,6 +1185,7 @@ func (sr SpatialReference) SetPS(
 func (sr SpatialReference) SetRobinson(
 	centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetRobinson(
 		sr.cval,
 		C.double(centerLong),
@@ -1076,6 +1198,7 @@ func (sr SpatialReference) SetRobinson(
 func (sr SpatialReference) SetSinusoidal(
 	centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetSinusoidal(
 		sr.cval,
 		C.double(centerLong),
@@ -1088,6 +1211,7 @@ func (sr SpatialReference) SetSinusoidal(
 func (sr SpatialReference) SetStereographic(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetStereographic(
 		sr.cval,
 		C.double(centerLat),
@@ -1102,6 +1226,7 @@ func (sr SpatialReference) SetStereographic(
 func (sr SpatialReference) SetSOC(
 	latitudeOfOrigin, centralMeridian, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetSOC(
 		sr.cval,
 		C.double(latitudeOfOrigin),
@@ -1115,6 +1240,7 @@ func (sr SpatialReference) SetSOC(
 func (sr SpatialReference) SetTM(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetTM(
 		sr.cval,
 		C.double(centerLat),
@@ -1129,6 +1255,7 @@ func (sr SpatialReference) SetTM(
 func (sr SpatialReference) SetTMVariant(
 	variantName string, centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	cName := C.CString(variantName)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetTMVariant(
@@ -1146,6 +1273,7 @@ func (sr SpatialReference) SetTMVariant(
 func (sr SpatialReference) SetTMG(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetTMG(
 		sr.cval,
 		C.double(centerLat),
@@ -1159,6 +1287,7 @@ func (sr SpatialReference) SetTMG(
 func (sr SpatialReference) SetTMSO(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetTMSO(
 		sr.cval,
 		C.double(centerLat),
@@ -1173,6 +1302,7 @@ func (sr SpatialReference) SetTMSO(
 func (sr SpatialReference) SetVDG(
 	centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr.ref)
 	return C.OSRSetVDG(
 		sr.cval,
 		C.double(centerLong),
@@ -1199,10 +1329,17 @@ func CreateCoordinateTransform(
 	source SpatialReference,
 	dest SpatialReference,
 ) CoordinateTransform {
+	defer runtime.KeepAlive(source.ref)
+	defer runtime.KeepAlive(dest.ref)
 	ct := C.OCTNewCoordinateTransformation(source.cval, dest.cval)
 	return CoordinateTransform{ct}
 }
 
+// Test if the transformation could not be created
+func (ct CoordinateTransform) IsNull() bool {
+	return ct.cval == nil
+}
+
 // Destroy CoordinateTransform
 func (ct CoordinateTransform) Destroy() {
 	C.OCTDestroyCoordinateTransformation(ct.cval)
@@ -0,0 +1,483 @@
+package gdal
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/* --------------------------------------------- */
+/* Typed options for the utility wrappers        */
+/* --------------------------------------------- */
+
+// ErrInvalidOptions is wrapped by the errors returned when typed utility
+// options fail validation.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Extent is an axis aligned bounding box in georeferenced coordinates.
+type Extent struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// argBuilder accumulates the argv of a command line utility and remembers
+// the first validation failure.
+type argBuilder struct {
+	app  string
+	args []string
+	err  error
+}
+
+func (b *argBuilder) add(args ...string) {
+	b.args = append(b.args, args...)
+}
+
+func (b *argBuilder) fail(format string, a ...interface{}) {
+	if b.err == nil {
+		b.err = fmt.Errorf("%s: %w: %s", b.app, ErrInvalidOptions, fmt.Sprintf(format, a...))
+	}
+}
+
+func (b *argBuilder) result() ([]string, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.args, nil
+}
+
+func formatFloat(val float64) string {
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
+
+func formatFloats(vals []float64) string {
+	s := make([]string, len(vals))
+	for i, val := range vals {
+		s[i] = formatFloat(val)
+	}
+	return strings.Join(s, " ")
+}
+
+func (b *argBuilder) str(flag, val string) {
+	if val != "" {
+		b.add(flag, val)
+	}
+}
+
+func (b *argBuilder) flag(flag string, set bool) {
+	if set {
+		b.add(flag)
+	}
+}
+
+func (b *argBuilder) dataType(flag string, dataType DataType) {
+	if dataType != Unknown {
+		b.add(flag, dataType.Name())
+	}
+}
+
+func (b *argBuilder) keyValues(flag string, options []string) {
+	for _, opt := range options {
+		if !strings.Contains(opt, "=") || strings.HasPrefix(opt, "=") {
+			b.fail("%s %q is not of the form KEY=VALUE", flag, opt)
+			continue
+		}
+		b.add(flag, opt)
+	}
+}
+
+func (b *argBuilder) resolution(xRes, yRes float64) {
+	if xRes == 0 && yRes == 0 {
+		return
+	}
+	if xRes <= 0 || yRes <= 0 {
+		b.fail("-tr needs a positive XRes and YRes, got %v and %v", xRes, yRes)
+		return
+	}
+	b.add("-tr", formatFloat(xRes), formatFloat(yRes))
+}
+
+func (b *argBuilder) size(flag string, width, height int) {
+	if width == 0 && height == 0 {
+		return
+	}
+	if width < 0 || height < 0 {
+		b.fail("%s needs a non-negative Width and Height, got %d and %d", flag, width, height)
+		return
+	}
+	b.add(flag, strconv.Itoa(width), strconv.Itoa(height))
+}
+
+func (b *argBuilder) extent(flag string, extent *Extent) {
+	if extent == nil {
+		return
+	}
+	if !(extent.MinX < extent.MaxX) || !(extent.MinY < extent.MaxY) {
+		b.fail("%s extent %+v is empty or inverted", flag, *extent)
+		return
+	}
+	b.add(flag,
+		formatFloat(extent.MinX), formatFloat(extent.MinY),
+		formatFloat(extent.MaxX), formatFloat(extent.MaxY))
+}
+
+func (b *argBuilder) bands(flag string, bands []int) {
+	for _, band := range bands {
+		if band < 1 {
+			b.fail("%s band %d is out of range, bands are numbered from 1", flag, band)
+			continue
+		}
+		b.add(flag, strconv.Itoa(band))
+	}
+}
+
+func (b *argBuilder) resampling(resampleAlg ResampleAlg, allowed ...ResampleAlg) {
+	if resampleAlg == GRA_NearestNeighbour {
+		return
+	}
+	for _, alg := range allowed {
+		if alg == resampleAlg {
+			b.add("-r", resampleAlg.Name())
+			return
+		}
+	}
+	b.fail("resampling algorithm %d is not supported", resampleAlg)
+}
+
+// WarpOptions are the typed equivalent of the gdalwarp command line.
+// Zero values leave the corresponding setting to GDAL's default.
+type WarpOptions struct {
+	// Format is the output driver short name (-of).
+	Format string
+	// OutputType forces the output band data type (-ot).
+	OutputType DataType
+	// SourceSRS overrides the source spatial reference (-s_srs).
+	SourceSRS string
+	// TargetSRS is the output spatial reference (-t_srs).
+	TargetSRS string
+	// XRes and YRes set the output resolution (-tr).
+	XRes, YRes float64
+	// TargetAlignedPixels aligns the output extent on the resolution (-tap).
+	TargetAlignedPixels bool
+	// Width and Height set the output size in pixels (-ts).
+	Width, Height int
+	// Extent sets the output bounds (-te), expressed in ExtentSRS if set (-te_srs).
+	Extent    *Extent
+	ExtentSRS string
+	// Resampling is the resampling algorithm (-r).
+	Resampling ResampleAlg
+	// SrcNoData and DstNoData hold per band nodata values (-srcnodata, -dstnodata).
+	SrcNoData, DstNoData []float64
+	// SrcBands and DstBands select and map bands (-srcband, -dstband, GDAL >= 3.7).
+	SrcBands, DstBands []int
+	// SrcAlpha and DstAlpha treat the last band as alpha (-srcalpha, -dstalpha).
+	SrcAlpha, DstAlpha bool
+	// CreationOptions are KEY=VALUE driver creation options (-co).
+	CreationOptions []string
+	// WarpOptions are KEY=VALUE warp options (-wo).
+	WarpOptions []string
+	// Multithread enables multithreaded warping (-multi).
+	Multithread bool
+	// Overwrite overwrites the destination dataset (-overwrite).
+	Overwrite bool
+	// ExtraArgs are appended verbatim.
+	ExtraArgs []string
+}
+
+// Args validates the options and returns the equivalent gdalwarp arguments.
+func (opts WarpOptions) Args() ([]string, error) {
+	b := &argBuilder{app: "gdalwarp"}
+	b.str("-of", opts.Format)
+	b.dataType("-ot", opts.OutputType)
+	b.str("-s_srs", opts.SourceSRS)
+	b.str("-t_srs", opts.TargetSRS)
+	if (opts.XRes != 0 || opts.YRes != 0) && (opts.Width != 0 || opts.Height != 0) {
+		b.fail("-tr and -ts are mutually exclusive")
+	}
+	b.resolution(opts.XRes, opts.YRes)
+	if opts.TargetAlignedPixels && opts.XRes == 0 {
+		b.fail("-tap requires -tr")
+	}
+	b.flag("-tap", opts.TargetAlignedPixels)
+	b.size("-ts", opts.Width, opts.Height)
+	b.extent("-te", opts.Extent)
+	if opts.ExtentSRS != "" && opts.Extent == nil {
+		b.fail("-te_srs requires -te")
+	}
+	b.str("-te_srs", opts.ExtentSRS)
+	b.resampling(opts.Resampling,
+		GRA_Bilinear, GRA_Cubic, GRA_CubicSpline, GRA_Lanczos, GRA_Average, GRA_Mode,
+		GRA_Max, GRA_Min, GRA_Med, GRA_Q1, GRA_Q3, GRA_Sum, GRA_RMS)
+	if len(opts.SrcNoData) > 0 {
+		b.add("-srcnodata", formatFloats(opts.SrcNoData))
+	}
+	if len(opts.DstNoData) > 0 {
+		b.add("-dstnodata", formatFloats(opts.DstNoData))
+	}
+	if len(opts.DstBands) > 0 && len(opts.DstBands) != len(opts.SrcBands) {
+		b.fail("-dstband needs as many bands as -srcband")
+	}
+	b.bands("-srcband", opts.SrcBands)
+	b.bands("-dstband", opts.DstBands)
+	b.flag("-srcalpha", opts.SrcAlpha)
+	b.flag("-dstalpha", opts.DstAlpha)
+	b.keyValues("-co", opts.CreationOptions)
+	b.keyValues("-wo", opts.WarpOptions)
+	b.flag("-multi", opts.Multithread)
+	b.flag("-overwrite", opts.Overwrite)
+	b.add(opts.ExtraArgs...)
+	return b.result()
+}
+
+// TranslateOptions are the typed equivalent of the gdal_translate command
+// line. Zero values leave the corresponding setting to GDAL's default.
+type TranslateOptions struct {
+	// Format is the output driver short name (-of).
+	Format string
+	// OutputType forces the output band data type (-ot).
+	OutputType DataType
+	// Bands selects the input bands to copy, in order (-b).
+	Bands []int
+	// OutputSRS assigns a spatial reference to the output (-a_srs).
+	OutputSRS string
+	// XRes and YRes set the output resolution (-tr).
+	XRes, YRes float64
+	// Width and Height set the output size in pixels (-outsize).
+	Width, Height int
+	// ProjWin selects a subwindow in georeferenced coordinates (-projwin),
+	// expressed in ProjWinSRS if set (-projwin_srs).
+	ProjWin    *Extent
+	ProjWinSRS string
+	// Resampling is the resampling algorithm used when resizing (-r).
+	Resampling ResampleAlg
+	// NoData assigns a nodata value to the output bands (-a_nodata).
+	NoData *float64
+	// CreationOptions are KEY=VALUE driver creation options (-co).
+	CreationOptions []string
+	// Strict fails instead of downgrading when the output format
+	// can't represent the input (-strict).
+	Strict bool
+	// Stats computes and stores statistics on the output (-stats).
+	Stats bool
+	// ExtraArgs are appended verbatim.
+	ExtraArgs []string
+}
+
+// Args validates the options and returns the equivalent gdal_translate arguments.
+func (opts TranslateOptions) Args() ([]string, error) {
+	b := &argBuilder{app: "gdal_translate"}
+	b.str("-of", opts.Format)
+	b.dataType("-ot", opts.OutputType)
+	b.bands("-b", opts.Bands)
+	b.str("-a_srs", opts.OutputSRS)
+	if (opts.XRes != 0 || opts.YRes != 0) && (opts.Width != 0 || opts.Height != 0) {
+		b.fail("-tr and -outsize are mutually exclusive")
+	}
+	b.resolution(opts.XRes, opts.YRes)
+	b.size("-outsize", opts.Width, opts.Height)
+	if opts.ProjWin != nil {
+		// -projwin takes the upper left corner first
+		if !(opts.ProjWin.MinX < opts.ProjWin.MaxX) || !(opts.ProjWin.MinY < opts.ProjWin.MaxY) {
+			b.fail("-projwin extent %+v is empty or inverted", *opts.ProjWin)
+		} else {
+			b.add("-projwin",
+				formatFloat(opts.ProjWin.MinX), formatFloat(opts.ProjWin.MaxY),
+				formatFloat(opts.ProjWin.MaxX), formatFloat(opts.ProjWin.MinY))
+		}
+	}
+	if opts.ProjWinSRS != "" && opts.ProjWin == nil {
+		b.fail("-projwin_srs requires -projwin")
+	}
+	b.str("-projwin_srs", opts.ProjWinSRS)
+	b.resampling(opts.Resampling,
+		GRA_Bilinear, GRA_Cubic, GRA_CubicSpline, GRA_Lanczos, GRA_Average, GRA_Mode, GRA_RMS)
+	if opts.NoData != nil {
+		b.add("-a_nodata", formatFloat(*opts.NoData))
+	}
+	b.keyValues("-co", opts.CreationOptions)
+	b.flag("-strict", opts.Strict)
+	b.flag("-stats", opts.Stats)
+	b.add(opts.ExtraArgs...)
+	return b.result()
+}
+
+// VectorTranslateOptions are the typed equivalent of the ogr2ogr command
+// line. Zero values leave the corresponding setting to GDAL's default.
+type VectorTranslateOptions struct {
+	// Format is the output driver short name (-f).
+	Format string
+	// SourceSRS overrides the source spatial reference (-s_srs).
+	SourceSRS string
+	// TargetSRS reprojects the output to this spatial reference (-t_srs).
+	TargetSRS string
+	// Layers restricts the translation to the named source layers.
+	Layers []string
+	// SQL selects the features to translate with an SQL statement (-sql).
+	SQL string
+	// Where is an attribute filter (-where).
+	Where string
+	// SpatialFilter keeps only features intersecting the extent (-spat),
+	// expressed in SpatialFilterSRS if set (-spat_srs).
+	SpatialFilter    *Extent
+	SpatialFilterSRS string
+	// NewLayerName renames the output layer (-nln).
+	NewLayerName string
+	// GeometryType forces the output geometry type, e.g. "MULTIPOLYGON" (-nlt).
+	GeometryType string
+	// DatasetCreationOptions are KEY=VALUE dataset creation options (-dsco).
+	DatasetCreationOptions []string
+	// LayerCreationOptions are KEY=VALUE layer creation options (-lco).
+	LayerCreationOptions []string
+	// Append appends to an existing layer (-append).
+	Append bool
+	// Overwrite replaces existing layers (-overwrite).
+	Overwrite bool
+	// SkipFailures continues after a failure, skipping the failed feature (-skipfailures).
+	SkipFailures bool
+	// ExtraArgs are appended verbatim, before the layer names.
+	ExtraArgs []string
+}
+
+// Args validates the options and returns the equivalent ogr2ogr arguments.
+func (opts VectorTranslateOptions) Args() ([]string, error) {
+	b := &argBuilder{app: "ogr2ogr"}
+	b.str("-f", opts.Format)
+	b.str("-s_srs", opts.SourceSRS)
+	b.str("-t_srs", opts.TargetSRS)
+	if opts.SQL != "" && len(opts.Layers) > 0 {
+		b.fail("-sql and layer names are mutually exclusive")
+	}
+	b.str("-sql", opts.SQL)
+	b.str("-where", opts.Where)
+	b.extent("-spat", opts.SpatialFilter)
+	if opts.SpatialFilterSRS != "" && opts.SpatialFilter == nil {
+		b.fail("-spat_srs requires -spat")
+	}
+	b.str("-spat_srs", opts.SpatialFilterSRS)
+	if opts.NewLayerName != "" && len(opts.Layers) > 1 {
+		b.fail("-nln can only be used with a single layer")
+	}
+	b.str("-nln", opts.NewLayerName)
+	b.str("-nlt", opts.GeometryType)
+	b.keyValues("-dsco", opts.DatasetCreationOptions)
+	b.keyValues("-lco", opts.LayerCreationOptions)
+	if opts.Append && opts.Overwrite {
+		b.fail("-append and -overwrite are mutually exclusive")
+	}
+	b.flag("-append", opts.Append)
+	b.flag("-overwrite", opts.Overwrite)
+	b.flag("-skipfailures", opts.SkipFailures)
+	b.add(opts.ExtraArgs...)
+	// the library treats trailing positional arguments as layer names
+	b.add(opts.Layers...)
+	return b.result()
+}
+
+// RasterizeOptions are the typed equivalent of the gdal_rasterize command
+// line. Zero values leave the corresponding setting to GDAL's default.
+type RasterizeOptions struct {
+	// Format is the output driver short name (-of).
+	Format string
+	// OutputType forces the output band data type (-ot).
+	OutputType DataType
+	// Bands are the output bands to burn into (-b).
+	Bands []int
+	// BurnValues are the values burnt into the bands, one per band or a
+	// single value for all of them (-burn).
+	BurnValues []float64
+	// Attribute burns the value of this attribute field (-a).
+	Attribute string
+	// Use3D burns the Z value of the geometries (-3d).
+	Use3D bool
+	// Layers are the source layers to rasterize (-l).
+	Layers []string
+	// SQL selects the features to rasterize with an SQL statement (-sql).
+	SQL string
+	// Where is an attribute filter (-where).
+	Where string
+	// AllTouched burns every pixel touched by a geometry (-at).
+	AllTouched bool
+	// Invert burns the pixels not covered by a geometry (-i).
+	Invert bool
+	// NoData assigns a nodata value to the output bands (-a_nodata).
+	NoData *float64
+	// InitValues pre-initialize the output bands (-init).
+	InitValues []float64
+	// TargetSRS assigns a spatial reference to the output (-a_srs).
+	TargetSRS string
+	// XRes and YRes set the output resolution (-tr).
+	XRes, YRes float64
+	// TargetAlignedPixels aligns the output extent on the resolution (-tap).
+	TargetAlignedPixels bool
+	// Width and Height set the output size in pixels (-ts).
+	Width, Height int
+	// Extent sets the output bounds (-te).
+	Extent *Extent
+	// CreationOptions are KEY=VALUE driver creation options (-co).
+	CreationOptions []string
+	// ExtraArgs are appended verbatim.
+	ExtraArgs []string
+}
+
+// Args validates the options and returns the equivalent gdal_rasterize arguments.
+func (opts RasterizeOptions) Args() ([]string, error) {
+	b := &argBuilder{app: "gdal_rasterize"}
+	b.str("-of", opts.Format)
+	b.dataType("-ot", opts.OutputType)
+	b.bands("-b", opts.Bands)
+	sources := 0
+	if len(opts.BurnValues) > 0 {
+		sources++
+	}
+	if opts.Attribute != "" {
+		sources++
+	}
+	if opts.Use3D {
+		sources++
+	}
+	if sources > 1 {
+		b.fail("-burn, -a and -3d are mutually exclusive")
+	}
+	if sources == 0 && !stringArrayContains(opts.ExtraArgs, "-burn") &&
+		!stringArrayContains(opts.ExtraArgs, "-a") && !stringArrayContains(opts.ExtraArgs, "-3d") {
+		b.fail("one of -burn, -a or -3d is required")
+	}
+	if len(opts.Bands) > 0 && len(opts.BurnValues) > 1 && len(opts.BurnValues) != len(opts.Bands) {
+		b.fail("-burn needs one value or one value per band, got %d values for %d bands",
+			len(opts.BurnValues), len(opts.Bands))
+	}
+	for _, val := range opts.BurnValues {
+		b.add("-burn", formatFloat(val))
+	}
+	b.str("-a", opts.Attribute)
+	b.flag("-3d", opts.Use3D)
+	for _, layer := range opts.Layers {
+		b.add("-l", layer)
+	}
+	if opts.SQL != "" && len(opts.Layers) > 0 {
+		b.fail("-sql and -l are mutually exclusive")
+	}
+	b.str("-sql", opts.SQL)
+	b.str("-where", opts.Where)
+	b.flag("-at", opts.AllTouched)
+	b.flag("-i", opts.Invert)
+	if opts.NoData != nil {
+		b.add("-a_nodata", formatFloat(*opts.NoData))
+	}
+	for _, val := range opts.InitValues {
+		b.add("-init", formatFloat(val))
+	}
+	b.str("-a_srs", opts.TargetSRS)
+	if (opts.XRes != 0 || opts.YRes != 0) && (opts.Width != 0 || opts.Height != 0) {
+		b.fail("-tr and -ts are mutually exclusive")
+	}
+	b.resolution(opts.XRes, opts.YRes)
+	if opts.TargetAlignedPixels && opts.XRes == 0 {
+		b.fail("-tap requires -tr")
+	}
+	b.flag("-tap", opts.TargetAlignedPixels)
+	b.size("-ts", opts.Width, opts.Height)
+	b.extent("-te", opts.Extent)
+	b.keyValues("-co", opts.CreationOptions)
+	b.add(opts.ExtraArgs...)
+	return b.result()
+}
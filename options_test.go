@@ -0,0 +1,93 @@
+package gdal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWarpOptionsArgs(t *testing.T) {
+	args, err := WarpOptions{
+		Format:          "GTiff",
+		TargetSRS:       "EPSG:3857",
+		XRes:            10,
+		YRes:            10,
+		Extent:          &Extent{MinX: 0, MinY: 0, MaxX: 100, MaxY: 50},
+		Resampling:      GRA_Cubic,
+		DstNoData:       []float64{-9999},
+		CreationOptions: []string{"COMPRESS=DEFLATE"},
+	}.Args()
+	if err != nil {
+		t.Fatalf("Args: %v", err)
+	}
+	want := []string{
+		"-of", "GTiff",
+		"-t_srs", "EPSG:3857",
+		"-tr", "10", "10",
+		"-te", "0", "0", "100", "50",
+		"-r", "cubic",
+		"-dstnodata", "-9999",
+		"-co", "COMPRESS=DEFLATE",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("got %q, want %q", args, want)
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	for name, args := range map[string]func() ([]string, error){
+		"single resolution": WarpOptions{XRes: 10}.Args,
+		"tr and ts":         WarpOptions{XRes: 10, YRes: 10, Width: 256, Height: 256}.Args,
+		"inverted extent":   WarpOptions{Extent: &Extent{MinX: 10, MaxX: 0, MinY: 0, MaxY: 10}}.Args,
+		"creation option":   TranslateOptions{CreationOptions: []string{"COMPRESS"}}.Args,
+		"band zero":         TranslateOptions{Bands: []int{0}}.Args,
+		"warp resampling":   TranslateOptions{Resampling: GRA_Q1}.Args,
+		"nln many layers":   VectorTranslateOptions{Layers: []string{"a", "b"}, NewLayerName: "c"}.Args,
+		"burn and attr":     RasterizeOptions{BurnValues: []float64{1}, Attribute: "code"}.Args,
+		"nothing to burn":   RasterizeOptions{XRes: 10, YRes: 10}.Args,
+		"burn per band":     RasterizeOptions{Bands: []int{1, 2, 3}, BurnValues: []float64{1, 2}}.Args,
+	} {
+		if _, err := args(); !errors.Is(err, ErrInvalidOptions) {
+			t.Errorf("%s: got %v, want ErrInvalidOptions", name, err)
+		}
+	}
+}
+
+func TestVectorTranslateOptionsLayers(t *testing.T) {
+	args, err := VectorTranslateOptions{
+		Format:    "GeoJSON",
+		TargetSRS: "EPSG:4326",
+		Layers:    []string{"test"},
+		ExtraArgs: []string{"-progress"},
+	}.Args()
+	if err != nil {
+		t.Fatalf("Args: %v", err)
+	}
+	// Extra arguments come before the layer names, which ogr2ogr takes as
+	// trailing positional arguments.
+	want := []string{"-f", "GeoJSON", "-t_srs", "EPSG:4326", "-progress", "test"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("got %q, want %q", args, want)
+	}
+}
+
+func TestTranslateWithOptions(t *testing.T) {
+	srcDS, err := Open("testdata/tiles.gpkg", ReadOnly)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer srcDS.Close()
+
+	dstDS, err := TranslateWithOptions("", srcDS, TranslateOptions{
+		Bands: []int{1},
+		Width: 64, Height: 64,
+		Resampling: GRA_Average,
+	})
+	if err != nil {
+		t.Fatalf("TranslateWithOptions: %v", err)
+	}
+	defer dstDS.Close()
+	if dstDS.RasterCount() != 1 || dstDS.RasterXSize() != 64 {
+		t.Errorf("got %d bands of width %d, want 1 band of width 64", dstDS.RasterCount(), dstDS.RasterXSize())
+	}
+}
@@ -0,0 +1,229 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Overview management                                             */
+/* -------------------------------------------------------------------- */
+
+// OverviewLevel describes an overview of a band.
+type OverviewLevel struct {
+	// Index is the index of the overview, as passed to RasterBand.Overview.
+	Index int
+	// Factor is the decimation factor of the overview relative to the
+	// band, rounded to the nearest integer.
+	Factor       int
+	XSize, YSize int
+}
+
+// Overviews lists the overviews of the band, from the largest.
+func (rasterBand RasterBand) Overviews() []OverviewLevel {
+	count := rasterBand.OverviewCount()
+	levels := make([]OverviewLevel, 0, count)
+	for i := 0; i < count; i++ {
+		overview := rasterBand.Overview(i)
+		if overview.cval == nil {
+			continue
+		}
+		level := OverviewLevel{Index: i, XSize: overview.XSize(), YSize: overview.YSize()}
+		if level.XSize > 0 {
+			level.Factor = int(math.Round(float64(rasterBand.XSize()) / float64(level.XSize)))
+		}
+		levels = append(levels, level)
+	}
+	return levels
+}
+
+// Overviews lists the overviews of the first band of the dataset, which
+// usually has the same ones as the others.
+func (dataset Dataset) Overviews() []OverviewLevel {
+	if dataset.RasterCount() == 0 {
+		return nil
+	}
+	return dataset.RasterBand(1).Overviews()
+}
+
+// ClearOverviews removes all the overviews of the dataset, when the
+// driver supports it.
+func (dataset Dataset) ClearOverviews() error {
+	defer runtime.KeepAlive(dataset.ref)
+	cResampling := C.CString("NONE")
+	defer C.free(unsafe.Pointer(cResampling))
+
+	return cplCall("GDALBuildOverviews", func() C.CPLErr {
+		return C.GDALBuildOverviews(dataset.cval, cResampling, 0, nil, 0, nil, nil, nil)
+	})
+}
+
+// OverviewOptions configure Dataset.BuildOverviewsWithOptions.
+type OverviewOptions struct {
+	// Factors are the decimation factors of the overviews to build, e.g.
+	// 2, 4, 8. Existing overviews with the same factors are regenerated.
+	Factors []int
+	// Resampling is the resampling algorithm, as accepted by gdaladdo
+	// ("nearest", "average", "mode", ...), "nearest" if empty.
+	Resampling string
+	// LevelResampling overrides Resampling for some factors.
+	LevelResampling map[int]string
+	// Bands lists the bands (1-based) to build overviews for, all if
+	// empty.
+	Bands []int
+	// External builds the overviews in an external .ovr file rather than
+	// in the dataset. If the dataset is opened for update, the file is
+	// reopened read-only to do so, and the dataset has to be reopened to
+	// see the new overviews.
+	External bool
+	// CreationOptions are the configuration options controlling the
+	// overview format, such as COMPRESS_OVERVIEW=DEFLATE,
+	// PREDICTOR_OVERVIEW=2, BIGTIFF_OVERVIEW=YES or
+	// INTERLEAVE_OVERVIEW=PIXEL. They only apply to this build.
+	CreationOptions []string
+	// Progress, if not nil, is called with ProgressData as the overviews
+	// are built. Returning 0 cancels the build.
+	Progress     ProgressFunc
+	ProgressData interface{}
+}
+
+// BuildOverviewsWithOptions is BuildOverviews with typed options.
+func (dataset Dataset) BuildOverviewsWithOptions(opts OverviewOptions) error {
+	if len(opts.Factors) == 0 {
+		return fmt.Errorf("no overview factors")
+	}
+	for _, factor := range opts.Factors {
+		if factor < 2 {
+			return fmt.Errorf("invalid overview factor %d", factor)
+		}
+	}
+	if opts.External && dataset.Access() == Update {
+		readOnly, err := Open(dataset.Description(), ReadOnly)
+		if err != nil {
+			return err
+		}
+		defer readOnly.Close()
+		dataset = readOnly
+	}
+
+	// Build the factors sharing a resampling algorithm together, so GDAL
+	// can compute each level from the previous one.
+	groups := map[string][]int{}
+	var algorithms []string
+	for _, factor := range opts.Factors {
+		resampling := opts.Resampling
+		if r, ok := opts.LevelResampling[factor]; ok {
+			resampling = r
+		}
+		if resampling == "" {
+			resampling = "nearest"
+		}
+		if _, ok := groups[resampling]; !ok {
+			algorithms = append(algorithms, resampling)
+		}
+		groups[resampling] = append(groups[resampling], factor)
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	restore, err := setThreadLocalConfigOptions(opts.CreationOptions)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	done := 0
+	for _, resampling := range algorithms {
+		factors := groups[resampling]
+		sort.Ints(factors)
+		start, share := float64(done)/float64(len(opts.Factors)), float64(len(factors))/float64(len(opts.Factors))
+		progress := func(complete float64, message string, data interface{}) int {
+			if opts.Progress == nil {
+				return 1
+			}
+			return opts.Progress(start+complete*share, message, data)
+		}
+		if err := dataset.buildOverviews(resampling, factors, opts.Bands, progress, opts.ProgressData); err != nil {
+			return err
+		}
+		done += len(factors)
+	}
+	return nil
+}
+
+func (dataset Dataset) buildOverviews(resampling string, factors, bands []int, progress ProgressFunc, data interface{}) error {
+	defer runtime.KeepAlive(dataset.ref)
+	cResampling := C.CString(strings.ToUpper(resampling))
+	defer C.free(unsafe.Pointer(cResampling))
+	cFactors := IntSliceToCInt(factors)
+	var cBands *C.int
+	if len(bands) > 0 {
+		cBands = (*C.int)(unsafe.Pointer(&IntSliceToCInt(bands)[0]))
+	}
+	key, release := registerProgress(progress, data)
+	defer release()
+
+	return cplCall("GDALBuildOverviews", func() C.CPLErr {
+		return C.GDALBuildOverviews(
+			dataset.cval,
+			cResampling,
+			C.int(len(factors)),
+			(*C.int)(unsafe.Pointer(&cFactors[0])),
+			C.int(len(bands)),
+			cBands,
+			C.goGDALProgressFuncRegistryProxyB(),
+			key,
+		)
+	})
+}
+
+// setThreadLocalConfigOptions sets KEY=VALUE configuration options for the
+// current thread, which must be locked, and returns a function restoring
+// their previous values.
+func setThreadLocalConfigOptions(options []string) (restore func(), err error) {
+	type saved struct {
+		key   *C.char
+		value *C.char
+	}
+	var previous []saved
+	restore = func() {
+		for i := len(previous) - 1; i >= 0; i-- {
+			C.CPLSetThreadLocalConfigOption(previous[i].key, previous[i].value)
+			C.free(unsafe.Pointer(previous[i].key))
+			if previous[i].value != nil {
+				C.free(unsafe.Pointer(previous[i].value))
+			}
+		}
+	}
+	for _, option := range options {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			restore()
+			return nil, fmt.Errorf("invalid option %q, want KEY=VALUE", option)
+		}
+		key := C.CString(kv[0])
+		var old *C.char
+		if value := C.CPLGetThreadLocalConfigOption(key, nil); value != nil {
+			old = C.CString(C.GoString(value))
+		}
+		previous = append(previous, saved{key, old})
+		value := C.CString(kv[1])
+		C.CPLSetThreadLocalConfigOption(key, value)
+		C.free(unsafe.Pointer(value))
+	}
+	return restore, nil
+}
@@ -0,0 +1,98 @@
+package gdal
+
+import (
+	"testing"
+)
+
+func TestOverviews(t *testing.T) {
+	driver, err := GetDriverByName("GTiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("/vsimem/overviews.tif", 8, 8, 1, Byte, nil)
+	defer ds.Close()
+	band := ds.RasterBand(1)
+	if err := band.Fill(10, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls int
+	err = ds.BuildOverviewsWithOptions(OverviewOptions{
+		Factors:         []int{2, 4},
+		Resampling:      "average",
+		LevelResampling: map[int]string{4: "nearest"},
+		Progress: func(complete float64, message string, data interface{}) int {
+			if complete < 0 || complete > 1 {
+				t.Errorf("progress %v out of range", complete)
+			}
+			calls++
+			return 1
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildOverviewsWithOptions: %v", err)
+	}
+	if calls == 0 {
+		t.Error("progress was not reported")
+	}
+	levels := ds.Overviews()
+	if len(levels) != 2 || levels[0] != (OverviewLevel{0, 2, 4, 4}) || levels[1] != (OverviewLevel{1, 4, 2, 2}) {
+		t.Fatalf("got levels %+v", levels)
+	}
+
+	if err := band.Fill(20, 0); err != nil {
+		t.Fatal(err)
+	}
+	overview := band.Overview(0)
+	if err := band.RegenerateOverviews([]RasterBand{overview}, "AVERAGE", nil, nil); err != nil {
+		t.Fatalf("RegenerateOverviews: %v", err)
+	}
+	if got, err := ReadWindow[uint8](overview, Window{XSize: 1, YSize: 1}); err != nil || got[0] != 20 {
+		t.Errorf("regenerated overview: got %v, %v, want 20", got, err)
+	}
+
+	if err := ds.ClearOverviews(); err != nil {
+		t.Fatalf("ClearOverviews: %v", err)
+	}
+	if levels := ds.Overviews(); len(levels) != 0 {
+		t.Errorf("got levels %+v after ClearOverviews", levels)
+	}
+
+	if err := ds.BuildOverviewsWithOptions(OverviewOptions{Factors: []int{1}}); err == nil {
+		t.Error("factor 1: got no error")
+	}
+	if err := ds.BuildOverviewsWithOptions(OverviewOptions{Factors: []int{2}, CreationOptions: []string{"COMPRESS_OVERVIEW"}}); err == nil {
+		t.Error("invalid creation option: got no error")
+	}
+}
+
+func TestExternalOverviews(t *testing.T) {
+	driver, err := GetDriverByName("GTiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("/vsimem/external.tif", 8, 8, 1, Byte, nil)
+	ds.Close()
+	ds, err = Open("/vsimem/external.tif", Update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ds.BuildOverviewsWithOptions(OverviewOptions{
+		Factors:         []int{2},
+		External:        true,
+		CreationOptions: []string{"COMPRESS_OVERVIEW=DEFLATE"},
+	})
+	ds.Close()
+	if err != nil {
+		t.Fatalf("BuildOverviewsWithOptions: %v", err)
+	}
+
+	ovr, err := Open("/vsimem/external.tif.ovr", ReadOnly)
+	if err != nil {
+		t.Fatalf("no external overview file: %v", err)
+	}
+	defer ovr.Close()
+	if compression := ovr.MetadataItem("COMPRESSION", "IMAGE_STRUCTURE"); compression != "DEFLATE" {
+		t.Errorf("got compression %q, want DEFLATE", compression)
+	}
+}
@@ -0,0 +1,157 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      VRT derived band pixel functions                                */
+/* -------------------------------------------------------------------- */
+
+// PixelFunc computes the pixels of a VRT derived band. sources holds the
+// pixels of each source of the band, and out must be filled with the
+// output pixels, all xSize by ySize in row major order.
+type PixelFunc[T, U Numeric] func(sources [][]T, out []U, xSize, ySize int) error
+
+// pixelFunc is a PixelFunc working on the raw buffers passed by GDAL.
+type pixelFunc func(sources []unsafe.Pointer, srcType DataType, out unsafe.Pointer, outType DataType, xSize, ySize, pixelSpace, lineSpace int) error
+
+var pixelFuncs = struct {
+	sync.RWMutex
+	slots map[string]int
+	funcs [C.GO_GDAL_PIXEL_FUNC_SLOTS]pixelFunc
+}{slots: make(map[string]int)}
+
+// AddDerivedBandPixelFunc registers fn under name, so VRT derived bands
+// can use it as their PixelFunctionType:
+//
+//	<VRTRasterBand dataType="Float32" band="1" subClass="VRTDerivedRasterBand">
+//	  <PixelFunctionType>ndvi</PixelFunctionType>
+//	  <SourceTransferType>Float32</SourceTransferType>
+//	  <SimpleSource>...</SimpleSource>
+//	  <SimpleSource>...</SimpleSource>
+//	</VRTRasterBand>
+//
+// The source pixels are converted to T and the output pixels from U, so
+// fn works whatever SourceTransferType and the requested buffer type are.
+// fn may be called concurrently, from threads started by GDAL.
+//
+// Registering a name again replaces its function. GDAL can't unregister
+// pixel functions, so at most 32 names can be registered by a process.
+func AddDerivedBandPixelFunc[T, U Numeric](name string, fn PixelFunc[T, U]) error {
+	pixelFuncs.Lock()
+	defer pixelFuncs.Unlock()
+
+	wrapped := wrapPixelFunc(fn)
+	if slot, ok := pixelFuncs.slots[name]; ok {
+		pixelFuncs.funcs[slot] = wrapped
+		return nil
+	}
+	slot := len(pixelFuncs.slots)
+	if slot >= len(pixelFuncs.funcs) {
+		return fmt.Errorf("cannot register pixel function %q: all %d slots are used", name, len(pixelFuncs.funcs))
+	}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	err := cplCall("GDALAddDerivedBandPixelFunc", func() C.CPLErr {
+		return C.GDALAddDerivedBandPixelFunc(cName, C.goGDALPixelFunc(C.int(slot)))
+	})
+	if err != nil {
+		return err
+	}
+	pixelFuncs.slots[name] = slot
+	pixelFuncs.funcs[slot] = wrapped
+	return nil
+}
+
+func wrapPixelFunc[T, U Numeric](fn PixelFunc[T, U]) pixelFunc {
+	inType, outType := DataTypeOf[T](), DataTypeOf[U]()
+	return func(sources []unsafe.Pointer, srcType DataType, out unsafe.Pointer, bufType DataType, xSize, ySize, pixelSpace, lineSpace int) error {
+		n := xSize * ySize
+		if n == 0 {
+			return nil
+		}
+		in := make([][]T, len(sources))
+		for i, src := range sources {
+			in[i] = make([]T, n)
+			copyWords(src, srcType, srcType.Size()/8, unsafe.Pointer(&in[i][0]), inType, inType.Size()/8, n)
+		}
+		result := make([]U, n)
+		if err := fn(in, result, xSize, ySize); err != nil {
+			return err
+		}
+		for y := 0; y < ySize; y++ {
+			copyWords(
+				unsafe.Pointer(&result[y*xSize]), outType, outType.Size()/8,
+				unsafe.Add(out, y*lineSpace), bufType, pixelSpace,
+				xSize,
+			)
+		}
+		return nil
+	}
+}
+
+func copyWords(src unsafe.Pointer, srcType DataType, srcStride int, dst unsafe.Pointer, dstType DataType, dstStride int, count int) {
+	C.GDALCopyWords(
+		src, C.GDALDataType(srcType), C.int(srcStride),
+		dst, C.GDALDataType(dstType), C.int(dstStride),
+		C.int(count),
+	)
+}
+
+//export goGDALPixelFuncProxy
+func goGDALPixelFuncProxy(
+	slot C.int,
+	sources *unsafe.Pointer, nSources C.int,
+	data unsafe.Pointer,
+	xSize, ySize C.int,
+	srcType, bufType C.GDALDataType,
+	pixelSpace, lineSpace C.int,
+) (result C.CPLErr) {
+	pixelFuncs.RLock()
+	fn := pixelFuncs.funcs[slot]
+	pixelFuncs.RUnlock()
+
+	fail := func(msg string) C.CPLErr {
+		cMsg := C.CString(msg)
+		defer C.free(unsafe.Pointer(cMsg))
+		C.goCPLError(C.CE_Failure, C.CPLE_AppDefined, cMsg)
+		return C.CE_Failure
+	}
+	defer func() {
+		// A panic must not unwind through GDAL's stack.
+		if r := recover(); r != nil {
+			result = fail(fmt.Sprintf("pixel function panicked: %v", r))
+		}
+	}()
+
+	if fn == nil {
+		return fail(fmt.Sprintf("no pixel function registered in slot %d", slot))
+	}
+	var srcs []unsafe.Pointer
+	if nSources > 0 {
+		srcs = unsafe.Slice(sources, int(nSources))
+	}
+	err := fn(
+		srcs, DataType(srcType),
+		data, DataType(bufType),
+		int(xSize), int(ySize), int(pixelSpace), int(lineSpace),
+	)
+	if err != nil {
+		return fail(err.Error())
+	}
+	return C.CE_None
+}
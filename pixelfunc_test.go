@@ -0,0 +1,71 @@
+package gdal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAddDerivedBandPixelFunc(t *testing.T) {
+	driver, err := GetDriverByName("GTiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, values := range [][]uint8{{1, 2, 3, 4}, {10, 20, 30, 40}} {
+		ds := driver.Create(fmt.Sprintf("/vsimem/pixelfunc%d.tif", i), 2, 2, 1, Byte, nil)
+		if err := WriteWindow(ds.RasterBand(1), Window{XSize: 2, YSize: 2}, values); err != nil {
+			t.Fatal(err)
+		}
+		ds.Close()
+	}
+
+	err = AddDerivedBandPixelFunc("gotest_sum", func(sources [][]float64, out []float32, xSize, ySize int) error {
+		for i := range out {
+			for _, src := range sources {
+				out[i] += float32(src[i])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("AddDerivedBandPixelFunc: %v", err)
+	}
+	err = AddDerivedBandPixelFunc("gotest_fail", func(sources [][]uint8, out []uint8, xSize, ySize int) error {
+		return errors.New("no luck")
+	})
+	if err != nil {
+		t.Fatalf("AddDerivedBandPixelFunc: %v", err)
+	}
+
+	vrt := func(function string) string {
+		return `<VRTDataset rasterXSize="2" rasterYSize="2">
+  <VRTRasterBand dataType="Float32" band="1" subClass="VRTDerivedRasterBand">
+    <PixelFunctionType>` + function + `</PixelFunctionType>
+    <SimpleSource><SourceFilename>/vsimem/pixelfunc0.tif</SourceFilename><SourceBand>1</SourceBand></SimpleSource>
+    <SimpleSource><SourceFilename>/vsimem/pixelfunc1.tif</SourceFilename><SourceBand>1</SourceBand></SimpleSource>
+  </VRTRasterBand>
+</VRTDataset>`
+	}
+
+	ds, err := Open(vrt("gotest_sum"), ReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+	got, err := ReadWindow[int16](ds.RasterBand(1), Window{XSize: 2, YSize: 2})
+	if err != nil {
+		t.Fatalf("ReadWindow: %v", err)
+	}
+	if fmt.Sprint(got) != "[11 22 33 44]" {
+		t.Errorf("got %v, want [11 22 33 44]", got)
+	}
+
+	failing, err := Open(vrt("gotest_fail"), ReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer failing.Close()
+	if _, err := ReadWindow[int16](failing.RasterBand(1), Window{XSize: 2, YSize: 2}); err == nil {
+		t.Error("failing pixel function: got no error")
+	}
+}
@@ -0,0 +1,448 @@
+package gdal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Band statistics                                                 */
+/* -------------------------------------------------------------------- */
+
+// StatsOptions configure RasterBand.Stats.
+type StatsOptions struct {
+	// Window restricts the statistics to a window of the band.
+	Window *Window
+	// Polygon restricts the statistics to the pixels whose center lies
+	// inside a polygon, in the georeferenced coordinates of the band's
+	// dataset. Holes are honored.
+	Polygon *GeoJSONPolygon
+
+	// IgnoreNoData counts pixels equal to the nodata value as valid.
+	IgnoreNoData bool
+	// IgnoreMask counts pixels masked out by the mask band as valid.
+	IgnoreMask bool
+
+	// Median requests BandStats.Median, and Percentiles lists the
+	// percentiles, between 0 and 100, to compute. They are interpolated
+	// linearly between the closest ranks.
+	Median      bool
+	Percentiles []float64
+
+	// MaxDistinctValues caps the number of distinct valid values held in
+	// memory for the median and percentiles, 1<<22 if 0. Stats fails on
+	// bands having more, which only 8 and 16 bit bands cannot.
+	MaxDistinctValues int
+
+	// Buckets is the number of histogram buckets, no histogram being
+	// computed if 0. The histogram spans HistogramMin to HistogramMax,
+	// which default to the minimum and maximum of the valid pixels when
+	// both are 0.
+	Buckets                    int
+	HistogramMin, HistogramMax float64
+
+	// Progress, if not nil, is called after each block with ProgressData.
+	// Returning 0 cancels the computation.
+	Progress     ProgressFunc
+	ProgressData interface{}
+}
+
+// BandStats are the statistics computed by RasterBand.Stats.
+type BandStats struct {
+	// Total is the number of pixels in the window or polygon, and Count
+	// the number of valid ones among them.
+	Total, Count int
+	Min, Max     float64
+	Sum, Mean    float64
+	StdDev       float64
+	// Median is set if StatsOptions.Median is.
+	Median float64
+	// Percentiles holds the requested percentiles, in the order of
+	// StatsOptions.Percentiles.
+	Percentiles []float64
+	// Histogram is set if StatsOptions.Buckets is.
+	Histogram *Histogram
+}
+
+// ValidPercent returns the percentage of valid pixels.
+func (stats BandStats) ValidPercent() float64 {
+	if stats.Total == 0 {
+		return 0
+	}
+	return 100 * float64(stats.Count) / float64(stats.Total)
+}
+
+// Histogram counts pixel values in Counts equal width buckets from Min to
+// Max. Values equal to Max fall in the last bucket, out of range values
+// are not counted.
+type Histogram struct {
+	Min, Max float64
+	Counts   []int
+}
+
+// defaultMaxDistinctValues is the default StatsOptions.MaxDistinctValues.
+const defaultMaxDistinctValues = 1 << 22
+
+// Stats computes exact statistics of the valid pixels of the band, read
+// block by block. Pixels are valid unless they are NaN, equal to the
+// nodata value or masked out by the mask band. The number of pixels of
+// each distinct value is held in memory only to compute the median and
+// percentiles, see StatsOptions.MaxDistinctValues. A histogram spanning
+// the minimum to the maximum is otherwise computed in a second read of the
+// band.
+func (rasterBand RasterBand) Stats(opts StatsOptions) (BandStats, error) {
+	var stats BandStats
+	for _, p := range opts.Percentiles {
+		if !(p >= 0 && p <= 100) {
+			return stats, fmt.Errorf("percentile %v is not between 0 and 100", p)
+		}
+	}
+	if opts.Buckets < 0 {
+		return stats, fmt.Errorf("negative bucket count %d", opts.Buckets)
+	}
+	maxDistinct := opts.MaxDistinctValues
+	if maxDistinct <= 0 {
+		maxDistinct = defaultMaxDistinctValues
+	}
+
+	win := Window{XSize: rasterBand.XSize(), YSize: rasterBand.YSize()}
+	if opts.Window != nil {
+		if err := opts.Window.within(win.XSize, win.YSize); err != nil {
+			return stats, err
+		}
+		win = *opts.Window
+	}
+	var inside func(y int) []Window
+	if opts.Polygon != nil {
+		gt := rasterBand.GetDataset().GeoTransform()
+		inv, ok := gt.Inverse()
+		if !ok {
+			return stats, fmt.Errorf("geotransform %v is not invertible", [6]float64(gt))
+		}
+		extent := opts.Polygon.Extent()
+		bounds, err := gt.WindowForBounds(extent.MinX, extent.MinY, extent.MaxX, extent.MaxY)
+		if err != nil {
+			return stats, err
+		}
+		if win, ok = intersectWindows(win, bounds); !ok {
+			return stats, nil
+		}
+		inside = polygonSpans(opts.Polygon, inv, win)
+	}
+
+	noData, hasNoData := rasterBand.NoDataValue()
+	hasNoData = hasNoData && !opts.IgnoreNoData
+	var mask RasterBand
+	// Masks derived from the nodata value are skipped, nodata being
+	// handled above.
+	useMask := !opts.IgnoreMask && rasterBand.GetMaskFlags()&(GMF_AllValid|GMF_NoData) == 0
+	if useMask {
+		mask = rasterBand.GetMaskBand()
+	}
+
+	needCounts := opts.Median || len(opts.Percentiles) > 0
+	defaultRange := opts.HistogramMin == 0 && opts.HistogramMax == 0
+	passes := 1
+	if opts.Buckets > 0 && defaultRange && !needCounts {
+		passes = 2
+	}
+
+	var data []float64
+	var maskData []uint8
+	// scan calls visit with each valid pixel, and stops at its first error.
+	scan := func(pass int, visit func(v float64) error) error {
+		it := rasterBand.Blocks()
+		for it.Next() {
+			block, ok := intersectWindows(it.Block().Window, win)
+			if !ok {
+				continue
+			}
+			var err error
+			if data, err = ReadBlockData[float64](rasterBand, Block{Window: block}, data); err != nil {
+				return err
+			}
+			if useMask {
+				if maskData, err = ReadBlockData[uint8](mask, Block{Window: block}, maskData); err != nil {
+					return err
+				}
+			}
+			for y := 0; y < block.YSize; y++ {
+				spans := []Window{{XOff: block.XOff, XSize: block.XSize}}
+				if inside != nil {
+					spans = inside(block.YOff + y)
+				}
+				for _, span := range spans {
+					x0, x1 := span.XOff, span.XOff+span.XSize
+					if x0 < block.XOff {
+						x0 = block.XOff
+					}
+					if x1 > block.XOff+block.XSize {
+						x1 = block.XOff + block.XSize
+					}
+					for x := x0; x < x1; x++ {
+						i := y*block.XSize + x - block.XOff
+						if pass == 0 {
+							stats.Total++
+						}
+						v := data[i]
+						if math.IsNaN(v) || (hasNoData && v == noData) || (useMask && maskData[i] == 0) {
+							continue
+						}
+						if err := visit(v); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			done := (float64(pass) + float64(it.next)/float64(it.Len())) / float64(passes)
+			if opts.Progress != nil && opts.Progress(done, "", opts.ProgressData) == 0 {
+				return fmt.Errorf("statistics interrupted")
+			}
+		}
+		return nil
+	}
+
+	var counts map[float64]int
+	if needCounts {
+		counts = map[float64]int{}
+	}
+	var hist *Histogram
+	if opts.Buckets > 0 && !defaultRange {
+		hist = newHistogram(opts.HistogramMin, opts.HistogramMax, opts.Buckets)
+	}
+	// The standard deviation is accumulated with Welford's algorithm.
+	var mean, squares float64
+	err := scan(0, func(v float64) error {
+		stats.Count++
+		if stats.Count == 1 || v < stats.Min {
+			stats.Min = v
+		}
+		if stats.Count == 1 || v > stats.Max {
+			stats.Max = v
+		}
+		stats.Sum += v
+		delta := v - mean
+		mean += delta / float64(stats.Count)
+		squares += delta * (v - mean)
+		if hist != nil {
+			hist.add(v, 1)
+		}
+		if counts != nil {
+			counts[v]++
+			if len(counts) > maxDistinct {
+				return fmt.Errorf("more than %d distinct values", maxDistinct)
+			}
+		}
+		return nil
+	})
+	if err != nil || stats.Count == 0 {
+		return stats, err
+	}
+	stats.Mean = stats.Sum / float64(stats.Count)
+	stats.StdDev = math.Sqrt(squares / float64(stats.Count))
+
+	var sorted sortedCounts
+	if needCounts {
+		sorted = newSortedCounts(counts)
+	}
+	if opts.Median {
+		stats.Median = percentile(sorted, 50)
+	}
+	if len(opts.Percentiles) > 0 {
+		stats.Percentiles = make([]float64, len(opts.Percentiles))
+		for i, p := range opts.Percentiles {
+			stats.Percentiles[i] = percentile(sorted, p)
+		}
+	}
+	if opts.Buckets > 0 && defaultRange {
+		hist = newHistogram(stats.Min, stats.Max, opts.Buckets)
+		if needCounts {
+			for i, v := range sorted.values {
+				hist.add(v, sorted.count(i))
+			}
+		} else if err := scan(1, func(v float64) error {
+			hist.add(v, 1)
+			return nil
+		}); err != nil {
+			return stats, err
+		}
+	}
+	stats.Histogram = hist
+	return stats, nil
+}
+
+// sortedCounts holds distinct values in increasing order, with the
+// cumulated number of pixels up to each of them.
+type sortedCounts struct {
+	values     []float64
+	cumulative []int
+}
+
+func newSortedCounts(counts map[float64]int) sortedCounts {
+	s := sortedCounts{values: make([]float64, 0, len(counts))}
+	for v := range counts {
+		s.values = append(s.values, v)
+	}
+	sort.Float64s(s.values)
+	s.cumulative = make([]int, len(s.values))
+	total := 0
+	for i, v := range s.values {
+		total += counts[v]
+		s.cumulative[i] = total
+	}
+	return s
+}
+
+func (s sortedCounts) total() int {
+	return s.cumulative[len(s.cumulative)-1]
+}
+
+// count returns the number of pixels of the i-th value.
+func (s sortedCounts) count(i int) int {
+	if i == 0 {
+		return s.cumulative[0]
+	}
+	return s.cumulative[i] - s.cumulative[i-1]
+}
+
+// at returns the value of rank k, as if all the pixels were sorted.
+func (s sortedCounts) at(k int) float64 {
+	return s.values[sort.Search(len(s.cumulative), func(i int) bool { return s.cumulative[i] > k })]
+}
+
+// percentile interpolates the p-th percentile of the values.
+func percentile(sorted sortedCounts, p float64) float64 {
+	n := sorted.total()
+	rank := p / 100 * float64(n-1)
+	lower := int(math.Floor(rank))
+	if lower >= n-1 {
+		return sorted.at(n - 1)
+	}
+	v := sorted.at(lower)
+	return v + (rank-float64(lower))*(sorted.at(lower+1)-v)
+}
+
+func newHistogram(min, max float64, buckets int) *Histogram {
+	return &Histogram{Min: min, Max: max, Counts: make([]int, buckets)}
+}
+
+// add counts n pixels of value v.
+func (h *Histogram) add(v float64, n int) {
+	if v < h.Min || v > h.Max {
+		return
+	}
+	buckets := len(h.Counts)
+	width := (h.Max - h.Min) / float64(buckets)
+	i := buckets - 1
+	if width > 0 && v < h.Max {
+		i = int((v - h.Min) / width)
+		if i >= buckets {
+			i = buckets - 1
+		}
+	}
+	h.Counts[i] += n
+}
+
+func intersectWindows(a, b Window) (Window, bool) {
+	clipped, ok := Window{a.XOff - b.XOff, a.YOff - b.YOff, a.XSize, a.YSize}.Clip(b.XSize, b.YSize)
+	if !ok {
+		return Window{}, false
+	}
+	clipped.XOff += b.XOff
+	clipped.YOff += b.YOff
+	return clipped, true
+}
+
+// polygonSpans returns a function giving, for a line of win, the spans of
+// pixels whose center lies inside polygon, with the even-odd rule. inv
+// maps georeferenced coordinates to pixels.
+func polygonSpans(polygon *GeoJSONPolygon, inv GeoTransform, win Window) func(y int) []Window {
+	type edge struct{ x0, y0, x1, y1 float64 }
+	var edges []edge
+	for _, ring := range polygon.Coordinates {
+		for i := range ring {
+			j := (i + 1) % len(ring)
+			x0, y0 := inv.apply(ring[i][0], ring[i][1])
+			x1, y1 := inv.apply(ring[j][0], ring[j][1])
+			if y0 != y1 {
+				edges = append(edges, edge{x0, y0, x1, y1})
+			}
+		}
+	}
+	var xs []float64
+	return func(y int) []Window {
+		cy := float64(y) + 0.5
+		xs = xs[:0]
+		for _, e := range edges {
+			if (e.y0 <= cy) != (e.y1 <= cy) {
+				xs = append(xs, e.x0+(cy-e.y0)*(e.x1-e.x0)/(e.y1-e.y0))
+			}
+		}
+		sort.Float64s(xs)
+		var spans []Window
+		for i := 0; i+1 < len(xs); i += 2 {
+			// Pixels whose center x+0.5 lies in [xs[i], xs[i+1]).
+			first := int(math.Ceil(xs[i] - 0.5))
+			last := int(math.Ceil(xs[i+1]-0.5)) - 1
+			if first < win.XOff {
+				first = win.XOff
+			}
+			if last >= win.XOff+win.XSize {
+				last = win.XOff + win.XSize - 1
+			}
+			if first <= last {
+				spans = append(spans, Window{XOff: first, XSize: last - first + 1})
+			}
+		}
+		return spans
+	}
+}
+
+// SaveStats stores stats in the band, so that GetStatistics and
+// DefaultHistogram return them and they are written to the dataset or its
+// .aux.xml file: the usual STATISTICS_* metadata items, plus
+// STATISTICS_VALID_PERCENT, and the histogram as the default histogram.
+// opts are the options the stats were computed with: the median is saved
+// as STATISTICS_MEDIAN if requested, and the percentiles as
+// STATISTICS_P<percentile> items.
+func (rasterBand RasterBand) SaveStats(stats BandStats, opts StatsOptions) error {
+	if stats.Count == 0 {
+		return fmt.Errorf("no valid pixels")
+	}
+	if len(opts.Percentiles) != len(stats.Percentiles) {
+		return fmt.Errorf("got %d percentiles, stats have %d", len(opts.Percentiles), len(stats.Percentiles))
+	}
+	if err := rasterBand.SetStatistics(stats.Min, stats.Max, stats.Mean, stats.StdDev); err != nil {
+		return err
+	}
+	items := map[string]float64{
+		"STATISTICS_VALID_PERCENT": stats.ValidPercent(),
+	}
+	if opts.Median {
+		items["STATISTICS_MEDIAN"] = stats.Median
+	}
+	for i, p := range opts.Percentiles {
+		items["STATISTICS_P"+formatFloat(p)] = stats.Percentiles[i]
+	}
+	for key, value := range items {
+		if err := rasterBand.SetMetadataItem(key, formatFloat(value), ""); err != nil {
+			return err
+		}
+	}
+	if stats.Histogram != nil {
+		return rasterBand.SetDefaultHistogram(stats.Histogram.Min, stats.Histogram.Max, stats.Histogram.Counts)
+	}
+	return nil
+}
+
+// SavedPercentile returns the p-th percentile saved by SaveStats, if any.
+func (rasterBand RasterBand) SavedPercentile(p float64) (float64, bool) {
+	key := "STATISTICS_P" + formatFloat(p)
+	if p == 50 {
+		key = "STATISTICS_MEDIAN"
+	}
+	value, err := strconv.ParseFloat(rasterBand.MetadataItem(key, ""), 64)
+	return value, err == nil
+}
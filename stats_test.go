@@ -0,0 +1,107 @@
+package gdal
+
+import (
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("", 4, 4, 1, Float32, nil)
+	defer ds.Close()
+	if err := ds.SetGeoTransform(GeoTransform{0, 1, 0, 4, 0, -1}); err != nil {
+		t.Fatal(err)
+	}
+	band := ds.RasterBand(1)
+	values := make([]float32, 16)
+	for i := range values {
+		values[i] = float32(i + 1)
+	}
+	if err := WriteWindow(band, Window{XSize: 4, YSize: 4}, values); err != nil {
+		t.Fatal(err)
+	}
+	if err := band.SetNoDataValue(16); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := StatsOptions{Median: true, Percentiles: []float64{25}, Buckets: 2}
+	stats, err := band.Stats(opts)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Total != 16 || stats.Count != 15 || stats.Min != 1 || stats.Max != 15 || stats.Mean != 8 || stats.Median != 8 {
+		t.Errorf("got %+v", stats)
+	}
+	if len(stats.Percentiles) != 1 || stats.Percentiles[0] != 4.5 {
+		t.Errorf("got percentiles %v, want [4.5]", stats.Percentiles)
+	}
+	if h := stats.Histogram; h == nil || h.Min != 1 || h.Max != 15 || len(h.Counts) != 2 || h.Counts[0] != 7 || h.Counts[1] != 8 {
+		t.Errorf("got histogram %+v", stats.Histogram)
+	}
+
+	if all, err := band.Stats(StatsOptions{IgnoreNoData: true, IgnoreMask: true}); err != nil || all.Count != 16 {
+		t.Errorf("IgnoreNoData: got %+v, %v", all, err)
+	}
+	if all, err := band.Stats(StatsOptions{IgnoreNoData: true}); err != nil || all.Count != 16 || all.Max != 16 {
+		t.Errorf("IgnoreNoData without IgnoreMask: got %+v, %v", all, err)
+	}
+	if _, err := band.Stats(StatsOptions{Median: true, MaxDistinctValues: 10}); err == nil {
+		t.Error("more distinct values than MaxDistinctValues: got no error")
+	}
+	// Without a median or percentiles, values are not held in memory and
+	// the histogram range is found by a first read.
+	streamed, err := band.Stats(StatsOptions{Buckets: 2, MaxDistinctValues: 10})
+	if err != nil {
+		t.Fatalf("Stats without median: %v", err)
+	}
+	if streamed.Count != 15 || streamed.Mean != 8 || streamed.StdDev != stats.StdDev || streamed.Median != 0 {
+		t.Errorf("Stats without median: got %+v", streamed)
+	}
+	if h := streamed.Histogram; h == nil || h.Min != 1 || h.Max != 15 || h.Counts[0] != 7 || h.Counts[1] != 8 {
+		t.Errorf("streamed histogram: got %+v", streamed.Histogram)
+	}
+	ranged, err := band.Stats(StatsOptions{Buckets: 2, HistogramMin: 0, HistogramMax: 4})
+	if err != nil {
+		t.Fatalf("Stats with a histogram range: %v", err)
+	}
+	if h := ranged.Histogram; h == nil || h.Counts[0] != 1 || h.Counts[1] != 3 {
+		t.Errorf("histogram from 0 to 4: got %+v", ranged.Histogram)
+	}
+	if win, err := band.Stats(StatsOptions{Window: &Window{XSize: 2, YSize: 2}}); err != nil || win.Count != 4 || win.Mean != 3.5 {
+		t.Errorf("Window: got %+v, %v", win, err)
+	}
+	square := &GeoJSONPolygon{Type: "Polygon", Coordinates: [][][2]float64{
+		{{0, 2}, {2, 2}, {2, 4}, {0, 4}, {0, 2}},
+	}}
+	if poly, err := band.Stats(StatsOptions{Polygon: square}); err != nil || poly.Count != 4 || poly.Mean != 3.5 {
+		t.Errorf("Polygon: got %+v, %v", poly, err)
+	}
+	ring := &GeoJSONPolygon{Type: "Polygon", Coordinates: [][][2]float64{
+		{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+		{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}},
+	}}
+	if poly, err := band.Stats(StatsOptions{Polygon: ring, IgnoreNoData: true, IgnoreMask: true}); err != nil || poly.Count != 12 {
+		t.Errorf("Polygon with hole: got %+v, %v", poly, err)
+	}
+	if _, err := band.Stats(StatsOptions{Percentiles: []float64{101}}); err == nil {
+		t.Error("invalid percentile: got no error")
+	}
+
+	if err := band.SaveStats(stats, opts); err != nil {
+		t.Fatalf("SaveStats: %v", err)
+	}
+	if min, max, mean, _ := band.GetStatistics(0, 0); min != 1 || max != 15 || mean != 8 {
+		t.Errorf("GetStatistics: got %v, %v, %v", min, max, mean)
+	}
+	if p, ok := band.SavedPercentile(25); !ok || p != 4.5 {
+		t.Errorf("SavedPercentile: got %v, %v", p, ok)
+	}
+	if m, ok := band.SavedPercentile(50); !ok || m != 8 {
+		t.Errorf("saved median: got %v, %v", m, ok)
+	}
+	if min, max, buckets, counts, err := band.DefaultHistogram(0, DummyProgress, nil); err != nil || min != 1 || max != 15 || buckets != 2 || counts[1] != 8 {
+		t.Errorf("DefaultHistogram: got %v, %v, %v, %v, %v", min, max, buckets, counts, err)
+	}
+}
@@ -11,7 +11,9 @@ package gdal
 */
 import "C"
 import (
+	"context"
 	"fmt"
+	"runtime"
 	"unsafe"
 )
 
@@ -33,6 +35,24 @@ func stringArrayContains(array []string, needle string) bool {
 }
 
 func Warp(dstDS string, sourceDS []Dataset, options []string) (Dataset, error) {
+	return WarpContext(context.Background(), dstDS, sourceDS, options, nil, nil)
+}
+
+// WarpContext is Warp with progress reporting and cancellation. progress
+// may be nil. Once ctx is done GDAL is asked to abort, and the returned
+// error wraps ctx.Err().
+func WarpContext(
+	ctx context.Context,
+	dstDS string,
+	sourceDS []Dataset,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (Dataset, error) {
+	defer runtime.KeepAlive(sourceDS)
+	if len(sourceDS) == 0 {
+		return Dataset{}, fmt.Errorf("warp needs at least one source dataset")
+	}
 	if dstDS == "" {
 		dstDS = "MEM:::"
 		if !stringArrayContains(options, "-of") {
@@ -46,15 +66,28 @@ func Warp(dstDS string, sourceDS []Dataset, options []string) (Dataset, error) {
 		defer C.free(unsafe.Pointer(opts[i]))
 	}
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
 	warpopts := C.GDALWarpAppOptionsNew(
 		(**C.char)(unsafe.Pointer(&opts[0])),
 		(*C.GDALWarpAppOptionsForBinary)(unsafe.Pointer(nil)))
+	if warpopts == nil {
+		return Dataset{}, failure("GDALWarpAppOptionsNew", "invalid warp options")
+	}
 	defer C.GDALWarpAppOptionsFree(warpopts)
+	if key, release := contextProgress(ctx, progress, data); key != nil {
+		defer release()
+		C.GDALWarpAppOptionsSetProgress(warpopts, C.goGDALProgressFuncRegistryProxyB(), key)
+	}
 
 	srcDS := make([]C.GDALDatasetH, len(sourceDS))
 	for i, ds := range sourceDS {
 		srcDS[i] = ds.cval
 	}
+
 	var cerr C.int
 	cdstDS := C.CString(dstDS)
 	defer C.free(unsafe.Pointer(cdstDS))
@@ -62,14 +95,32 @@ func Warp(dstDS string, sourceDS []Dataset, options []string) (Dataset, error) {
 		C.int(len(sourceDS)),
 		(*C.GDALDatasetH)(unsafe.Pointer(&srcDS[0])),
 		warpopts, &cerr)
-	if cerr != 0 {
-		return Dataset{}, fmt.Errorf("warp failed with code %d", cerr)
+	if ds == nil || cerr != 0 {
+		err := contextError(ctx, failure("GDALWarp", "warp failed"))
+		if ds != nil {
+			C.GDALClose(ds)
+		}
+		return Dataset{}, err
 	}
-	return Dataset{ds}, nil
-
+	return ownedDataset(ds), nil
 }
 
 func Translate(dstDS string, sourceDS Dataset, options []string) (Dataset, error) {
+	return TranslateContext(context.Background(), dstDS, sourceDS, options, nil, nil)
+}
+
+// TranslateContext is Translate with progress reporting and cancellation.
+// progress may be nil. Once ctx is done GDAL is asked to abort, and the
+// returned error wraps ctx.Err().
+func TranslateContext(
+	ctx context.Context,
+	dstDS string,
+	sourceDS Dataset,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (Dataset, error) {
+	defer runtime.KeepAlive(sourceDS.ref)
 	if dstDS == "" {
 		dstDS = "MEM:::"
 		if !stringArrayContains(options, "-of") {
@@ -83,10 +134,22 @@ func Translate(dstDS string, sourceDS Dataset, options []string) (Dataset, error
 		defer C.free(unsafe.Pointer(opts[i]))
 	}
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
 	translateopts := C.GDALTranslateOptionsNew(
 		(**C.char)(unsafe.Pointer(&opts[0])),
 		(*C.GDALTranslateOptionsForBinary)(unsafe.Pointer(nil)))
+	if translateopts == nil {
+		return Dataset{}, failure("GDALTranslateOptionsNew", "invalid translate options")
+	}
 	defer C.GDALTranslateOptionsFree(translateopts)
+	if key, release := contextProgress(ctx, progress, data); key != nil {
+		defer release()
+		C.GDALTranslateOptionsSetProgress(translateopts, C.goGDALProgressFuncRegistryProxyB(), key)
+	}
 
 	var cerr C.int
 	cdstDS := C.CString(dstDS)
@@ -94,14 +157,35 @@ func Translate(dstDS string, sourceDS Dataset, options []string) (Dataset, error
 	ds := C.GDALTranslate(cdstDS,
 		sourceDS.cval,
 		translateopts, &cerr)
-	if cerr != 0 {
-		return Dataset{}, fmt.Errorf("translate failed with code %d", cerr)
+	if ds == nil || cerr != 0 {
+		err := contextError(ctx, failure("GDALTranslate", "translate failed"))
+		if ds != nil {
+			C.GDALClose(ds)
+		}
+		return Dataset{}, err
 	}
-	return Dataset{ds}, nil
-
+	return ownedDataset(ds), nil
 }
 
 func VectorTranslate(dstDS string, sourceDS []Dataset, options []string) (Dataset, error) {
+	return VectorTranslateContext(context.Background(), dstDS, sourceDS, options, nil, nil)
+}
+
+// VectorTranslateContext is VectorTranslate with progress reporting and
+// cancellation. progress may be nil. Once ctx is done GDAL is asked to
+// abort, and the returned error wraps ctx.Err().
+func VectorTranslateContext(
+	ctx context.Context,
+	dstDS string,
+	sourceDS []Dataset,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (Dataset, error) {
+	defer runtime.KeepAlive(sourceDS)
+	if len(sourceDS) == 0 {
+		return Dataset{}, fmt.Errorf("vector translate needs at least one source dataset")
+	}
 	if dstDS == "" {
 		dstDS = "MEM:::"
 		if !stringArrayContains(options, "-f") {
@@ -115,10 +199,22 @@ func VectorTranslate(dstDS string, sourceDS []Dataset, options []string) (Datase
 		defer C.free(unsafe.Pointer(opts[i]))
 	}
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
 	translateopts := C.GDALVectorTranslateOptionsNew(
 		(**C.char)(unsafe.Pointer(&opts[0])),
 		(*C.GDALVectorTranslateOptionsForBinary)(unsafe.Pointer(nil)))
+	if translateopts == nil {
+		return Dataset{}, failure("GDALVectorTranslateOptionsNew", "invalid vector translate options")
+	}
 	defer C.GDALVectorTranslateOptionsFree(translateopts)
+	if key, release := contextProgress(ctx, progress, data); key != nil {
+		defer release()
+		C.GDALVectorTranslateOptionsSetProgress(translateopts, C.goGDALProgressFuncRegistryProxyB(), key)
+	}
 
 	srcDS := make([]C.GDALDatasetH, len(sourceDS))
 	for i, ds := range sourceDS {
@@ -132,17 +228,35 @@ func VectorTranslate(dstDS string, sourceDS []Dataset, options []string) (Datase
 		C.int(len(sourceDS)),
 		(*C.GDALDatasetH)(unsafe.Pointer(&srcDS[0])),
 		translateopts, &cerr)
-	if cerr != 0 {
-		return Dataset{}, fmt.Errorf("vector translate failed with code %d", cerr)
+	if ds == nil || cerr != 0 {
+		err := contextError(ctx, failure("GDALVectorTranslate", "vector translate failed"))
+		if ds != nil {
+			C.GDALClose(ds)
+		}
+		return Dataset{}, err
 	}
-	return Dataset{ds}, nil
-
+	return ownedDataset(ds), nil
 }
 
 func Rasterize(dstDS string, sourceDS Dataset, options []string) (Dataset, error) {
+	return RasterizeContext(context.Background(), dstDS, sourceDS, options, nil, nil)
+}
+
+// RasterizeContext is Rasterize with progress reporting and cancellation.
+// progress may be nil. Once ctx is done GDAL is asked to abort, and the
+// returned error wraps ctx.Err().
+func RasterizeContext(
+	ctx context.Context,
+	dstDS string,
+	sourceDS Dataset,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (Dataset, error) {
+	defer runtime.KeepAlive(sourceDS.ref)
 	if dstDS == "" {
 		dstDS = "MEM:::"
-		if !stringArrayContains(options, "-f") {
+		if !stringArrayContains(options, "-of") {
 			options = append([]string{"-of", "MEM"}, options...)
 		}
 	}
@@ -153,10 +267,22 @@ func Rasterize(dstDS string, sourceDS Dataset, options []string) (Dataset, error
 		defer C.free(unsafe.Pointer(opts[i]))
 	}
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
 	rasterizeopts := C.GDALRasterizeOptionsNew(
 		(**C.char)(unsafe.Pointer(&opts[0])),
 		(*C.GDALRasterizeOptionsForBinary)(unsafe.Pointer(nil)))
+	if rasterizeopts == nil {
+		return Dataset{}, failure("GDALRasterizeOptionsNew", "invalid rasterize options")
+	}
 	defer C.GDALRasterizeOptionsFree(rasterizeopts)
+	if key, release := contextProgress(ctx, progress, data); key != nil {
+		defer release()
+		C.GDALRasterizeOptionsSetProgress(rasterizeopts, C.goGDALProgressFuncRegistryProxyB(), key)
+	}
 
 	var cerr C.int
 	cdstDS := C.CString(dstDS)
@@ -164,14 +290,84 @@ func Rasterize(dstDS string, sourceDS Dataset, options []string) (Dataset, error
 	ds := C.GDALRasterize(cdstDS, nil,
 		sourceDS.cval,
 		rasterizeopts, &cerr)
-	if cerr != 0 {
-		return Dataset{}, fmt.Errorf("rasterize failed with code %d", cerr)
+	if ds == nil || cerr != 0 {
+		err := contextError(ctx, failure("GDALRasterize", "rasterize failed"))
+		if ds != nil {
+			C.GDALClose(ds)
+		}
+		return Dataset{}, err
+	}
+	return ownedDataset(ds), nil
+}
+
+// contextProgress registers a progress callback that forwards to progress
+// and aborts once ctx is done. It returns a nil key when there is nothing
+// to report or cancel.
+func contextProgress(ctx context.Context, progress ProgressFunc, data interface{}) (unsafe.Pointer, func()) {
+	if progress == nil && ctx.Done() == nil {
+		return nil, func() {}
+	}
+	return registerProgress(func(complete float64, message string, data interface{}) int {
+		if ctx.Err() != nil {
+			return 0
+		}
+		if progress != nil {
+			return progress(complete, message, data)
+		}
+		return 1
+	}, data)
+}
+
+// contextError returns err, or ctx.Err() annotated with the GDAL error
+// message if the failure was caused by ctx being done.
+func contextError(ctx context.Context, err *Error) error {
+	if ctx.Err() == nil {
+		return err
+	}
+	if err.Msg != "" {
+		return fmt.Errorf("%w: %s", ctx.Err(), err.Msg)
+	}
+	return ctx.Err()
+}
+
+// WarpWithOptions is Warp with typed options.
+func WarpWithOptions(dstDS string, sourceDS []Dataset, options WarpOptions) (Dataset, error) {
+	args, err := options.Args()
+	if err != nil {
+		return Dataset{}, err
+	}
+	return Warp(dstDS, sourceDS, args)
+}
+
+// TranslateWithOptions is Translate with typed options.
+func TranslateWithOptions(dstDS string, sourceDS Dataset, options TranslateOptions) (Dataset, error) {
+	args, err := options.Args()
+	if err != nil {
+		return Dataset{}, err
+	}
+	return Translate(dstDS, sourceDS, args)
+}
+
+// VectorTranslateWithOptions is VectorTranslate with typed options.
+func VectorTranslateWithOptions(dstDS string, sourceDS []Dataset, options VectorTranslateOptions) (Dataset, error) {
+	args, err := options.Args()
+	if err != nil {
+		return Dataset{}, err
 	}
-	return Dataset{ds}, nil
+	return VectorTranslate(dstDS, sourceDS, args)
+}
 
+// RasterizeWithOptions is Rasterize with typed options.
+func RasterizeWithOptions(dstDS string, sourceDS Dataset, options RasterizeOptions) (Dataset, error) {
+	args, err := options.Args()
+	if err != nil {
+		return Dataset{}, err
+	}
+	return Rasterize(dstDS, sourceDS, args)
 }
 
 func Info(sourceDS Dataset, options []string) string {
+	defer runtime.KeepAlive(sourceDS.ref)
 	length := len(options)
 	opts := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -185,7 +381,320 @@ func Info(sourceDS Dataset, options []string) string {
 	defer C.GDALInfoOptionsFree(infoopts)
 
 	info := C.GDALInfo(sourceDS.cval, infoopts)
+	defer C.VSIFree(unsafe.Pointer(info))
 
 	return C.GoString(info)
 
 }
+
+// gdalbuildvrt
+
+// BuildVRT builds a VRT mosaic from either sourceDS or the datasets named in
+// srcDSNames. The VRT is kept in memory when dstDS is empty.
+func BuildVRT(dstDS string, sourceDS []Dataset, srcDSNames []string, options []string) (Dataset, error) {
+	defer runtime.KeepAlive(sourceDS)
+	if (len(sourceDS) == 0) == (len(srcDSNames) == 0) {
+		return Dataset{}, fmt.Errorf("buildvrt needs either source datasets or source dataset names")
+	}
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	buildvrtopts := C.GDALBuildVRTOptionsNew(
+		(**C.char)(unsafe.Pointer(&opts[0])),
+		(*C.GDALBuildVRTOptionsForBinary)(unsafe.Pointer(nil)))
+	if buildvrtopts == nil {
+		return Dataset{}, failure("GDALBuildVRTOptionsNew", "invalid buildvrt options")
+	}
+	defer C.GDALBuildVRTOptionsFree(buildvrtopts)
+
+	var srcDS *C.GDALDatasetH
+	var srcNames **C.char
+	if len(sourceDS) > 0 {
+		srcDSA := make([]C.GDALDatasetH, len(sourceDS))
+		for i, ds := range sourceDS {
+			srcDSA[i] = ds.cval
+		}
+		srcDS = (*C.GDALDatasetH)(unsafe.Pointer(&srcDSA[0]))
+	} else {
+		names := make([]*C.char, len(srcDSNames)+1)
+		for i := range srcDSNames {
+			names[i] = C.CString(srcDSNames[i])
+			defer C.free(unsafe.Pointer(names[i]))
+		}
+		names[len(srcDSNames)] = (*C.char)(unsafe.Pointer(nil))
+		srcNames = (**C.char)(unsafe.Pointer(&names[0]))
+	}
+
+	var cerr C.int
+	cdstDS := C.CString(dstDS)
+	defer C.free(unsafe.Pointer(cdstDS))
+	ds := C.GDALBuildVRT(cdstDS,
+		C.int(len(sourceDS)+len(srcDSNames)),
+		srcDS, srcNames,
+		buildvrtopts, &cerr)
+	if ds == nil || cerr != 0 {
+		err := failure("GDALBuildVRT", "buildvrt failed")
+		if ds != nil {
+			C.GDALClose(ds)
+		}
+		return Dataset{}, err
+	}
+	return ownedDataset(ds), nil
+}
+
+// gdaldem
+
+var demProcessingModes = []string{
+	"hillshade", "slope", "aspect", "color-relief", "TRI", "TPI", "roughness",
+}
+
+// DEMProcessing runs one of the gdaldem processing modes ("hillshade",
+// "slope", "aspect", "color-relief", "TRI", "TPI" or "roughness") on
+// sourceDS. colorFilename is only used, and required, by "color-relief".
+func DEMProcessing(dstDS string, sourceDS Dataset, processing string, colorFilename string, options []string) (Dataset, error) {
+	defer runtime.KeepAlive(sourceDS.ref)
+	if !stringArrayContains(demProcessingModes, processing) {
+		return Dataset{}, fmt.Errorf("unknown dem processing mode %q", processing)
+	}
+	if (processing == "color-relief") != (colorFilename != "") {
+		return Dataset{}, fmt.Errorf("a color file is required by, and only by, color-relief")
+	}
+	if dstDS == "" {
+		dstDS = "MEM:::"
+		if !stringArrayContains(options, "-of") {
+			options = append([]string{"-of", "MEM"}, options...)
+		}
+	}
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	demopts := C.GDALDEMProcessingOptionsNew(
+		(**C.char)(unsafe.Pointer(&opts[0])),
+		(*C.GDALDEMProcessingOptionsForBinary)(unsafe.Pointer(nil)))
+	if demopts == nil {
+		return Dataset{}, failure("GDALDEMProcessingOptionsNew", "invalid dem processing options")
+	}
+	defer C.GDALDEMProcessingOptionsFree(demopts)
+
+	cProcessing := C.CString(processing)
+	defer C.free(unsafe.Pointer(cProcessing))
+	var cColorFilename *C.char
+	if colorFilename != "" {
+		cColorFilename = C.CString(colorFilename)
+		defer C.free(unsafe.Pointer(cColorFilename))
+	}
+
+	var cerr C.int
+	cdstDS := C.CString(dstDS)
+	defer C.free(unsafe.Pointer(cdstDS))
+	ds := C.GDALDEMProcessing(cdstDS,
+		sourceDS.cval,
+		cProcessing, cColorFilename,
+		demopts, &cerr)
+	if ds == nil || cerr != 0 {
+		err := failure("GDALDEMProcessing", "dem processing failed")
+		if ds != nil {
+			C.GDALClose(ds)
+		}
+		return Dataset{}, err
+	}
+	return ownedDataset(ds), nil
+}
+
+// nearblack
+
+// NearBlack converts nearly black/white borders of sourceDS to exactly
+// black/white.
+func NearBlack(dstDS string, sourceDS Dataset, options []string) (Dataset, error) {
+	defer runtime.KeepAlive(sourceDS.ref)
+	if dstDS == "" {
+		dstDS = "MEM:::"
+		if !stringArrayContains(options, "-of") {
+			options = append([]string{"-of", "MEM"}, options...)
+		}
+	}
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	nearblackopts := C.GDALNearblackOptionsNew(
+		(**C.char)(unsafe.Pointer(&opts[0])),
+		(*C.GDALNearblackOptionsForBinary)(unsafe.Pointer(nil)))
+	if nearblackopts == nil {
+		return Dataset{}, failure("GDALNearblackOptionsNew", "invalid nearblack options")
+	}
+	defer C.GDALNearblackOptionsFree(nearblackopts)
+
+	var cerr C.int
+	cdstDS := C.CString(dstDS)
+	defer C.free(unsafe.Pointer(cdstDS))
+	ds := C.GDALNearblack(cdstDS, nil,
+		sourceDS.cval,
+		nearblackopts, &cerr)
+	if ds == nil || cerr != 0 {
+		err := failure("GDALNearblack", "nearblack failed")
+		if ds != nil {
+			C.GDALClose(ds)
+		}
+		return Dataset{}, err
+	}
+	return ownedDataset(ds), nil
+}
+
+// gdal_grid
+
+// Grid interpolates the points of the vector dataset sourceDS onto a
+// regular grid.
+func Grid(dstDS string, sourceDS Dataset, options []string) (Dataset, error) {
+	defer runtime.KeepAlive(sourceDS.ref)
+	if dstDS == "" {
+		dstDS = "MEM:::"
+		if !stringArrayContains(options, "-of") {
+			options = append([]string{"-of", "MEM"}, options...)
+		}
+	}
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	gridopts := C.GDALGridOptionsNew(
+		(**C.char)(unsafe.Pointer(&opts[0])),
+		(*C.GDALGridOptionsForBinary)(unsafe.Pointer(nil)))
+	if gridopts == nil {
+		return Dataset{}, failure("GDALGridOptionsNew", "invalid grid options")
+	}
+	defer C.GDALGridOptionsFree(gridopts)
+
+	var cerr C.int
+	cdstDS := C.CString(dstDS)
+	defer C.free(unsafe.Pointer(cdstDS))
+	ds := C.GDALGrid(cdstDS,
+		sourceDS.cval,
+		gridopts, &cerr)
+	if ds == nil || cerr != 0 {
+		err := failure("GDALGrid", "grid failed")
+		if ds != nil {
+			C.GDALClose(ds)
+		}
+		return Dataset{}, err
+	}
+	return ownedDataset(ds), nil
+}
+
+// gdalmdimtranslate
+
+// MultiDimTranslate converts multidimensional datasets between formats,
+// optionally subsetting arrays.
+func MultiDimTranslate(dstDS string, sourceDS []Dataset, options []string) (Dataset, error) {
+	defer runtime.KeepAlive(sourceDS)
+	if len(sourceDS) == 0 {
+		return Dataset{}, fmt.Errorf("multidim translate needs at least one source dataset")
+	}
+	if dstDS == "" {
+		dstDS = "MEM:::"
+		if !stringArrayContains(options, "-of") {
+			options = append([]string{"-of", "MEM"}, options...)
+		}
+	}
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	mdimopts := C.GDALMultiDimTranslateOptionsNew(
+		(**C.char)(unsafe.Pointer(&opts[0])),
+		(*C.GDALMultiDimTranslateOptionsForBinary)(unsafe.Pointer(nil)))
+	if mdimopts == nil {
+		return Dataset{}, failure("GDALMultiDimTranslateOptionsNew", "invalid multidim translate options")
+	}
+	defer C.GDALMultiDimTranslateOptionsFree(mdimopts)
+
+	srcDS := make([]C.GDALDatasetH, len(sourceDS))
+	for i, ds := range sourceDS {
+		srcDS[i] = ds.cval
+	}
+
+	var cerr C.int
+	cdstDS := C.CString(dstDS)
+	defer C.free(unsafe.Pointer(cdstDS))
+	ds := C.GDALMultiDimTranslate(cdstDS, nil,
+		C.int(len(sourceDS)),
+		(*C.GDALDatasetH)(unsafe.Pointer(&srcDS[0])),
+		mdimopts, &cerr)
+	if ds == nil || cerr != 0 {
+		err := failure("GDALMultiDimTranslate", "multidim translate failed")
+		if ds != nil {
+			C.GDALClose(ds)
+		}
+		return Dataset{}, err
+	}
+	return ownedDataset(ds), nil
+}
+
+// ogrinfo
+
+// VectorInfo returns the ogrinfo report for sourceDS. It requires GDAL 3.7
+// or later.
+func VectorInfo(sourceDS Dataset, options []string) (string, error) {
+	defer runtime.KeepAlive(sourceDS.ref)
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	info := C.goGDALVectorInfo(sourceDS.cval, (**C.char)(unsafe.Pointer(&opts[0])))
+	if info == nil {
+		return "", failure("GDALVectorInfo", "vector info failed")
+	}
+	defer C.VSIFree(unsafe.Pointer(info))
+
+	return C.GoString(info), nil
+}
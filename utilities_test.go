@@ -1,7 +1,12 @@
 package gdal
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
+
+	"github.com/airmap/gdal/ogr"
 )
 
 func TestVectorTranslate(t *testing.T) {
@@ -83,3 +88,219 @@ func TestTranslate(t *testing.T) {
 	}
 	dstDS.Close()
 }
+
+func TestBuildVRT(t *testing.T) {
+	srcDS, err := Open("testdata/tiles.gpkg", ReadOnly)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer srcDS.Close()
+
+	dstDS, err := BuildVRT("", []Dataset{srcDS}, nil, []string{"-b", "1"})
+	if err != nil {
+		t.Fatalf("BuildVRT: %v", err)
+	}
+	defer dstDS.Close()
+	if dstDS.RasterCount() != 1 {
+		t.Errorf("got %d bands, want 1", dstDS.RasterCount())
+	}
+
+	if _, err := BuildVRT("", nil, nil, nil); err == nil {
+		t.Errorf("BuildVRT without sources should fail")
+	}
+}
+
+func TestDEMProcessing(t *testing.T) {
+	srcDS, err := Open("testdata/tiles.gpkg", ReadOnly)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer srcDS.Close()
+
+	dstDS, err := DEMProcessing("", srcDS, "hillshade", "", []string{"-b", "1"})
+	if err != nil {
+		t.Fatalf("DEMProcessing: %v", err)
+	}
+	defer dstDS.Close()
+	if dstDS.RasterXSize() != srcDS.RasterXSize() {
+		t.Errorf("got width %d, want %d", dstDS.RasterXSize(), srcDS.RasterXSize())
+	}
+
+	if _, err := DEMProcessing("", srcDS, "color-relief", "", nil); err == nil {
+		t.Errorf("color-relief without a color file should fail")
+	}
+}
+
+func TestNearBlack(t *testing.T) {
+	srcDS, err := Open("testdata/tiles.gpkg", ReadOnly)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer srcDS.Close()
+
+	dstDS, err := NearBlack("", srcDS, []string{"-near", "10"})
+	if err != nil {
+		t.Fatalf("NearBlack: %v", err)
+	}
+	defer dstDS.Close()
+	if dstDS.RasterXSize() != srcDS.RasterXSize() || dstDS.RasterYSize() != srcDS.RasterYSize() {
+		t.Errorf("got %dx%d, want %dx%d", dstDS.RasterXSize(), dstDS.RasterYSize(), srcDS.RasterXSize(), srcDS.RasterYSize())
+	}
+
+	if _, err := NearBlack("", srcDS, []string{"-nosuchoption"}); err == nil {
+		t.Errorf("NearBlack with an invalid option should fail")
+	}
+}
+
+// pointsDataset returns an in-memory vector dataset with a "points" layer
+// of three points with a "z" field.
+func pointsDataset(t *testing.T) Dataset {
+	driver, err := GetDriverByName("Memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("", 0, 0, 0, Unknown, nil)
+	layer, err := ds.CreateLayer("points", ogr.SpatialReference{}, ogr.GT_Point, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	field := ogr.CreateFieldDefinition("z", ogr.FT_Real)
+	defer field.Destroy()
+	if err := layer.CreateField(field, false); err != nil {
+		t.Fatal(err)
+	}
+	for i, wkt := range []string{"POINT (0 0)", "POINT (10 0)", "POINT (5 10)"} {
+		geom, err := ogr.CreateFromWKT(wkt, ogr.SpatialReference{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		feature := layer.Definition().Create()
+		feature.SetFieldFloat64(0, float64(10*i))
+		if err := feature.SetGeometry(geom); err != nil {
+			t.Fatal(err)
+		}
+		if err := layer.Create(feature); err != nil {
+			t.Fatal(err)
+		}
+		feature.Destroy()
+		geom.Destroy()
+	}
+	return ds
+}
+
+func TestGrid(t *testing.T) {
+	srcDS := pointsDataset(t)
+	defer srcDS.Close()
+
+	dstDS, err := Grid("", srcDS, []string{"-zfield", "z", "-outsize", "8", "4", "-a", "nearest"})
+	if err != nil {
+		t.Fatalf("Grid: %v", err)
+	}
+	defer dstDS.Close()
+	if dstDS.RasterXSize() != 8 || dstDS.RasterYSize() != 4 || dstDS.RasterCount() != 1 {
+		t.Errorf("got %d bands of %dx%d, want 1 of 8x4", dstDS.RasterCount(), dstDS.RasterXSize(), dstDS.RasterYSize())
+	}
+
+	if _, err := Grid("", srcDS, []string{"-a", "nosuchalgorithm"}); err == nil {
+		t.Errorf("Grid with an unknown algorithm should fail")
+	}
+}
+
+func TestMultiDimTranslate(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srcDS, err := driver.CreateMultiDimensional("", nil, nil)
+	if err != nil {
+		t.Fatalf("CreateMultiDimensional: %v", err)
+	}
+	defer srcDS.Close()
+	root, err := srcDS.RootGroup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Release()
+	dim, err := root.CreateDimension("x", "HORIZONTAL_X", "", 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dim.Release()
+	dataType := NewExtendedDataType(Byte)
+	defer dataType.Release()
+	array, err := root.CreateMDArray("values", []Dimension{dim}, dataType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer array.Release()
+
+	dstDS, err := MultiDimTranslate("", []Dataset{srcDS}, nil)
+	if err != nil {
+		t.Fatalf("MultiDimTranslate: %v", err)
+	}
+	defer dstDS.Close()
+	dstRoot, err := dstDS.RootGroup()
+	if err != nil {
+		t.Fatalf("RootGroup: %v", err)
+	}
+	defer dstRoot.Release()
+	if names := dstRoot.MDArrayNames(nil); len(names) != 1 || names[0] != "values" {
+		t.Errorf("got arrays %v, want [values]", names)
+	}
+
+	if _, err := MultiDimTranslate("", nil, nil); err == nil {
+		t.Errorf("MultiDimTranslate without sources should fail")
+	}
+	if _, err := MultiDimTranslate("", []Dataset{srcDS}, []string{"-array", "nosucharray"}); err == nil {
+		t.Errorf("MultiDimTranslate of an unknown array should fail")
+	}
+}
+
+func TestVectorInfo(t *testing.T) {
+	if VERSION_NUM < 3070000 {
+		t.Skip("VectorInfo requires GDAL 3.7")
+	}
+	srcDS := pointsDataset(t)
+	defer srcDS.Close()
+
+	info, err := VectorInfo(srcDS, []string{"-al", "-so"})
+	if err != nil {
+		t.Fatalf("VectorInfo: %v", err)
+	}
+	if !strings.Contains(info, "Layer name: points") || !strings.Contains(info, "Feature Count: 3") {
+		t.Errorf("unexpected report:\n%s", info)
+	}
+
+	if _, err := VectorInfo(srcDS, []string{"-nosuchoption"}); err == nil {
+		t.Errorf("VectorInfo with an invalid option should fail")
+	}
+}
+
+func TestWarpContext(t *testing.T) {
+	srcDS, err := Open("testdata/tiles.gpkg", ReadOnly)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer srcDS.Close()
+
+	var calls int
+	progress := func(complete float64, message string, data interface{}) int {
+		calls++
+		return 1
+	}
+	dstDS, err := WarpContext(context.Background(), "", []Dataset{srcDS}, []string{"-t_srs", "epsg:4326"}, progress, nil)
+	if err != nil {
+		t.Fatalf("WarpContext: %v", err)
+	}
+	dstDS.Close()
+	if calls == 0 {
+		t.Errorf("progress was never called")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = WarpContext(ctx, "", []Dataset{srcDS}, []string{"-t_srs", "epsg:4326"}, nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,188 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"github.com/airmap/gdal/internal/bridge"
+	"github.com/airmap/gdal/ogr"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Vector layers                                                   */
+/* -------------------------------------------------------------------- */
+
+// Layers returned by these methods are owned by the dataset and become
+// invalid once it is closed, except the result sets of ExecuteSQL which
+// must be released with ReleaseResultSet.
+
+// ogrCall runs fn, a GDAL function returning an OGRErr, like cplCall.
+func ogrCall(op string, fn func() C.OGRErr) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	if code := fn(); code != C.OGRERR_NONE {
+		return failure(op, fmt.Sprintf("%s failed with OGR error %d", op, int(code)))
+	}
+	return nil
+}
+
+// LayerCount returns the number of vector layers of the dataset.
+func (dataset Dataset) LayerCount() int {
+	defer runtime.KeepAlive(dataset.ref)
+	return int(C.GDALDatasetGetLayerCount(dataset.cval))
+}
+
+// layer wraps a layer of the dataset, keeping the dataset reachable while
+// the layer is when handles are tracked.
+func (dataset Dataset) layer(h C.OGRLayerH) ogr.Layer {
+	return bridge.Layer(unsafe.Pointer(h), dataset.ref).(ogr.Layer)
+}
+
+// Layer returns the i-th vector layer of the dataset, from 0.
+func (dataset Dataset) Layer(i int) (ogr.Layer, error) {
+	defer runtime.KeepAlive(dataset.ref)
+	if i < 0 || i >= dataset.LayerCount() {
+		return ogr.Layer{}, fmt.Errorf("layer %d out of range [0, %d)", i, dataset.LayerCount())
+	}
+	layer := C.GDALDatasetGetLayer(dataset.cval, C.int(i))
+	return dataset.layer(layer), nil
+}
+
+// LayerByName returns the vector layer of the dataset with the given name.
+func (dataset Dataset) LayerByName(name string) (ogr.Layer, error) {
+	defer runtime.KeepAlive(dataset.ref)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	layer := C.GDALDatasetGetLayerByName(dataset.cval, cName)
+	if layer == nil {
+		return ogr.Layer{}, failure("GDALDatasetGetLayerByName", fmt.Sprintf("no layer named '%s'", name))
+	}
+	return dataset.layer(layer), nil
+}
+
+// CreateLayer creates a vector layer in a dataset opened for update or
+// created by a vector capable driver. srs may be the zero value for a
+// layer without spatial reference.
+func (dataset Dataset) CreateLayer(
+	name string,
+	srs ogr.SpatialReference,
+	geomType ogr.GeometryType,
+	options []string,
+) (ogr.Layer, error) {
+	defer runtime.KeepAlive(dataset.ref)
+	defer runtime.KeepAlive(srs)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cOptions, freeOptions := cStringList(options)
+	defer freeOptions()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	layer := C.GDALDatasetCreateLayer(
+		dataset.cval,
+		cName,
+		C.OGRSpatialReferenceH(srs.Handle()),
+		C.OGRwkbGeometryType(geomType),
+		cOptions,
+	)
+	if layer == nil {
+		return ogr.Layer{}, failure("GDALDatasetCreateLayer", fmt.Sprintf("cannot create layer '%s'", name))
+	}
+	return dataset.layer(layer), nil
+}
+
+// DeleteLayer deletes the i-th vector layer of the dataset, from 0.
+func (dataset Dataset) DeleteLayer(i int) error {
+	defer runtime.KeepAlive(dataset.ref)
+	return ogrCall("GDALDatasetDeleteLayer", func() C.OGRErr {
+		return C.GDALDatasetDeleteLayer(dataset.cval, C.int(i))
+	})
+}
+
+// ExecuteSQL runs an SQL statement against the dataset. filter, which may
+// be the zero value, is a spatial filter for the result, and dialect is
+// "", "OGRSQL", "SQLITE" or a driver specific dialect. Statements with a
+// result set return a layer to be released with ReleaseResultSet, others
+// return a null layer.
+func (dataset Dataset) ExecuteSQL(sql string, filter ogr.Geometry, dialect string) (ogr.Layer, error) {
+	defer runtime.KeepAlive(dataset.ref)
+	defer runtime.KeepAlive(filter)
+	cSQL := C.CString(sql)
+	defer C.free(unsafe.Pointer(cSQL))
+	var cDialect *C.char
+	if dialect != "" {
+		cDialect = C.CString(dialect)
+		defer C.free(unsafe.Pointer(cDialect))
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	layer := C.GDALDatasetExecuteSQL(dataset.cval, cSQL, C.OGRGeometryH(filter.Handle()), cDialect)
+	if layer == nil && C.CPLGetLastErrorType() >= C.CE_Failure {
+		return ogr.Layer{}, failure("GDALDatasetExecuteSQL", "SQL statement failed")
+	}
+	return dataset.layer(layer), nil
+}
+
+// ReleaseResultSet releases a layer returned by ExecuteSQL. Null layers
+// are ignored.
+func (dataset Dataset) ReleaseResultSet(layer ogr.Layer) {
+	defer runtime.KeepAlive(dataset.ref)
+	defer runtime.KeepAlive(layer)
+	if layer.IsNull() {
+		return
+	}
+	C.GDALDatasetReleaseResultSet(dataset.cval, C.OGRLayerH(layer.Handle()))
+}
+
+// StartTransaction starts a transaction on the dataset, for the drivers
+// supporting them. If force is true, drivers emulating transactions, by
+// copying the dataset for instance, are accepted too.
+func (dataset Dataset) StartTransaction(force bool) error {
+	defer runtime.KeepAlive(dataset.ref)
+	cForce := C.int(0)
+	if force {
+		cForce = 1
+	}
+	return ogrCall("GDALDatasetStartTransaction", func() C.OGRErr {
+		return C.GDALDatasetStartTransaction(dataset.cval, cForce)
+	})
+}
+
+// CommitTransaction commits the transaction started by StartTransaction.
+func (dataset Dataset) CommitTransaction() error {
+	defer runtime.KeepAlive(dataset.ref)
+	return ogrCall("GDALDatasetCommitTransaction", func() C.OGRErr {
+		return C.GDALDatasetCommitTransaction(dataset.cval)
+	})
+}
+
+// RollbackTransaction cancels the changes made since StartTransaction.
+func (dataset Dataset) RollbackTransaction() error {
+	defer runtime.KeepAlive(dataset.ref)
+	return ogrCall("GDALDatasetRollbackTransaction", func() C.OGRErr {
+		return C.GDALDatasetRollbackTransaction(dataset.cval)
+	})
+}
@@ -0,0 +1,142 @@
+package gdal
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/airmap/gdal/ogr"
+)
+
+func TestDatasetLayers(t *testing.T) {
+	driver, err := GetDriverByName("GPKG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const filename = "/vsimem/vector_test.gpkg"
+	ds := driver.Create(filename, 0, 0, 0, Unknown, nil)
+	defer VSIUnlink(filename)
+	defer ds.Close()
+
+	if n := ds.LayerCount(); n != 0 {
+		t.Fatalf("LayerCount = %d, want 0", n)
+	}
+	srs := ogr.CreateSpatialReference("")
+	if err := srs.FromEPSG(4326); err != nil {
+		t.Fatal(err)
+	}
+	defer srs.Destroy()
+	points, err := ds.CreateLayer("points", srs, ogr.GT_Point, nil)
+	if err != nil {
+		t.Fatalf("CreateLayer: %v", err)
+	}
+	if err := points.CreateField(ogr.CreateFieldDefinition("value", ogr.FT_Integer), false); err != nil {
+		t.Fatalf("CreateField: %v", err)
+	}
+	if _, err := ds.CreateLayer("lines", ogr.SpatialReference{}, ogr.GT_LineString, nil); err != nil {
+		t.Fatalf("CreateLayer: %v", err)
+	}
+	if n := ds.LayerCount(); n != 2 {
+		t.Fatalf("LayerCount = %d, want 2", n)
+	}
+
+	addPoint := func(value int) {
+		feature := points.Definition().Create()
+		defer feature.Destroy()
+		feature.SetFieldInteger(0, value)
+		geom, err := ogr.CreateFromWKT("POINT (1 2)", srs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer geom.Destroy()
+		if err := feature.SetGeometry(geom); err != nil {
+			t.Fatal(err)
+		}
+		if err := points.Create(feature); err != nil {
+			t.Fatalf("Create feature: %v", err)
+		}
+	}
+	count := func() int {
+		result, err := ds.ExecuteSQL("SELECT COUNT(*) FROM points", ogr.Geometry{}, "")
+		if err != nil {
+			t.Fatalf("ExecuteSQL: %v", err)
+		}
+		defer ds.ReleaseResultSet(result)
+		feature := result.NextFeature()
+		if feature == nil {
+			t.Fatal("no result row")
+		}
+		defer feature.Destroy()
+		return feature.FieldAsInteger(0)
+	}
+
+	if err := ds.StartTransaction(false); err != nil {
+		t.Fatalf("StartTransaction: %v", err)
+	}
+	addPoint(1)
+	if err := ds.CommitTransaction(); err != nil {
+		t.Fatalf("CommitTransaction: %v", err)
+	}
+	if err := ds.StartTransaction(false); err != nil {
+		t.Fatalf("StartTransaction: %v", err)
+	}
+	addPoint(2)
+	if err := ds.RollbackTransaction(); err != nil {
+		t.Fatalf("RollbackTransaction: %v", err)
+	}
+	if n := count(); n != 1 {
+		t.Errorf("%d features after rollback, want 1", n)
+	}
+
+	if _, err := ds.ExecuteSQL("SELECT * FROM missing", ogr.Geometry{}, ""); err == nil {
+		t.Error("ExecuteSQL on a missing table succeeded")
+	}
+	layer, err := ds.LayerByName("lines")
+	if err != nil {
+		t.Fatalf("LayerByName: %v", err)
+	}
+	if layer.Name() != "lines" {
+		t.Errorf("LayerByName returned %q", layer.Name())
+	}
+	if _, err := ds.LayerByName("missing"); err == nil {
+		t.Error("LayerByName of a missing layer succeeded")
+	}
+	if err := ds.DeleteLayer(1); err != nil {
+		t.Fatalf("DeleteLayer: %v", err)
+	}
+	layer, err = ds.Layer(0)
+	if err != nil {
+		t.Fatalf("Layer: %v", err)
+	}
+	if ds.LayerCount() != 1 || layer.Name() != "points" {
+		t.Errorf("after DeleteLayer: %d layers, first %q", ds.LayerCount(), layer.Name())
+	}
+	if _, err := ds.Layer(1); err == nil {
+		t.Error("Layer out of range succeeded")
+	}
+}
+
+func TestTrackedLayerKeepsDataset(t *testing.T) {
+	TrackHandles(true, false)
+	defer TrackHandles(false, false)
+
+	layer := func() ogr.Layer {
+		driver, err := GetDriverByName("Memory")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ds := driver.Create("", 0, 0, 0, Unknown, nil)
+		layer, err := ds.CreateLayer("kept", ogr.SpatialReference{}, ogr.GT_Point, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return layer
+	}()
+	// The dataset is only reachable through the layer and must not be
+	// finalized while the layer is.
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+	}
+	if name := layer.Name(); name != "kept" {
+		t.Errorf("layer name = %q after collection", name)
+	}
+}
@@ -0,0 +1,552 @@
+// Package vrt builds GDAL virtual datasets (VRT) from Go.
+//
+// A Dataset mirrors the VRT XML format: it can be built with New and the
+// Add methods, read from and written to XML, and opened as a gdal.Dataset
+// usable anywhere a regular dataset is, for instance by gdal.Translate or
+// gdal.Warp.
+//
+//	ds := vrt.New(1024, 1024)
+//	band := ds.AddBand(gdal.Byte).SetNoData(0)
+//	band.AddSimpleSource("a.tif", 1, gdal.Window{}, gdal.Window{XSize: 512, YSize: 1024})
+//	band.AddSimpleSource("b.tif", 1, gdal.Window{}, gdal.Window{XOff: 512, XSize: 512, YSize: 1024})
+//	mosaic, err := ds.Open()
+package vrt
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/airmap/gdal"
+)
+
+// Dataset is a VRT dataset.
+type Dataset struct {
+	XMLName      xml.Name      `xml:"VRTDataset"`
+	XSize        int           `xml:"rasterXSize,attr"`
+	YSize        int           `xml:"rasterYSize,attr"`
+	SRS          *SRS          `xml:"SRS,omitempty"`
+	GeoTransform *GeoTransform `xml:"GeoTransform,omitempty"`
+	Metadata     []Metadata    `xml:"Metadata,omitempty"`
+	Bands        []*Band       `xml:"VRTRasterBand"`
+	// Other holds the elements this package doesn't model, such as GCPs or
+	// mask bands, so they survive a round trip.
+	Other []RawElement `xml:",any"`
+}
+
+// SRS is the spatial reference system of a dataset, as WKT.
+type SRS struct {
+	DataAxisToSRSAxisMapping string `xml:"dataAxisToSRSAxisMapping,attr,omitempty"`
+	WKT                      string `xml:",chardata"`
+}
+
+// GeoTransform is a gdal.GeoTransform serialized as VRT does.
+type GeoTransform gdal.GeoTransform
+
+// MarshalText implements encoding.TextMarshaler.
+func (gt GeoTransform) MarshalText() ([]byte, error) {
+	return []byte(formatFloats(gt[:], ", ")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (gt *GeoTransform) UnmarshalText(text []byte) error {
+	values, err := parseFloats(string(text), ",")
+	if err != nil {
+		return fmt.Errorf("invalid GeoTransform: %v", err)
+	}
+	if len(values) != len(gt) {
+		return fmt.Errorf("invalid GeoTransform: got %d coefficients, want %d", len(values), len(gt))
+	}
+	copy(gt[:], values)
+	return nil
+}
+
+// Metadata is a metadata domain of a dataset or band.
+type Metadata struct {
+	Domain string         `xml:"domain,attr,omitempty"`
+	Items  []MetadataItem `xml:"MDI"`
+}
+
+// MetadataItem is a key/value pair of a metadata domain.
+type MetadataItem struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// RawElement is an XML element kept as is.
+type RawElement struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   []byte     `xml:",innerxml"`
+}
+
+// New returns an empty dataset of xSize by ySize pixels.
+func New(xSize, ySize int) *Dataset {
+	return &Dataset{XSize: xSize, YSize: ySize}
+}
+
+// Parse reads a dataset from its XML description.
+func Parse(data []byte) (*Dataset, error) {
+	ds := &Dataset{}
+	if err := xml.Unmarshal(data, ds); err != nil {
+		return nil, fmt.Errorf("invalid VRT: %v", err)
+	}
+	return ds, nil
+}
+
+// XML returns the XML description of the dataset.
+func (ds *Dataset) XML() ([]byte, error) {
+	return xml.MarshalIndent(ds, "", "  ")
+}
+
+// Open opens the dataset read-only. Relative source filenames are
+// resolved from the current directory.
+func (ds *Dataset) Open() (gdal.Dataset, error) {
+	data, err := ds.XML()
+	if err != nil {
+		return gdal.Dataset{}, err
+	}
+	return gdal.Open(string(data), gdal.ReadOnly)
+}
+
+// SetSRS sets the spatial reference system as WKT.
+func (ds *Dataset) SetSRS(wkt string) *Dataset {
+	ds.SRS = &SRS{WKT: wkt}
+	return ds
+}
+
+// SetGeoTransform sets the affine transformation coefficients.
+func (ds *Dataset) SetGeoTransform(gt gdal.GeoTransform) *Dataset {
+	transform := GeoTransform(gt)
+	ds.GeoTransform = &transform
+	return ds
+}
+
+// SetMetadataItem sets an item of a metadata domain, "" being the
+// default one.
+func (ds *Dataset) SetMetadataItem(key, value, domain string) *Dataset {
+	ds.Metadata = setMetadataItem(ds.Metadata, key, value, domain)
+	return ds
+}
+
+// AddBand appends a band of the given type and returns it.
+func (ds *Dataset) AddBand(dataType gdal.DataType) *Band {
+	band := &Band{DataType: dataType.Name(), Band: len(ds.Bands) + 1}
+	ds.Bands = append(ds.Bands, band)
+	return band
+}
+
+// AddPixelFuncBand appends a derived band whose pixels are computed by the
+// pixel function registered under function, from the values of its
+// sources, and returns it. Go functions can be registered with
+// gdal.AddDerivedBandPixelFunc.
+func (ds *Dataset) AddPixelFuncBand(dataType gdal.DataType, function string) *Band {
+	band := ds.AddBand(dataType)
+	band.SubClass = DerivedBand
+	band.PixelFunctionType = function
+	return band
+}
+
+// Band subclasses.
+const (
+	SourcedBand = "VRTSourcedRasterBand"
+	DerivedBand = "VRTDerivedRasterBand"
+)
+
+// Band is a band of a VRT dataset.
+type Band struct {
+	DataType    string     `xml:"dataType,attr"`
+	Band        int        `xml:"band,attr"`
+	SubClass    string     `xml:"subClass,attr,omitempty"`
+	Description string     `xml:"Description,omitempty"`
+	Metadata    []Metadata `xml:"Metadata,omitempty"`
+	NoData      *float64   `xml:"NoDataValue"`
+	Offset      *float64   `xml:"Offset"`
+	Scale       *float64   `xml:"Scale"`
+	ColorInterp string     `xml:"ColorInterp,omitempty"`
+
+	// Derived bands only.
+	PixelFunctionType      string      `xml:"PixelFunctionType,omitempty"`
+	PixelFunctionLanguage  string      `xml:"PixelFunctionLanguage,omitempty"`
+	PixelFunctionArguments *RawElement `xml:"PixelFunctionArguments"`
+	SourceTransferType     string      `xml:"SourceTransferType,omitempty"`
+
+	// Sources are the sources of the band, later ones drawn over earlier
+	// ones.
+	Sources []*Source `xml:"-"`
+	// Other holds the elements this package doesn't model, such as color
+	// tables or overviews, so they survive a round trip.
+	Other []RawElement `xml:",any"`
+}
+
+// SetNoData sets the nodata value of the band.
+func (band *Band) SetNoData(value float64) *Band {
+	band.NoData = &value
+	return band
+}
+
+// SetOffset sets the offset of the band.
+func (band *Band) SetOffset(offset float64) *Band {
+	band.Offset = &offset
+	return band
+}
+
+// SetScale sets the scale of the band.
+func (band *Band) SetScale(scale float64) *Band {
+	band.Scale = &scale
+	return band
+}
+
+// SetMetadataItem sets an item of a metadata domain, "" being the
+// default one.
+func (band *Band) SetMetadataItem(key, value, domain string) *Band {
+	band.Metadata = setMetadataItem(band.Metadata, key, value, domain)
+	return band
+}
+
+// AddSimpleSource reads srcWin of band srcBand of filename into dstWin of
+// the band, resampling if their sizes differ. A zero window stands for the
+// whole source, or the whole band.
+func (band *Band) AddSimpleSource(filename string, srcBand int, srcWin, dstWin gdal.Window) *Source {
+	return band.addSource(SimpleSource, filename, srcBand, srcWin, dstWin)
+}
+
+// AddComplexSource is AddSimpleSource for a source whose values can be
+// filtered through a nodata value, a scale and offset or a lookup table,
+// set on the returned source.
+func (band *Band) AddComplexSource(filename string, srcBand int, srcWin, dstWin gdal.Window) *Source {
+	return band.addSource(ComplexSource, filename, srcBand, srcWin, dstWin)
+}
+
+// AddAveragedSource is AddComplexSource for a source downsampled by
+// averaging rather than nearest neighbour.
+func (band *Band) AddAveragedSource(filename string, srcBand int, srcWin, dstWin gdal.Window) *Source {
+	return band.addSource(AveragedSource, filename, srcBand, srcWin, dstWin)
+}
+
+// AddKernelFilteredSource is AddComplexSource for a source convolved with
+// kernel.
+func (band *Band) AddKernelFilteredSource(filename string, srcBand int, srcWin, dstWin gdal.Window, kernel Kernel) *Source {
+	source := band.addSource(KernelFilteredSource, filename, srcBand, srcWin, dstWin)
+	source.Kernel = &kernel
+	return source
+}
+
+func (band *Band) addSource(kind SourceKind, filename string, srcBand int, srcWin, dstWin gdal.Window) *Source {
+	source := &Source{
+		Kind:     kind,
+		Filename: Filename{Name: filename},
+		Band:     strconv.Itoa(srcBand),
+		SrcRect:  rectFor(srcWin),
+		DstRect:  rectFor(dstWin),
+	}
+	band.Sources = append(band.Sources, source)
+	return source
+}
+
+// MarshalXML implements xml.Marshaler.
+func (band *Band) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain Band
+	return e.EncodeElement(struct {
+		*plain
+		Sources []*Source `xml:"Source"`
+	}{(*plain)(band), band.Sources}, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (band *Band) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain Band
+	if err := d.DecodeElement((*plain)(band), &start); err != nil {
+		return err
+	}
+	var other []RawElement
+	for _, elem := range band.Other {
+		kind := SourceKind(elem.XMLName.Local)
+		if !kind.valid() {
+			other = append(other, elem)
+			continue
+		}
+		data, err := xml.Marshal(elem)
+		if err != nil {
+			return err
+		}
+		source := &Source{}
+		if err := xml.Unmarshal(data, source); err != nil {
+			return fmt.Errorf("invalid %s: %v", kind, err)
+		}
+		source.Kind = kind
+		band.Sources = append(band.Sources, source)
+	}
+	band.Other = other
+	return nil
+}
+
+// SetPixelFunctionArgument sets an argument passed to the pixel function
+// of a derived band, for the functions accepting some.
+func (band *Band) SetPixelFunctionArgument(key, value string) *Band {
+	if band.PixelFunctionArguments == nil {
+		band.PixelFunctionArguments = &RawElement{XMLName: xml.Name{Local: "PixelFunctionArguments"}}
+	}
+	attrs := band.PixelFunctionArguments.Attrs
+	for i := range attrs {
+		if attrs[i].Name.Local == key {
+			attrs[i].Value = value
+			return band
+		}
+	}
+	band.PixelFunctionArguments.Attrs = append(attrs, xml.Attr{Name: xml.Name{Local: key}, Value: value})
+	return band
+}
+
+// SourceKind is the kind of a band source.
+type SourceKind string
+
+const (
+	SimpleSource         = SourceKind("SimpleSource")
+	ComplexSource        = SourceKind("ComplexSource")
+	AveragedSource       = SourceKind("AveragedSource")
+	KernelFilteredSource = SourceKind("KernelFilteredSource")
+)
+
+func (kind SourceKind) valid() bool {
+	switch kind {
+	case SimpleSource, ComplexSource, AveragedSource, KernelFilteredSource:
+		return true
+	}
+	return false
+}
+
+// Source is a source of a band. The fields after DstRect only apply to
+// complex, averaged and kernel filtered sources.
+type Source struct {
+	Kind SourceKind `xml:"-"`
+	// Resampling is the resampling algorithm used when the source and
+	// destination windows differ in size, e.g. "bilinear".
+	Resampling  string       `xml:"resampling,attr,omitempty"`
+	Filename    Filename     `xml:"SourceFilename"`
+	OpenOptions *OpenOptions `xml:"OpenOptions"`
+	// Band is the band number of the source, or "mask,n" for the mask of
+	// band n.
+	Band       string            `xml:"SourceBand"`
+	Properties *SourceProperties `xml:"SourceProperties"`
+	SrcRect    *Rect             `xml:"SrcRect"`
+	DstRect    *Rect             `xml:"DstRect"`
+
+	NoData              *float64 `xml:"NODATA"`
+	UseMaskBand         Bool     `xml:"UseMaskBand,omitempty"`
+	ScaleOffset         *float64 `xml:"ScaleOffset"`
+	ScaleRatio          *float64 `xml:"ScaleRatio"`
+	LUT                 LUT      `xml:"LUT,omitempty"`
+	ColorTableComponent int      `xml:"ColorTableComponent,omitempty"`
+	Kernel              *Kernel  `xml:"Kernel"`
+}
+
+// Filename is the name of the dataset of a source.
+type Filename struct {
+	// RelativeToVRT is set when Name is relative to the VRT file rather
+	// than to the current directory.
+	RelativeToVRT Bool   `xml:"relativeToVRT,attr"`
+	Shared        *Bool  `xml:"shared,attr,omitempty"`
+	Name          string `xml:",chardata"`
+}
+
+// OpenOptions are the open options of the dataset of a source.
+type OpenOptions struct {
+	Items []MetadataItem `xml:"OOI"`
+}
+
+// SourceProperties describe the source dataset, sparing GDAL from opening
+// it until its pixels are needed.
+type SourceProperties struct {
+	XSize      int    `xml:"RasterXSize,attr"`
+	YSize      int    `xml:"RasterYSize,attr"`
+	DataType   string `xml:"DataType,attr"`
+	BlockXSize int    `xml:"BlockXSize,attr,omitempty"`
+	BlockYSize int    `xml:"BlockYSize,attr,omitempty"`
+}
+
+// Rect is a window of a source or band. Source windows may be fractional.
+type Rect struct {
+	XOff  float64 `xml:"xOff,attr"`
+	YOff  float64 `xml:"yOff,attr"`
+	XSize float64 `xml:"xSize,attr"`
+	YSize float64 `xml:"ySize,attr"`
+}
+
+func rectFor(win gdal.Window) *Rect {
+	if win == (gdal.Window{}) {
+		return nil
+	}
+	return &Rect{float64(win.XOff), float64(win.YOff), float64(win.XSize), float64(win.YSize)}
+}
+
+// MarshalXML implements xml.Marshaler, naming the element after the kind
+// of the source.
+func (source *Source) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain Source
+	start.Name.Local = string(source.Kind)
+	if source.Kind == "" {
+		start.Name.Local = string(SimpleSource)
+	}
+	return e.EncodeElement((*plain)(source), start)
+}
+
+// SetNoData sets the source value treated as nodata.
+func (source *Source) SetNoData(value float64) *Source {
+	source.NoData = &value
+	return source
+}
+
+// SetScaleOffset maps the source values v to v*scale + offset.
+func (source *Source) SetScaleOffset(scale, offset float64) *Source {
+	source.ScaleRatio, source.ScaleOffset = &scale, &offset
+	return source
+}
+
+// SetLUT maps the source values through lut, interpolating linearly
+// between its entries.
+func (source *Source) SetLUT(lut LUT) *Source {
+	source.LUT = lut
+	return source
+}
+
+// SetProperties describes the source dataset.
+func (source *Source) SetProperties(xSize, ySize int, dataType gdal.DataType) *Source {
+	source.Properties = &SourceProperties{XSize: xSize, YSize: ySize, DataType: dataType.Name()}
+	return source
+}
+
+// LUTEntry maps the source value In to Out.
+type LUTEntry struct {
+	In, Out float64
+}
+
+// LUT is a lookup table of a source, sorted by input value.
+type LUT []LUTEntry
+
+// MarshalText implements encoding.TextMarshaler.
+func (lut LUT) MarshalText() ([]byte, error) {
+	entries := make([]string, len(lut))
+	for i, entry := range lut {
+		entries[i] = formatFloat(entry.In) + ":" + formatFloat(entry.Out)
+	}
+	return []byte(strings.Join(entries, ",")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (lut *LUT) UnmarshalText(text []byte) error {
+	*lut = nil
+	for _, entry := range strings.Split(string(text), ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		values, err := parseFloats(entry, ":")
+		if err != nil || len(values) != 2 {
+			return fmt.Errorf("invalid LUT entry %q", entry)
+		}
+		*lut = append(*lut, LUTEntry{values[0], values[1]})
+	}
+	return nil
+}
+
+// Kernel is the convolution kernel of a kernel filtered source.
+type Kernel struct {
+	// Normalized divides the result by the sum of the coefficients.
+	Normalized Bool `xml:"normalized,attr"`
+	// Size is the width of the kernel, which is square if it has Size*Size
+	// coefficients and separable if it has Size.
+	Size  int   `xml:"Size"`
+	Coefs Coefs `xml:"Coefs"`
+}
+
+// Coefs are the coefficients of a kernel, in row major order.
+type Coefs []float64
+
+// MarshalText implements encoding.TextMarshaler.
+func (coefs Coefs) MarshalText() ([]byte, error) {
+	return []byte(formatFloats(coefs, " ")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (coefs *Coefs) UnmarshalText(text []byte) error {
+	values, err := parseFloats(strings.Join(strings.Fields(string(text)), " "), " ")
+	if err != nil {
+		return fmt.Errorf("invalid kernel coefficients: %v", err)
+	}
+	*coefs = values
+	return nil
+}
+
+// Bool is a boolean serialized as 0 or 1, as GDAL expects for most VRT
+// flags.
+type Bool bool
+
+// MarshalText implements encoding.TextMarshaler.
+func (b Bool) MarshalText() ([]byte, error) {
+	if b {
+		return []byte("1"), nil
+	}
+	return []byte("0"), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting the values
+// understood by GDAL.
+func (b *Bool) UnmarshalText(text []byte) error {
+	switch strings.ToLower(strings.TrimSpace(string(text))) {
+	case "0", "false", "no", "off":
+		*b = false
+	default:
+		*b = true
+	}
+	return nil
+}
+
+func setMetadataItem(domains []Metadata, key, value, domain string) []Metadata {
+	for i := range domains {
+		if domains[i].Domain != domain {
+			continue
+		}
+		for j := range domains[i].Items {
+			if domains[i].Items[j].Key == key {
+				domains[i].Items[j].Value = value
+				return domains
+			}
+		}
+		domains[i].Items = append(domains[i].Items, MetadataItem{key, value})
+		return domains
+	}
+	return append(domains, Metadata{Domain: domain, Items: []MetadataItem{{key, value}}})
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func formatFloats(values []float64, sep string) string {
+	var buf bytes.Buffer
+	for i, value := range values {
+		if i > 0 {
+			buf.WriteString(sep)
+		}
+		buf.WriteString(formatFloat(value))
+	}
+	return buf.String()
+}
+
+func parseFloats(text, sep string) ([]float64, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	fields := strings.Split(text, sep)
+	values := make([]float64, len(fields))
+	for i, field := range fields {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,110 @@
+package vrt
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/airmap/gdal"
+)
+
+func TestRoundTrip(t *testing.T) {
+	ds := New(4, 2).SetGeoTransform(gdal.GeoTransform{100, 2, 0, 200, 0, -2})
+	ds.SetMetadataItem("AREA_OR_POINT", "Area", "")
+	band := ds.AddBand(gdal.Float32).SetNoData(math.NaN()).SetScale(0.5).SetOffset(1)
+	band.AddSimpleSource("a.tif", 1, gdal.Window{}, gdal.Window{XSize: 2, YSize: 2})
+	band.AddComplexSource("b.tif", 2, gdal.Window{XSize: 4, YSize: 4}, gdal.Window{XOff: 2, XSize: 2, YSize: 2}).
+		SetNoData(0).
+		SetScaleOffset(2, 1).
+		SetLUT(LUT{{0, 0}, {10, 100}})
+	ds.AddBand(gdal.Byte).AddKernelFilteredSource("a.tif", 1, gdal.Window{}, gdal.Window{}, Kernel{Normalized: true, Size: 3, Coefs: Coefs{1, 2, 1}})
+	ds.AddPixelFuncBand(gdal.Float32, "ndvi").SetPixelFunctionArgument("k", "1")
+
+	data, err := ds.XML()
+	if err != nil {
+		t.Fatalf("XML: %v", err)
+	}
+	for _, want := range []string{
+		`<GeoTransform>100, 2, 0, 200, 0, -2</GeoTransform>`,
+		`<NoDataValue>NaN</NoDataValue>`,
+		`<SimpleSource>`,
+		`<SourceFilename relativeToVRT="0">b.tif</SourceFilename>`,
+		`<SrcRect xOff="0" yOff="0" xSize="4" ySize="4"></SrcRect>`,
+		`<LUT>0:0,10:100</LUT>`,
+		`<Kernel normalized="1">`,
+		`<Coefs>1 2 1</Coefs>`,
+		`<PixelFunctionArguments k="1"></PixelFunctionArguments>`,
+	} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("XML has no %s:\n%s", want, data)
+		}
+	}
+
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	again, err := parsed.XML()
+	if err != nil {
+		t.Fatalf("XML: %v", err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Errorf("round trip changed the XML:\n%s\n%s", data, again)
+	}
+	if sources := parsed.Bands[0].Sources; len(sources) != 2 || sources[1].Kind != ComplexSource || sources[1].LUT[1] != (LUTEntry{10, 100}) {
+		t.Errorf("got sources %+v", sources)
+	}
+}
+
+func TestParseKeepsUnknownElements(t *testing.T) {
+	data := []byte(`<VRTDataset rasterXSize="1" rasterYSize="1"><GCPList Projection=""></GCPList>` +
+		`<VRTRasterBand dataType="Byte" band="1"><ColorTable><Entry c1="0" c2="0" c3="0" c4="255"></Entry></ColorTable>` +
+		`<SimpleSource><SourceFilename relativeToVRT="1">a.tif</SourceFilename><SourceBand>mask,1</SourceBand></SimpleSource>` +
+		`</VRTRasterBand></VRTDataset>`)
+	ds, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(ds.Other) != 1 || len(ds.Bands[0].Other) != 1 || len(ds.Bands[0].Sources) != 1 {
+		t.Fatalf("got %+v and band %+v", ds, ds.Bands[0])
+	}
+	if source := ds.Bands[0].Sources[0]; !bool(source.Filename.RelativeToVRT) || source.Band != "mask,1" {
+		t.Errorf("got source %+v", source)
+	}
+	if _, err := Parse([]byte(`<VRTDataset rasterXSize="1" rasterYSize="1"><GeoTransform>1, 2</GeoTransform></VRTDataset>`)); err == nil {
+		t.Error("invalid GeoTransform: got no error")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	driver, err := gdal.GetDriverByName("GTiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		src := driver.Create(fmt.Sprintf("/vsimem/vrt%d.tif", i), 2, 2, 1, gdal.Byte, nil)
+		if err := gdal.WriteWindow(src.RasterBand(1), gdal.Window{XSize: 2, YSize: 2}, []uint8{1, 2, 3, byte(4 + i)}); err != nil {
+			t.Fatal(err)
+		}
+		src.Close()
+	}
+
+	ds := New(4, 2)
+	band := ds.AddBand(gdal.Int16)
+	band.AddSimpleSource("/vsimem/vrt0.tif", 1, gdal.Window{}, gdal.Window{XSize: 2, YSize: 2})
+	band.AddComplexSource("/vsimem/vrt1.tif", 1, gdal.Window{}, gdal.Window{XOff: 2, XSize: 2, YSize: 2}).SetScaleOffset(10, 0)
+
+	mosaic, err := ds.Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer mosaic.Close()
+	got, err := gdal.ReadWindow[int16](mosaic.RasterBand(1), gdal.Window{XSize: 4, YSize: 2})
+	if err != nil {
+		t.Fatalf("ReadWindow: %v", err)
+	}
+	if fmt.Sprint(got) != "[1 2 10 20 3 4 30 50]" {
+		t.Errorf("got %v, want [1 2 10 20 3 4 30 50]", got)
+	}
+}
@@ -0,0 +1,202 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"runtime"
+	"strings"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      In-memory encoding and decoding                                 */
+/* -------------------------------------------------------------------- */
+
+// EncodeDataset writes a copy of the dataset in the format of the named
+// driver, with the given creation options, and returns its content. Formats
+// writing several files, such as shapefiles or rasters with sidecar files,
+// are returned as a zip archive of the files, which OpenBytes can open.
+func EncodeDataset(dataset Dataset, driverName string, options []string) ([]byte, error) {
+	driver, err := GetDriverByName(driverName)
+	if err != nil {
+		return nil, err
+	}
+	dir := vsimemName("")
+	defer removeVSIMemDir(dir)
+	name := dir + "/" + encodedBaseName(dataset)
+	if extensions := driver.Info().Extensions; len(extensions) > 0 {
+		name += "." + extensions[0]
+	}
+	if err := createCopyAndClose(driver, name, dataset, options); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, file := range vsiReadDirRecursive(dir) {
+		if !strings.HasSuffix(file, "/") {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 1 {
+		return vsimemContent(dir + "/" + files[0])
+	}
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		data, err := vsimemContent(dir + "/" + file)
+		if err != nil {
+			return nil, err
+		}
+		w, err := archive.Create(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var unsafeFileName = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// encodedBaseName names the files written by EncodeDataset after the
+// dataset, without its extension, so that shapefile layers for instance
+// keep their name.
+func encodedBaseName(dataset Dataset) string {
+	base := path.Base(strings.ReplaceAll(dataset.Description(), "\\", "/"))
+	base = strings.TrimSuffix(base, path.Ext(base))
+	if base == "" || base == "." || unsafeFileName.MatchString(base) {
+		return "data"
+	}
+	return base
+}
+
+func createCopyAndClose(driver Driver, name string, dataset Dataset, options []string) error {
+	defer runtime.KeepAlive(dataset.ref)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cOptions, freeOptions := cStringList(options)
+	defer freeOptions()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	h := C.GDALCreateCopy(driver.cval, cName, dataset.cval, 0, cOptions, nil, nil)
+	if h == nil {
+		return failure("GDALCreateCopy", fmt.Sprintf("cannot encode dataset as %s", driver.ShortName()))
+	}
+	C.GDALClose(h)
+	if C.CPLGetLastErrorType() >= C.CE_Failure {
+		return failure("GDALClose", "cannot write dataset")
+	}
+	return nil
+}
+
+// vsimemContent returns the content of a /vsimem file and deletes it.
+func vsimemContent(name string) ([]byte, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	var length C.vsi_l_offset
+	buffer := C.VSIGetMemFileBuffer(cName, &length, 1)
+	if buffer == nil {
+		return nil, fmt.Errorf("cannot read '%s'", name)
+	}
+	defer C.VSIFree(unsafe.Pointer(buffer))
+	// C.GoBytes takes an int32 length, too small for large datasets.
+	if uint64(length) > math.MaxInt {
+		return nil, fmt.Errorf("'%s' is too large, %d bytes", name, uint64(length))
+	}
+	data := make([]byte, int(length))
+	copy(data, unsafe.Slice((*byte)(unsafe.Pointer(buffer)), int(length)))
+	return data, nil
+}
+
+func vsiReadDirRecursive(dir string) []string {
+	cDir := C.CString(dir)
+	defer C.free(unsafe.Pointer(cDir))
+
+	list := C.VSIReadDirRecursive(cDir)
+	defer C.CSLDestroy(list)
+	return goStringList(list)
+}
+
+// removeVSIMemDir deletes a /vsimem directory and the files in it.
+func removeVSIMemDir(dir string) {
+	files := vsiReadDirRecursive(dir)
+	for i := len(files) - 1; i >= 0; i-- {
+		name := C.CString(dir + "/" + strings.TrimSuffix(files[i], "/"))
+		if strings.HasSuffix(files[i], "/") {
+			C.VSIRmdir(name)
+		} else {
+			C.VSIUnlink(name)
+		}
+		C.free(unsafe.Pointer(name))
+	}
+	cDir := C.CString(dir)
+	C.VSIRmdir(cDir)
+	C.free(unsafe.Pointer(cDir))
+}
+
+// OpenBytes opens a dataset from its content, as Open would from a file
+// holding it. Zip archives, such as those returned by EncodeDataset for
+// multi-file formats, are opened through /vsizip. The data is copied, and
+// the in-memory file holding the copy is deleted once the dataset is
+// closed.
+func OpenBytes(data []byte, flags OpenFlag) (Dataset, error) {
+	if len(data) == 0 {
+		return Dataset{}, fmt.Errorf("no data to open")
+	}
+	name := vsimemName("")
+	filename := name
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		name += ".zip"
+		filename = "/vsizip/" + name
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	// GDAL takes ownership of the copy, freeing it with the file.
+	buffer := C.CBytes(data)
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+
+	file := C.VSIFileFromMemBuffer(cName, (*C.GByte)(buffer), C.vsi_l_offset(len(data)), 1)
+	if file == nil {
+		C.free(buffer)
+		return Dataset{}, failure("VSIFileFromMemBuffer", "cannot create in-memory file")
+	}
+	C.VSIFCloseL(file)
+
+	dataset, err := OpenEx(filename, flags, nil, nil, nil)
+	if err != nil {
+		C.VSIUnlink(cName)
+		return Dataset{}, err
+	}
+	onDatasetClose(dataset.cval, func() {
+		cName := C.CString(name)
+		C.VSIUnlink(cName)
+		C.free(unsafe.Pointer(cName))
+	})
+	return dataset, nil
+}
@@ -0,0 +1,127 @@
+package gdal
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/airmap/gdal/ogr"
+)
+
+// leftoverVSIMemFiles lists the temporary /vsimem files still present.
+func leftoverVSIMemFiles() []string {
+	var files []string
+	for _, file := range vsiReadDirRecursive("/vsimem") {
+		if strings.HasPrefix(file, "go-gdal-") {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+func TestEncodeDatasetOpenBytes(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := driver.Create("", 4, 3, 1, Byte, nil)
+	defer src.Close()
+	values := []uint8{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	if err := WriteWindow(src.RasterBand(1), Window{XSize: 4, YSize: 3}, values); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []struct {
+		driver string
+		magic  string
+	}{{"GTiff", "II*\x00"}, {"PNG", "\x89PNG"}} {
+		data, err := EncodeDataset(src, format.driver, nil)
+		if err != nil {
+			t.Fatalf("EncodeDataset(%s): %v", format.driver, err)
+		}
+		if !bytes.HasPrefix(data, []byte(format.magic)) {
+			t.Errorf("%s data starts with %q", format.driver, data[:4])
+		}
+		ds, err := OpenBytes(data, OFRaster)
+		if err != nil {
+			t.Fatalf("OpenBytes(%s): %v", format.driver, err)
+		}
+		got, err := ReadWindow[uint8](ds.RasterBand(1), Window{XSize: 4, YSize: 3})
+		ds.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, values) {
+			t.Errorf("%s round trip = %v, want %v", format.driver, got, values)
+		}
+	}
+	if _, err := EncodeDataset(src, "NoSuchDriver", nil); err == nil {
+		t.Error("EncodeDataset with an unknown driver succeeded")
+	}
+	if _, err := OpenBytes([]byte{0xde, 0xad, 0xbe, 0xef}, OFRaster); err == nil {
+		t.Error("OpenBytes of garbage succeeded")
+	}
+	if files := leftoverVSIMemFiles(); len(files) > 0 {
+		t.Errorf("temporary files left: %v", files)
+	}
+}
+
+func TestEncodeDatasetMultiFile(t *testing.T) {
+	driver, err := GetDriverByName("Memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := driver.Create("", 0, 0, 0, Unknown, nil)
+	defer src.Close()
+	layer, err := src.CreateLayer("roads", ogr.SpatialReference{}, ogr.GT_Point, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feature := layer.Definition().Create()
+	defer feature.Destroy()
+	geom, err := ogr.CreateFromWKT("POINT (1 2)", ogr.SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer geom.Destroy()
+	if err := feature.SetGeometry(geom); err != nil {
+		t.Fatal(err)
+	}
+	if err := layer.Create(feature); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := EncodeDataset(src, "ESRI Shapefile", nil)
+	if err != nil {
+		t.Fatalf("EncodeDataset: %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("shapefile not returned as a zip: %v", err)
+	}
+	names := map[string]bool{}
+	for _, file := range archive.File {
+		names[file.Name[strings.LastIndex(file.Name, "."):]] = true
+	}
+	for _, ext := range []string{".shp", ".shx", ".dbf"} {
+		if !names[ext] {
+			t.Errorf("no %s file in the zip", ext)
+		}
+	}
+
+	ds, err := OpenBytes(data, OFVector)
+	if err != nil {
+		t.Fatalf("OpenBytes: %v", err)
+	}
+	layer, err = ds.Layer(0)
+	if err != nil || ds.LayerCount() != 1 {
+		t.Errorf("%d layers, want 1 (%v)", ds.LayerCount(), err)
+	} else if count, _ := layer.FeatureCount(true); count != 1 {
+		t.Errorf("%d features, want 1", count)
+	}
+	ds.Close()
+	if files := leftoverVSIMemFiles(); len(files) > 0 {
+		t.Errorf("temporary files left: %v", files)
+	}
+}
@@ -0,0 +1,207 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Typed window I/O                                                */
+/* -------------------------------------------------------------------- */
+
+// Numeric is the set of Go types raster data can be read into and written
+// from. complex64 and complex128 map to CFloat32 and CFloat64.
+type Numeric interface {
+	~int8 | ~uint8 | ~int16 | ~uint16 | ~int32 | ~uint32 | ~int64 | ~uint64 |
+		~float32 | ~float64 | ~complex64 | ~complex128
+}
+
+// Window is a rectangular region of a raster, in pixels.
+type Window struct {
+	XOff, YOff   int
+	XSize, YSize int
+}
+
+// Len returns the number of pixels in the window.
+func (win Window) Len() int {
+	return win.XSize * win.YSize
+}
+
+// within checks that the window is not empty and lies inside a raster of
+// the given size.
+func (win Window) within(xSize, ySize int) error {
+	if win.XSize <= 0 || win.YSize <= 0 {
+		return fmt.Errorf("window %+v is empty", win)
+	}
+	if win.XOff < 0 || win.YOff < 0 || win.XOff+win.XSize > xSize || win.YOff+win.YSize > ySize {
+		return fmt.Errorf("window %+v is outside of the %dx%d raster", win, xSize, ySize)
+	}
+	return nil
+}
+
+// DataTypeOf returns the DataType matching the Go type T.
+func DataTypeOf[T Numeric]() DataType {
+	var zero T
+	switch reflect.TypeOf(zero).Kind() {
+	case reflect.Int8:
+		return Int8
+	case reflect.Uint8:
+		return Byte
+	case reflect.Int16:
+		return Int16
+	case reflect.Uint16:
+		return UInt16
+	case reflect.Int32:
+		return Int32
+	case reflect.Uint32:
+		return UInt32
+	case reflect.Int64:
+		return Int64
+	case reflect.Uint64:
+		return UInt64
+	case reflect.Float32:
+		return Float32
+	case reflect.Float64:
+		return Float64
+	case reflect.Complex64:
+		return CFloat32
+	case reflect.Complex128:
+		return CFloat64
+	}
+	return Unknown
+}
+
+// ReadWindow reads win of band into a new slice, converting the pixels to
+// T.
+func ReadWindow[T Numeric](band RasterBand, win Window) ([]T, error) {
+	if err := win.within(band.XSize(), band.YSize()); err != nil {
+		return nil, err
+	}
+	data := make([]T, win.Len())
+	if err := band.windowIO(Read, win, DataTypeOf[T](), unsafe.Pointer(&data[0])); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ReadWindowInto is ReadWindow reading into data, which must hold exactly
+// the pixels of win.
+func ReadWindowInto[T Numeric](band RasterBand, win Window, data []T) error {
+	if err := win.within(band.XSize(), band.YSize()); err != nil {
+		return err
+	}
+	if len(data) != win.Len() {
+		return fmt.Errorf("buffer has %d elements, window %+v needs %d", len(data), win, win.Len())
+	}
+	return band.windowIO(Read, win, DataTypeOf[T](), unsafe.Pointer(&data[0]))
+}
+
+// WriteWindow writes data, which must hold exactly the pixels of win in
+// row major order, to win of band.
+func WriteWindow[T Numeric](band RasterBand, win Window, data []T) error {
+	if err := win.within(band.XSize(), band.YSize()); err != nil {
+		return err
+	}
+	if len(data) != win.Len() {
+		return fmt.Errorf("buffer has %d elements, window %+v needs %d", len(data), win, win.Len())
+	}
+	return band.windowIO(Write, win, DataTypeOf[T](), unsafe.Pointer(&data[0]))
+}
+
+func (rasterBand RasterBand) windowIO(rwFlag RWFlag, win Window, dataType DataType, dataPtr unsafe.Pointer) error {
+	defer runtime.KeepAlive(rasterBand.ref)
+	return cplCall("GDALRasterIO", func() C.CPLErr {
+		return C.GDALRasterIO(
+			rasterBand.cval,
+			C.GDALRWFlag(rwFlag),
+			C.int(win.XOff), C.int(win.YOff), C.int(win.XSize), C.int(win.YSize),
+			dataPtr,
+			C.int(win.XSize), C.int(win.YSize),
+			C.GDALDataType(dataType),
+			0, 0,
+		)
+	})
+}
+
+// Interleave is the layout of multi band pixel buffers.
+type Interleave int
+
+const (
+	// InterleavePixel stores all bands of a pixel next to each other
+	// (RGBRGB...).
+	InterleavePixel = Interleave(iota)
+	// InterleaveLine stores a line of each band in turn (RRGGBB per line).
+	InterleaveLine
+	// InterleaveBand stores each band as a whole in turn (RR..GG..BB..).
+	InterleaveBand
+)
+
+// ReadInterleaved reads win of the given bands (1-based, all bands if
+// empty) into buffer, a numeric slice of the types accepted by IO, laid
+// out according to interleave. buffer must hold exactly the pixels of win
+// for each band.
+func (dataset Dataset) ReadInterleaved(win Window, bands []int, interleave Interleave, buffer interface{}) error {
+	defer runtime.KeepAlive(dataset.ref)
+	if err := win.within(dataset.RasterXSize(), dataset.RasterYSize()); err != nil {
+		return err
+	}
+	if len(bands) == 0 {
+		bands = make([]int, dataset.RasterCount())
+		for i := range bands {
+			bands[i] = i + 1
+		}
+	}
+	dataType, dataPtr, err := determineBufferType(buffer)
+	if err != nil {
+		return err
+	}
+	if n, want := reflect.ValueOf(buffer).Len(), win.Len()*len(bands); n != want {
+		return fmt.Errorf("buffer has %d elements, window %+v of %d bands needs %d", n, win, len(bands), want)
+	}
+
+	size := dataType.Size() / 8
+	var pixelSpace, lineSpace, bandSpace int
+	switch interleave {
+	case InterleavePixel:
+		pixelSpace = size * len(bands)
+		lineSpace = pixelSpace * win.XSize
+		bandSpace = size
+	case InterleaveLine:
+		pixelSpace = size
+		lineSpace = size * win.XSize * len(bands)
+		bandSpace = size * win.XSize
+	case InterleaveBand:
+		pixelSpace = size
+		lineSpace = size * win.XSize
+		bandSpace = lineSpace * win.YSize
+	default:
+		return fmt.Errorf("unknown interleave %d", interleave)
+	}
+
+	bandMap := IntSliceToCInt(bands)
+	return cplCall("GDALDatasetRasterIO", func() C.CPLErr {
+		return C.GDALDatasetRasterIO(
+			dataset.cval,
+			C.GDALRWFlag(Read),
+			C.int(win.XOff), C.int(win.YOff), C.int(win.XSize), C.int(win.YSize),
+			dataPtr,
+			C.int(win.XSize), C.int(win.YSize),
+			C.GDALDataType(dataType),
+			C.int(len(bands)),
+			(*C.int)(unsafe.Pointer(&bandMap[0])),
+			C.int(pixelSpace), C.int(lineSpace), C.int(bandSpace),
+		)
+	})
+}
@@ -0,0 +1,60 @@
+package gdal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetermineBufferType(t *testing.T) {
+	if dataType, _, err := determineBufferType([]int8{1}); err != nil || dataType != Int8 {
+		t.Errorf("[]int8: got %v, %v, want Int8", dataType, err)
+	}
+	if _, _, err := determineBufferType([]float64{}); err == nil {
+		t.Errorf("empty buffer: got no error")
+	}
+}
+
+func TestWindowIO(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := driver.Create("", 4, 3, 2, Float32, nil)
+	defer ds.Close()
+
+	win := Window{XOff: 1, YOff: 1, XSize: 2, YSize: 2}
+	if err := WriteWindow(ds.RasterBand(1), win, []float32{1, 2, 3, 4}); err != nil {
+		t.Fatalf("WriteWindow: %v", err)
+	}
+	if err := WriteWindow(ds.RasterBand(2), win, []int64{5, 6, 7, 8}); err != nil {
+		t.Fatalf("WriteWindow: %v", err)
+	}
+	if err := WriteWindow(ds.RasterBand(1), win, []float32{1}); err == nil {
+		t.Errorf("short buffer: got no error")
+	}
+	if _, err := ReadWindow[uint8](ds.RasterBand(1), Window{XOff: 3, XSize: 2, YSize: 1}); err == nil {
+		t.Errorf("window outside of the raster: got no error")
+	}
+
+	got, err := ReadWindow[complex128](ds.RasterBand(1), win)
+	if err != nil {
+		t.Fatalf("ReadWindow: %v", err)
+	}
+	if want := []complex128{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for interleave, want := range map[Interleave][]int16{
+		InterleavePixel: {1, 5, 2, 6, 3, 7, 4, 8},
+		InterleaveLine:  {1, 2, 5, 6, 3, 4, 7, 8},
+		InterleaveBand:  {1, 2, 3, 4, 5, 6, 7, 8},
+	} {
+		buf := make([]int16, 8)
+		if err := ds.ReadInterleaved(win, nil, interleave, buf); err != nil {
+			t.Fatalf("ReadInterleaved: %v", err)
+		}
+		if !reflect.DeepEqual(buf, want) {
+			t.Errorf("interleave %d: got %v, want %v", interleave, buf, want)
+		}
+	}
+}
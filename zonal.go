@@ -0,0 +1,420 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+
+	"github.com/airmap/gdal/ogr"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Zonal statistics                                                */
+/* -------------------------------------------------------------------- */
+
+// Stat is a statistic computed by ZonalStats.
+type Stat int
+
+const (
+	StatCount Stat = iota
+	StatSum
+	StatMean
+	StatMin
+	StatMax
+	StatStd
+	StatMajority
+	// StatHistogram counts the pixels of each distinct value, for
+	// instance land cover classes.
+	StatHistogram
+)
+
+var statNames = [...]string{"count", "sum", "mean", "min", "max", "std", "majority", "hist"}
+
+// String returns the name of the statistic, used to name the fields
+// written by ZonalStats.
+func (stat Stat) String() string {
+	if stat < 0 || int(stat) >= len(statNames) {
+		return fmt.Sprintf("Stat(%d)", int(stat))
+	}
+	return statNames[stat]
+}
+
+// ZonalStatsOptions configure ZonalStats.
+type ZonalStatsOptions struct {
+	// AllTouched counts all the pixels touched by a feature, rather than
+	// those whose center is inside it.
+	AllTouched bool
+
+	// IgnoreNoData counts pixels equal to the nodata value as valid.
+	IgnoreNoData bool
+	// IgnoreMask counts pixels masked out by the mask band as valid.
+	IgnoreMask bool
+
+	// WriteFields writes the statistics to fields of the layer, named
+	// FieldPrefix followed by the name of the statistic, such as
+	// "elev_mean", which are created if needed. Histograms are written
+	// to one integer field per value, such as "lc_hist_12". Statistics of
+	// features without valid pixels are null, except their count.
+	WriteFields bool
+	FieldPrefix string
+
+	// Progress, if not nil, is called after each feature with
+	// ProgressData. Returning 0 cancels the computation.
+	Progress     ProgressFunc
+	ProgressData interface{}
+}
+
+// ZoneStats are the statistics of the valid pixels of a feature. Those
+// not requested are left zero.
+type ZoneStats struct {
+	FID int64
+	// Count is the number of valid pixels, set whether requested or not.
+	Count               int
+	Sum, Mean, Min, Max float64
+	Std                 float64
+	// Majority is the most frequent value, the lowest one in case of a
+	// tie.
+	Majority float64
+	// Histogram maps the values found to their number of pixels.
+	Histogram map[float64]int
+}
+
+// ZonalStats computes statistics of the pixels of band covered by each
+// feature of layer, read from the start, in layer order. Features are
+// reprojected to the spatial reference of the band's dataset when both
+// have one, and rasterized with GDAL. Pixels are valid unless they are
+// NaN, equal to the nodata value or masked out by the mask band.
+func ZonalStats(band RasterBand, layer ogr.Layer, stats []Stat, opts ZonalStatsOptions) ([]ZoneStats, error) {
+	want := map[Stat]bool{}
+	for _, stat := range stats {
+		if stat < StatCount || stat > StatHistogram {
+			return nil, fmt.Errorf("unknown statistic %v", stat)
+		}
+		want[stat] = true
+	}
+	dataset := band.GetDataset()
+	gt := dataset.GeoTransform()
+	transform, release, err := zoneTransform(layer, dataset.Projection())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	mem, err := GetDriverByName("MEM")
+	if err != nil {
+		return nil, err
+	}
+
+	noData, hasNoData := band.NoDataValue()
+	hasNoData = hasNoData && !opts.IgnoreNoData
+	// Masks derived from the nodata value are skipped, nodata being
+	// handled above.
+	useMask := !opts.IgnoreMask && band.GetMaskFlags()&(GMF_AllValid|GMF_NoData) == 0
+	total, _ := layer.FeatureCount(true)
+
+	var results []ZoneStats
+	layer.ResetReading()
+	for feature := layer.NextFeature(); feature != nil; feature = layer.NextFeature() {
+		zone := ZoneStats{FID: feature.FID()}
+		values, err := zonePixels(band, feature.Geometry(), gt, transform, mem, opts.AllTouched, useMask)
+		feature.Destroy()
+		if err != nil {
+			return nil, err
+		}
+		var counts map[float64]int
+		if want[StatMajority] || want[StatHistogram] {
+			counts = map[float64]int{}
+		}
+		var mean, m2 float64
+		for i, v := range values.data {
+			if values.inside[i] == 0 || math.IsNaN(v) || (hasNoData && v == noData) || (useMask && values.mask[i] == 0) {
+				continue
+			}
+			zone.Count++
+			if zone.Count == 1 || v < zone.Min {
+				zone.Min = v
+			}
+			if zone.Count == 1 || v > zone.Max {
+				zone.Max = v
+			}
+			zone.Sum += v
+			delta := v - mean
+			mean += delta / float64(zone.Count)
+			m2 += delta * (v - mean)
+			if counts != nil {
+				counts[v]++
+			}
+		}
+		if zone.Count > 0 {
+			zone.Mean = zone.Sum / float64(zone.Count)
+			zone.Std = math.Sqrt(m2 / float64(zone.Count))
+		}
+		if want[StatMajority] {
+			best := 0
+			for v, count := range counts {
+				if count > best || (count == best && v < zone.Majority) {
+					zone.Majority, best = v, count
+				}
+			}
+		}
+		if want[StatHistogram] {
+			zone.Histogram = counts
+		}
+		results = append(results, zone.only(want))
+
+		if opts.Progress != nil && opts.Progress(float64(len(results))/math.Max(float64(total), 1), "", opts.ProgressData) == 0 {
+			return nil, fmt.Errorf("zonal statistics interrupted")
+		}
+	}
+
+	if opts.WriteFields {
+		if err := writeZoneStats(layer, results, stats, opts.FieldPrefix); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// only zeroes the statistics not wanted.
+func (zone ZoneStats) only(want map[Stat]bool) ZoneStats {
+	if !want[StatSum] {
+		zone.Sum = 0
+	}
+	if !want[StatMean] {
+		zone.Mean = 0
+	}
+	if !want[StatMin] {
+		zone.Min = 0
+	}
+	if !want[StatMax] {
+		zone.Max = 0
+	}
+	if !want[StatStd] {
+		zone.Std = 0
+	}
+	return zone
+}
+
+// zoneTransform returns the transformation from the layer's spatial
+// reference to the raster's, or nil if none is needed.
+func zoneTransform(layer ogr.Layer, projection string) (*ogr.CoordinateTransform, func(), error) {
+	layerSRS := layer.SpatialReference()
+	if projection == "" || layerSRS.Handle() == nil {
+		return nil, func() {}, nil
+	}
+	rasterSRS := ogr.CreateSpatialReference("")
+	if err := rasterSRS.FromWKT(projection); err != nil {
+		rasterSRS.Destroy()
+		return nil, nil, err
+	}
+	if rasterSRS.IsSame(layerSRS) {
+		rasterSRS.Destroy()
+		return nil, func() {}, nil
+	}
+	// Features are in longitude, latitude order whatever the axis order
+	// of their spatial reference.
+	sourceSRS := layerSRS.Clone()
+	sourceSRS.SetAxisMappingStrategy(ogr.OAMS_TRADITIONAL_GIS_ORDER)
+	rasterSRS.SetAxisMappingStrategy(ogr.OAMS_TRADITIONAL_GIS_ORDER)
+	transform := ogr.CreateCoordinateTransform(sourceSRS, rasterSRS)
+	if transform.IsNull() {
+		sourceSRS.Destroy()
+		rasterSRS.Destroy()
+		return nil, nil, fmt.Errorf("cannot transform features to the raster's spatial reference")
+	}
+	return &transform, func() {
+		transform.Destroy()
+		sourceSRS.Destroy()
+		rasterSRS.Destroy()
+	}, nil
+}
+
+// zoneValues holds the pixels of the window covering a feature, with
+// inside set to 1 for those rasterized from the feature.
+type zoneValues struct {
+	data   []float64
+	inside []uint8
+	mask   []uint8
+}
+
+func zonePixels(
+	band RasterBand,
+	geometry ogr.Geometry,
+	gt GeoTransform,
+	transform *ogr.CoordinateTransform,
+	mem Driver,
+	allTouched, readMask bool,
+) (zoneValues, error) {
+	var values zoneValues
+	if geometry.IsNull() || geometry.IsEmpty() {
+		return values, nil
+	}
+	geometry = geometry.Clone()
+	defer geometry.Destroy()
+	if transform != nil {
+		if err := geometry.Transform(*transform); err != nil {
+			return values, fmt.Errorf("cannot reproject feature: %w", err)
+		}
+	}
+	env := geometry.Envelope()
+	win, err := gt.WindowForBounds(env.MinX(), env.MinY(), env.MaxX(), env.MaxY())
+	if err != nil {
+		return values, err
+	}
+	// Widen the window so that points and lines on pixel edges are
+	// rasterized.
+	win, ok := Window{win.XOff - 1, win.YOff - 1, win.XSize + 2, win.YSize + 2}.Clip(band.XSize(), band.YSize())
+	if !ok {
+		return values, nil
+	}
+
+	zone := mem.Create("", win.XSize, win.YSize, 1, Byte, nil)
+	if zone.cval == nil {
+		return values, fmt.Errorf("cannot create rasterization dataset")
+	}
+	defer zone.Close()
+	x, y := gt.apply(float64(win.XOff), float64(win.YOff))
+	if err := zone.SetGeoTransform(GeoTransform{x, gt[1], gt[2], y, gt[4], gt[5]}); err != nil {
+		return values, err
+	}
+	if err := rasterizeGeometry(zone, geometry, allTouched); err != nil {
+		return values, err
+	}
+
+	if values.inside, err = ReadWindow[uint8](zone.RasterBand(1), Window{XSize: win.XSize, YSize: win.YSize}); err != nil {
+		return values, err
+	}
+	if values.data, err = ReadWindow[float64](band, win); err != nil {
+		return values, err
+	}
+	if readMask {
+		if values.mask, err = ReadWindow[uint8](band.GetMaskBand(), win); err != nil {
+			return values, err
+		}
+	}
+	return values, nil
+}
+
+func rasterizeGeometry(dataset Dataset, geometry ogr.Geometry, allTouched bool) error {
+	defer runtime.KeepAlive(dataset.ref)
+	var options []string
+	if allTouched {
+		options = append(options, "ALL_TOUCHED=TRUE")
+	}
+	cOptions, freeOptions := cStringList(options)
+	defer freeOptions()
+	bands := []C.int{1}
+	geometries := []C.OGRGeometryH{C.OGRGeometryH(geometry.Handle())}
+	burn := []C.double{1}
+
+	return cplCall("GDALRasterizeGeometries", func() C.CPLErr {
+		return C.GDALRasterizeGeometries(
+			dataset.cval,
+			1, &bands[0],
+			1, &geometries[0],
+			nil, nil,
+			&burn[0],
+			cOptions,
+			nil, nil,
+		)
+	})
+}
+
+// writeZoneStats writes results to fields of the layer.
+func writeZoneStats(layer ogr.Layer, results []ZoneStats, stats []Stat, prefix string) error {
+	var classes []float64
+	seen := map[float64]bool{}
+	for _, zone := range results {
+		for v := range zone.Histogram {
+			if !seen[v] {
+				seen[v] = true
+				classes = append(classes, v)
+			}
+		}
+	}
+	sort.Float64s(classes)
+
+	type field struct {
+		stat  Stat
+		class float64
+		index int
+	}
+	var fields []field
+	addField := func(stat Stat, class float64, name string, fieldType ogr.FieldType) error {
+		index := layer.FindFieldIndex(name, true)
+		if index < 0 {
+			definition := ogr.CreateFieldDefinition(name, fieldType)
+			err := layer.CreateField(definition, true)
+			definition.Destroy()
+			if err != nil {
+				return fmt.Errorf("cannot create field %s: %w", name, err)
+			}
+			// Drivers such as Shapefile may have renamed it.
+			index = layer.Definition().FieldCount() - 1
+		}
+		fields = append(fields, field{stat, class, index})
+		return nil
+	}
+	for _, stat := range stats {
+		var err error
+		switch stat {
+		case StatCount:
+			err = addField(stat, 0, prefix+stat.String(), ogr.FT_Integer64)
+		case StatHistogram:
+			for _, class := range classes {
+				if err = addField(stat, class, prefix+stat.String()+"_"+formatFloat(class), ogr.FT_Integer64); err != nil {
+					break
+				}
+			}
+		default:
+			err = addField(stat, 0, prefix+stat.String(), ogr.FT_Real)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, zone := range results {
+		feature := layer.Feature(zone.FID)
+		if feature.IsNull() {
+			return fmt.Errorf("cannot read feature %d", zone.FID)
+		}
+		for _, f := range fields {
+			switch f.stat {
+			case StatCount:
+				feature.SetFieldInteger64(f.index, int64(zone.Count))
+			case StatHistogram:
+				feature.SetFieldInteger64(f.index, int64(zone.Histogram[f.class]))
+			default:
+				if zone.Count == 0 {
+					feature.SetFieldNull(f.index)
+					continue
+				}
+				feature.SetFieldFloat64(f.index, map[Stat]float64{
+					StatSum:      zone.Sum,
+					StatMean:     zone.Mean,
+					StatMin:      zone.Min,
+					StatMax:      zone.Max,
+					StatStd:      zone.Std,
+					StatMajority: zone.Majority,
+				}[f.stat])
+			}
+		}
+		err := layer.SetFeature(feature)
+		feature.Destroy()
+		if err != nil {
+			return fmt.Errorf("cannot write feature %d: %w", zone.FID, err)
+		}
+	}
+	return nil
+}
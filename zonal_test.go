@@ -0,0 +1,260 @@
+package gdal
+
+import (
+	"math"
+	"testing"
+
+	"github.com/airmap/gdal/ogr"
+)
+
+func TestZonalStats(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raster := driver.Create("", 4, 4, 1, Byte, nil)
+	defer raster.Close()
+	if err := raster.SetGeoTransform(GeoTransform{0, 1, 0, 4, 0, -1}); err != nil {
+		t.Fatal(err)
+	}
+	band := raster.RasterBand(1)
+	if err := band.SetNoDataValue(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteWindow(band, Window{XSize: 4, YSize: 4}, []uint8{
+		1, 1, 2, 2,
+		1, 1, 3, 3,
+		1, 5, 0, 0,
+		1, 1, 0, 0,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	vectorDriver, err := GetDriverByName("Memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vector := vectorDriver.Create("", 0, 0, 0, Unknown, nil)
+	defer vector.Close()
+	layer, err := vector.CreateLayer("zones", ogr.SpatialReference{}, ogr.GT_Polygon, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, wkt := range []string{
+		"POLYGON ((0 0,2 0,2 4,0 4,0 0))",
+		"POLYGON ((2 2,4 2,4 4,2 4,2 2))",
+		"POLYGON ((2 0,4 0,4 2,2 2,2 0))",
+		// Inside the top left pixel, without covering its center.
+		"POLYGON ((0.1 3.9,0.3 3.9,0.3 3.7,0.1 3.9))",
+	} {
+		geom, err := ogr.CreateFromWKT(wkt, ogr.SpatialReference{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		feature := layer.Definition().Create()
+		if err := feature.SetGeometry(geom); err != nil {
+			t.Fatal(err)
+		}
+		if err := layer.Create(feature); err != nil {
+			t.Fatal(err)
+		}
+		feature.Destroy()
+		geom.Destroy()
+	}
+
+	stats := []Stat{StatCount, StatSum, StatMean, StatMin, StatMax, StatStd, StatMajority, StatHistogram}
+	results, err := ZonalStats(band, layer, stats, ZonalStatsOptions{WriteFields: true, FieldPrefix: "z_"})
+	if err != nil {
+		t.Fatalf("ZonalStats: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("%d results, want 4", len(results))
+	}
+	for i, want := range []ZoneStats{
+		{Count: 8, Sum: 12, Mean: 1.5, Min: 1, Max: 5, Std: math.Sqrt(1.75), Majority: 1, Histogram: map[float64]int{1: 7, 5: 1}},
+		{Count: 4, Sum: 10, Mean: 2.5, Min: 2, Max: 3, Std: 0.5, Majority: 2, Histogram: map[float64]int{2: 2, 3: 2}},
+		{},
+		{},
+	} {
+		got := results[i]
+		if got.Count != want.Count || got.Sum != want.Sum || got.Mean != want.Mean ||
+			got.Min != want.Min || got.Max != want.Max || math.Abs(got.Std-want.Std) > 1e-9 ||
+			got.Majority != want.Majority || len(got.Histogram) != len(want.Histogram) {
+			t.Errorf("zone %d = %+v, want %+v", i, got, want)
+			continue
+		}
+		for v, count := range want.Histogram {
+			if got.Histogram[v] != count {
+				t.Errorf("zone %d histogram = %v, want %v", i, got.Histogram, want.Histogram)
+			}
+		}
+	}
+
+	feature := layer.Feature(results[0].FID)
+	for name, want := range map[string]float64{"z_count": 8, "z_mean": 1.5, "z_majority": 1, "z_hist_1": 7, "z_hist_2": 0, "z_hist_5": 1} {
+		index := feature.FieldIndex(name)
+		if index < 0 {
+			t.Errorf("no field %s", name)
+		} else if got := feature.FieldAsFloat64(index); got != want {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+	feature.Destroy()
+	feature = layer.Feature(results[2].FID)
+	if index := feature.FieldIndex("z_mean"); index < 0 || feature.IsFieldSetAndNotNull(index) {
+		t.Error("z_mean of a zone without valid pixels is not null")
+	}
+	feature.Destroy()
+
+	results, err = ZonalStats(band, layer, []Stat{StatCount}, ZonalStatsOptions{AllTouched: true})
+	if err != nil {
+		t.Fatalf("ZonalStats: %v", err)
+	}
+	if results[3].Count != 1 || results[3].Mean != 0 {
+		t.Errorf("all touched zone = %+v, want a count of 1 only", results[3])
+	}
+	if _, err := ZonalStats(band, layer, []Stat{Stat(42)}, ZonalStatsOptions{}); err == nil {
+		t.Error("ZonalStats with an unknown statistic succeeded")
+	}
+
+	results, err = ZonalStats(band, layer, []Stat{StatCount, StatMean}, ZonalStatsOptions{IgnoreNoData: true})
+	if err != nil {
+		t.Fatalf("ZonalStats: %v", err)
+	}
+	if results[0].Count != 8 || results[2].Count != 4 || results[2].Mean != 0 {
+		t.Errorf("IgnoreNoData zones = %+v, %+v", results[0], results[2])
+	}
+}
+
+func TestZonalStatsRenamedFields(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raster := driver.Create("", 2, 2, 1, Byte, nil)
+	defer raster.Close()
+	if err := raster.SetGeoTransform(GeoTransform{0, 1, 0, 2, 0, -1}); err != nil {
+		t.Fatal(err)
+	}
+	band := raster.RasterBand(1)
+	if err := WriteWindow(band, Window{XSize: 2, YSize: 2}, []uint8{1, 2, 3, 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	shapefile, err := GetDriverByName("ESRI Shapefile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const dir = "/vsimem/zonal_test"
+	defer removeVSIMemDir(dir)
+	vector := shapefile.Create(dir, 0, 0, 0, Unknown, nil)
+	defer vector.Close()
+	layer, err := vector.CreateLayer("zones", ogr.SpatialReference{}, ogr.GT_Polygon, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	geom, err := ogr.CreateFromWKT("POLYGON ((0 0,2 0,2 2,0 2,0 0))", ogr.SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer geom.Destroy()
+	feature := layer.Definition().Create()
+	defer feature.Destroy()
+	if err := feature.SetGeometry(geom); err != nil {
+		t.Fatal(err)
+	}
+	if err := layer.Create(feature); err != nil {
+		t.Fatal(err)
+	}
+
+	// Shapefile field names are truncated to 10 characters, and renamed
+	// when the truncated names collide.
+	stats := []Stat{StatCount, StatMean, StatMajority}
+	results, err := ZonalStats(band, layer, stats, ZonalStatsOptions{WriteFields: true, FieldPrefix: "elevation_"})
+	if err != nil {
+		t.Fatalf("ZonalStats: %v", err)
+	}
+	if n := layer.Definition().FieldCount(); n != 3 {
+		t.Fatalf("%d fields, want 3", n)
+	}
+	written := layer.Feature(results[0].FID)
+	defer written.Destroy()
+	for i, want := range []float64{4, 2.5, 1} {
+		if got := written.FieldAsFloat64(i); got != want {
+			t.Errorf("field %d = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestZonalStatsReprojection(t *testing.T) {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A raster of 1 km pixels in web mercator, from (0, 0) to (4000, 4000).
+	raster := driver.Create("", 4, 4, 1, Byte, nil)
+	defer raster.Close()
+	if err := raster.SetGeoTransform(GeoTransform{0, 1000, 0, 4000, 0, -1000}); err != nil {
+		t.Fatal(err)
+	}
+	mercator := ogr.CreateSpatialReference("")
+	defer mercator.Destroy()
+	if err := mercator.FromEPSG(3857); err != nil {
+		t.Fatal(err)
+	}
+	wkt, err := mercator.ToWKT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := raster.SetProjection(wkt); err != nil {
+		t.Fatal(err)
+	}
+	band := raster.RasterBand(1)
+	if err := WriteWindow(band, Window{XSize: 4, YSize: 4}, []uint8{
+		1, 2, 9, 9,
+		3, 4, 9, 9,
+		5, 6, 9, 9,
+		7, 8, 9, 9,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	vectorDriver, err := GetDriverByName("Memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vector := vectorDriver.Create("", 0, 0, 0, Unknown, nil)
+	defer vector.Close()
+	wgs84 := ogr.CreateSpatialReference("")
+	defer wgs84.Destroy()
+	if err := wgs84.FromEPSG(4326); err != nil {
+		t.Fatal(err)
+	}
+	layer, err := vector.CreateLayer("zones", wgs84, ogr.GT_Polygon, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The western half of the raster, in longitude and latitude: 2 km is
+	// about 0.018 degrees there.
+	geom, err := ogr.CreateFromWKT("POLYGON ((0.001 0.001,0.0175 0.001,0.0175 0.0355,0.001 0.0355,0.001 0.001))", ogr.SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer geom.Destroy()
+	feature := layer.Definition().Create()
+	defer feature.Destroy()
+	if err := feature.SetGeometry(geom); err != nil {
+		t.Fatal(err)
+	}
+	if err := layer.Create(feature); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ZonalStats(band, layer, []Stat{StatCount, StatSum, StatMax}, ZonalStatsOptions{})
+	if err != nil {
+		t.Fatalf("ZonalStats: %v", err)
+	}
+	if len(results) != 1 || results[0].Count != 8 || results[0].Sum != 36 || results[0].Max != 8 {
+		t.Errorf("got %+v, want the 8 western pixels summing to 36", results)
+	}
+}